@@ -0,0 +1,331 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/schema"
+)
+
+// A CompletionKind describes the kind of entity offered by a completion.
+type CompletionKind string
+
+const (
+	// CompletionProperty indicates a property of the environment, its imports, or its execution context.
+	CompletionProperty CompletionKind = "property"
+	// CompletionBuiltin indicates the name of a builtin function.
+	CompletionBuiltin CompletionKind = "builtin"
+	// CompletionProvider indicates the name of a provider.
+	CompletionProvider CompletionKind = "provider"
+	// CompletionInput indicates a key within the argument to a builtin function, e.g. a provider input.
+	CompletionInput CompletionKind = "input"
+)
+
+// A Completion is a candidate for the text at a position within an environment definition.
+type Completion struct {
+	// Label is the text of the completion.
+	Label string `json:"label"`
+
+	// Kind is the kind of entity offered by the completion.
+	Kind CompletionKind `json:"kind"`
+
+	// Detail is a short description of the entity, e.g. its type.
+	Detail string `json:"detail,omitempty"`
+
+	// Documentation is the documentation for the entity, e.g. the description from its schema.
+	Documentation string `json:"documentation,omitempty"`
+}
+
+// Complete returns the completions for the entity at the indicated position, sorted by label. Completions are not
+// filtered by the text that precedes the position; that is left to the caller.
+func (a *Analysis) Complete(pos esc.Pos) ([]Completion, bool) {
+	// Fetch the expression (if any) at pos.
+	x, where, ok := a.expressionAtPos(pos)
+	if !ok {
+		return nil, false
+	}
+	x, where = argumentAtPos(x, where, pos)
+
+	// The completions we return depend on the type of the expression and where the position lies inside that
+	// expression.
+	//
+	// - If the position is within a property access, we return the properties of the access's receiver
+	// - If the position is within the name of a builtin, or within a key or string that starts with `fn::`, we
+	//   return the names of the builtins
+	// - If the position is within the argument to a builtin, we return the provider names or the argument's keys
+	var completions []Completion
+	switch {
+	case x.Builtin != nil:
+		if touches(x.Builtin.NameRange, pos) {
+			completions = a.completeBuiltins()
+		}
+	case len(x.Symbol) != 0:
+		completions = a.completeAccess(x.Symbol, pos)
+	case len(x.Interpolate) != 0:
+		for _, part := range x.Interpolate {
+			if _, ok := accessorAtPos(part.Value, pos); ok {
+				completions = a.completeAccess(part.Value, pos)
+				break
+			}
+		}
+	case isBuiltinPrefix(x, pos):
+		completions = a.completeBuiltins()
+	default:
+		// Find the nearest builtin in the traversal. If a builtin is found, return the completions for the argument
+		// on the path from the builtin.
+		for i := len(where) - 1; i >= 0; i-- {
+			r := where[i].receiver
+			if r.Builtin != nil {
+				completions = a.completeArgument(r.Builtin, where[i+1:], x, pos)
+				break
+			}
+		}
+	}
+	return completions, len(completions) != 0
+}
+
+// argumentAtPos refines the result of expressionAtPos for builtins whose arguments do not have a range of their own,
+// e.g. the long forms of fn::open and fn::rotate.
+func argumentAtPos(x *esc.Expr, where []traverser, pos esc.Pos) (*esc.Expr, []traverser) {
+	if x.Builtin == nil || x.Builtin.NameRange.Contains(pos) || x.Builtin.Arg.Range != (esc.Range{}) {
+		return x, where
+	}
+
+	arg := x.Builtin.Arg
+	here := append(where, newObjectTraverser(*x, x.Builtin.Name))
+	for _, key := range slices.Sorted(maps.Keys(arg.Object)) {
+		if x, where, ok := expressionAtPos(arg.Object[key], append(here, newObjectTraverser(arg, key)), pos); ok {
+			return x, where
+		}
+	}
+	return x, where
+}
+
+// touches returns true if rng contains pos or ends at pos. The latter is where the cursor sits while the text within
+// the range is being typed.
+func touches(rng esc.Range, pos esc.Pos) bool {
+	return rng.Contains(pos) || rng.End.Line == pos.Line && rng.End.Column == pos.Column
+}
+
+// accessorAtPos returns the index of the accessor that touches pos.
+func accessorAtPos(accessors []esc.PropertyAccessor, pos esc.Pos) (int, bool) {
+	for i, accessor := range accessors {
+		if touches(accessor.Range, pos) {
+			return i, true
+		}
+	}
+
+	// The accessors of an unterminated interpolation do not have ranges. In that case, the position is assumed to be
+	// within the last accessor.
+	if n := len(accessors); n != 0 && accessors[n-1].Range == (esc.Range{}) {
+		return n - 1, true
+	}
+	return 0, false
+}
+
+// isBuiltinPrefix returns true if the key at pos or the string literal x starts with `fn::`.
+func isBuiltinPrefix(x *esc.Expr, pos esc.Pos) bool {
+	for k, rng := range x.KeyRanges {
+		if touches(rng, pos) {
+			return strings.HasPrefix(k, "fn::")
+		}
+	}
+	s, ok := x.Literal.(string)
+	return ok && strings.HasPrefix(s, "fn::")
+}
+
+// completeAccess returns the completions for the accessor at pos, which are the properties of the value accessed by
+// the preceding accessors.
+func (a *Analysis) completeAccess(accessors []esc.PropertyAccessor, pos esc.Pos) []Completion {
+	i, ok := accessorAtPos(accessors, pos)
+	if !ok {
+		return nil
+	}
+	if i == 0 {
+		return a.completeRoot()
+	}
+
+	receiver, s := accessors[:i], a.env.Schema
+	if root := accessors[0].Key; root != nil {
+		switch *root {
+		case "imports":
+			if i == 1 {
+				return a.completeImports()
+			}
+			return nil
+		case "context":
+			if a.env.ExecutionContext == nil {
+				return nil
+			}
+			receiver, s = receiver[1:], a.env.ExecutionContext.Schema
+		}
+	}
+	if s == nil {
+		return nil
+	}
+
+	for _, accessor := range receiver {
+		switch {
+		case accessor.Key != nil:
+			s = s.Property(*accessor.Key)
+		case accessor.Index != nil:
+			s = s.Item(*accessor.Index)
+		}
+	}
+	return propertyCompletions(s, CompletionProperty, nil)
+}
+
+// completeRoot returns the completions for the first accessor of a property access: the top-level properties of the
+// environment, including those of its merged imports, and the `context` and `imports` roots.
+func (a *Analysis) completeRoot() []Completion {
+	completions := propertyCompletions(a.env.Schema, CompletionProperty, nil)
+	completions = append(completions,
+		Completion{
+			Label:         "context",
+			Kind:          CompletionProperty,
+			Detail:        "object",
+			Documentation: "The execution context of the environment.",
+		},
+		Completion{
+			Label:         "imports",
+			Kind:          CompletionProperty,
+			Detail:        "object",
+			Documentation: "The environments imported by the environment.",
+		},
+	)
+	sortCompletions(completions)
+	return completions
+}
+
+// completeImports returns the names of the environments imported by the environment. Environments that were skipped
+// are not included.
+func (a *Analysis) completeImports() []Completion {
+	// Each expression's range records the name of the environment that defined it.
+	self := ""
+	for _, x := range a.env.Exprs {
+		self = x.Range.Environment
+		break
+	}
+
+	var completions []Completion
+	for _, imp := range a.env.Imports {
+		if imp.Importer == self && !imp.Skipped {
+			completions = append(completions, Completion{Label: imp.Environment, Kind: CompletionProperty, Detail: "object"})
+		}
+	}
+	sortCompletions(completions)
+	return completions
+}
+
+// completeBuiltins returns the names of the builtin functions, including the short forms of fn::open for each
+// provider.
+func (a *Analysis) completeBuiltins() []Completion {
+	completions := make([]Completion, 0, len(builtinDocs)+len(a.providers))
+	for name, doc := range builtinDocs {
+		completions = append(completions, Completion{Label: name, Kind: CompletionBuiltin, Documentation: doc})
+	}
+	for name := range a.providers {
+		completions = append(completions, Completion{
+			Label:         "fn::open::" + name,
+			Kind:          CompletionBuiltin,
+			Documentation: builtinDocs["fn::open"],
+		})
+	}
+	sortCompletions(completions)
+	return completions
+}
+
+// completeArgument returns the completions for x, which is at the end of the given path from the argument to the
+// given builtin.
+func (a *Analysis) completeArgument(builtin *esc.BuiltinExpr, path []traverser, x *esc.Expr, pos esc.Pos) []Completion {
+	if builtin.Name == "fn::open" && len(path) == 1 && path[0].key == "provider" {
+		return a.completeProviders()
+	}
+
+	s := builtin.ArgSchema
+	for _, t := range path {
+		if t.key != "" {
+			s = s.Property(t.key)
+		} else {
+			s = s.Item(t.index)
+		}
+	}
+
+	// If x is an object, offer the keys that are not yet present. If x is a string, offer the keys of the object it
+	// is meant to be, e.g. while a key is being typed on a new line.
+	switch {
+	case x.Object != nil:
+		present := make(map[string]bool, len(x.Object))
+		for k, rng := range x.KeyRanges {
+			present[k] = !touches(rng, pos)
+		}
+		return propertyCompletions(s, CompletionInput, present)
+	case isString(x):
+		return propertyCompletions(s, CompletionInput, nil)
+	default:
+		return nil
+	}
+}
+
+// completeProviders returns the names of the providers known to the analysis.
+func (a *Analysis) completeProviders() []Completion {
+	completions := make([]Completion, 0, len(a.providers))
+	for _, name := range slices.Sorted(maps.Keys(a.providers)) {
+		completions = append(completions, Completion{
+			Label:         name,
+			Kind:          CompletionProvider,
+			Documentation: a.providers[name].Description,
+		})
+	}
+	return completions
+}
+
+func isString(x *esc.Expr) bool {
+	_, ok := x.Literal.(string)
+	return ok
+}
+
+// propertyCompletions returns a completion for each property of s whose name is not excluded.
+func propertyCompletions(s *schema.Schema, kind CompletionKind, exclude map[string]bool) []Completion {
+	if s == nil {
+		return nil
+	}
+
+	var completions []Completion
+	for _, k := range slices.Sorted(maps.Keys(s.Properties)) {
+		if exclude[k] {
+			continue
+		}
+		p := s.Properties[k]
+		completions = append(completions, Completion{
+			Label:         k,
+			Kind:          kind,
+			Detail:        schemaType(p),
+			Documentation: p.Description,
+		})
+	}
+	return completions
+}
+
+func sortCompletions(completions []Completion) {
+	sort.Slice(completions, func(i, j int) bool {
+		return completions[i].Label < completions[j].Label
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/eval"
+	"github.com/pulumi/esc/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// completeDef is an environment definition that is being edited. It is not expected to check without errors.
+const completeDef = `imports:
+  - a
+values:
+  app:
+    name: web
+    port: 8080
+  open:
+    fn::open::test:
+      address: some-url
+      jwt:
+        mou
+  open2:
+    fn::open:
+      provider: test
+      inputs:
+        address: some-url
+  ref: ${app.}
+  interp: hello, ${app.name}
+  ctx: ${context.pulumi.}
+  partial: fn::to
+  imp: ${imports.}
+`
+
+func TestComplete(t *testing.T) {
+	syntax, _, err := eval.LoadYAMLBytes("def", []byte(completeDef))
+	require.NoError(t, err)
+
+	execContext, err := esc.NewExecContext(map[string]esc.Value{
+		"pulumi": esc.NewValue(map[string]esc.Value{"user": esc.NewValue("me")}),
+	})
+	require.NoError(t, err)
+
+	env, _ := eval.CheckEnvironment(context.Background(), "def", syntax, nil, testProviders{}, testEnvironments{}, execContext, false)
+	require.NotNil(t, env)
+
+	analysis := New(*env, map[string]*schema.Schema{"test": testProviderSchema})
+
+	builtins := []string{
+		"fn::concat",
+		"fn::final",
+		"fn::fromBase64",
+		"fn::fromJSON",
+		"fn::join",
+		"fn::open",
+		"fn::open::test",
+		"fn::rotate",
+		"fn::secret",
+		"fn::split",
+		"fn::toBase64",
+		"fn::toJSON",
+		"fn::toString",
+		"fn::validate",
+	}
+	properties := []string{"app", "context", "ctx", "imp", "imports", "interp", "open", "open2", "partial", "ref"}
+
+	expected := map[esc.Pos][]string{
+		{Line: 8, Column: 5}:   builtins,
+		{Line: 9, Column: 7}:   {"address", "token"},
+		{Line: 10, Column: 7}:  {"jwt", "token"},
+		{Line: 11, Column: 9}:  {"mount", "role"},
+		{Line: 11, Column: 11}: {"mount", "role"},
+		{Line: 13, Column: 5}:  builtins,
+		{Line: 14, Column: 17}: {"test"},
+		{Line: 16, Column: 9}:  {"address", "jwt", "token"},
+		{Line: 17, Column: 10}: properties,
+		{Line: 17, Column: 13}: properties,
+		{Line: 17, Column: 14}: {"name", "port"},
+		{Line: 18, Column: 12}: nil,
+		{Line: 18, Column: 20}: properties,
+		{Line: 18, Column: 24}: {"name", "port"},
+		{Line: 19, Column: 17}: properties,
+		{Line: 19, Column: 18}: {"currentEnvironment", "pulumi", "rootEnvironment"},
+		{Line: 19, Column: 25}: {"user"},
+		{Line: 20, Column: 12}: builtins,
+		{Line: 21, Column: 18}: {"a"},
+		{Line: 5, Column: 11}:  nil,
+	}
+	for pos, labels := range expected {
+		t.Run(fmt.Sprintf("%v:%v", pos.Line, pos.Column), func(t *testing.T) {
+			completions, ok := analysis.Complete(pos)
+			require.Equal(t, len(labels) != 0, ok)
+
+			var actual []string
+			for _, c := range completions {
+				actual = append(actual, c.Label)
+			}
+			assert.Equal(t, labels, actual)
+		})
+	}
+
+	t.Run("details", func(t *testing.T) {
+		completions, ok := analysis.Complete(esc.Pos{Line: 11, Column: 9})
+		require.True(t, ok)
+		assert.Equal(t, []Completion{
+			{
+				Label:         "mount",
+				Kind:          CompletionInput,
+				Detail:        "string",
+				Documentation: "The name of the authentication engine mount.",
+			},
+			{
+				Label:         "role",
+				Kind:          CompletionInput,
+				Detail:        "string",
+				Documentation: "The name of the role to use for login.",
+			},
+		}, completions)
+
+		completions, ok = analysis.Complete(esc.Pos{Line: 17, Column: 14})
+		require.True(t, ok)
+		assert.Equal(t, []Completion{
+			{Label: "name", Kind: CompletionProperty, Detail: "string"},
+			{Label: "port", Kind: CompletionProperty, Detail: "number"},
+		}, completions)
+
+		completions, ok = analysis.Complete(esc.Pos{Line: 14, Column: 17})
+		require.True(t, ok)
+		assert.Equal(t, []Completion{{Label: "test", Kind: CompletionProvider}}, completions)
+
+		completions, ok = analysis.Complete(esc.Pos{Line: 20, Column: 12})
+		require.True(t, ok)
+		assert.Equal(t, Completion{
+			Label:         "fn::toBase64",
+			Kind:          CompletionBuiltin,
+			Documentation: "Encodes a string into its Base64 representation.",
+		}, completions[10])
+	})
+
+	t.Run("none", func(t *testing.T) {
+		actual, ok := analysis.Complete(esc.Pos{})
+		require.False(t, ok)
+		assert.Empty(t, actual)
+	})
+}
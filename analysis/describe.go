@@ -18,6 +18,7 @@ import (
 	"strings"
 
 	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/schema"
 )
 
 // Describe returns a Markdown-formatted description of the entity at the indicated position.
@@ -52,40 +53,50 @@ func (a *Analysis) Describe(pos esc.Pos) (string, bool) {
 	return "", false
 }
 
+// builtinDocs maps the name of each builtin function to its documentation.
+var builtinDocs = map[string]string{
+	"fn::concat":     "Concatenates a list of lists into a single list.",
+	"fn::final":      "Marks a value as final. Final values cannot be overridden in child environments.",
+	"fn::fromJSON":   "Decodes a value from its JSON representation.",
+	"fn::fromBase64": "Decodes a string from its Base64 representation.",
+	"fn::join": "Concatenates the elements of its second argument to create a single string. The first argument is " +
+		"placed between each element in the result.",
+	"fn::open":     "Fetches values from an external source when the environment is opened.",
+	"fn::rotate":   "Rotates a secret in an external source.",
+	"fn::secret":   "Marks a value as secret.",
+	"fn::split":    "Splits its second argument into a list of strings. The first argument is the delimiter.",
+	"fn::toBase64": "Encodes a string into its Base64 representation.",
+	"fn::toJSON":   "Encodes a value into its JSON representation.",
+	"fn::toString": "Encodes a value into its string representation.",
+	"fn::validate": "Validates a value against a JSON schema.",
+}
+
 func (a *Analysis) describeBuiltin(builtin *esc.BuiltinExpr) (string, bool) {
-	switch builtin.Name {
-	case "fn::final":
-		return "Marks a value as final. Final values cannot be overridden in child environments.", true
-	case "fn::fromJSON":
-		return "Decodes a value from its JSON representation.", true
-	case "fn::fromBase64":
-		return "Decodes a string from its Base64 representation.", true
-	case "fn::join":
-		return "Concatenates the elements of its second argument to create a single string. The first argument is " +
-			"placed between each element in the result.", true
-	case "fn::open":
-		return "Fetches values from an external source when the environment is opened.", true
-	case "fn::secret":
-		return "Marks a value as secret.", true
-	case "fn::toBase64":
-		return "Encodes a string into its Base64 representation.", true
-	case "fn::toJSON":
-		return "Encodes a value into its JSON representation.", true
-	case "fn::toString":
-		return "Encodes a value into its string representation.", true
-	default:
-		if strings.HasPrefix(builtin.Name, "fn::open::") {
-			return "Fetches values from an external source when the environment is opened.", true
-		}
-		return "", false
+	name := builtin.Name
+	switch {
+	case strings.HasPrefix(name, "fn::open::"):
+		name = "fn::open"
+	case strings.HasPrefix(name, "fn::rotate::"):
+		name = "fn::rotate"
 	}
+	doc, ok := builtinDocs[name]
+	return doc, ok
 }
 
 func (a *Analysis) describeSymbol(x *esc.Expr) (string, bool) {
-	if x.Schema.Always {
-		return "any", true
+	typ := schemaType(x.Schema)
+	return typ, typ != ""
+}
+
+// schemaType returns the name of the type described by s, or "any" if s accepts any value.
+func schemaType(s *schema.Schema) string {
+	if s == nil {
+		return ""
+	}
+	if s.Always {
+		return "any"
 	}
-	return x.Schema.Type, x.Schema.Type != ""
+	return s.Type
 }
 
 func (a *Analysis) describeArgument(builtin *esc.BuiltinExpr, path []traverser, x *esc.Expr, pos esc.Pos) (string, bool) {
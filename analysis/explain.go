@@ -0,0 +1,284 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// A Relation describes how a layer of a value's provenance chain combines with the layer beneath it.
+type Relation string
+
+const (
+	// RelationOverrides indicates that a layer replaces the layer beneath it.
+	RelationOverrides Relation = "overrides"
+	// RelationMerges indicates that a layer is an object that is merged with the object beneath it.
+	RelationMerges Relation = "merges"
+)
+
+// A SecretSource describes why a value is secret.
+type SecretSource string
+
+const (
+	// SecretDeclared indicates that a value was explicitly marked as secret with fn::secret.
+	SecretDeclared SecretSource = "declared"
+	// SecretProvider indicates that a value was marked as secret by the provider or rotator that produced it.
+	SecretProvider SecretSource = "provider"
+	// SecretInherited indicates that a value is secret because it was computed from a secret value.
+	SecretInherited SecretSource = "inherited"
+)
+
+// A Reference is a reference to another value made by the expression that defined a layer.
+type Reference struct {
+	// Path is the path of the referenced value, e.g. `aws.creds.accessKeyId`.
+	Path string `json:"path"`
+
+	// Range is the range of the expression that defines the referenced value.
+	Range esc.Range `json:"range"`
+}
+
+// A Layer is a single layer of a value's provenance chain.
+type Layer struct {
+	// Range is the range of the expression that defined the layer.
+	Range esc.Range `json:"range"`
+
+	// Relation describes how the layer combines with the next layer in the chain, if any.
+	Relation Relation `json:"relation,omitempty"`
+
+	// Builtin is the name of the builtin function that produced the layer, if any.
+	Builtin string `json:"builtin,omitempty"`
+
+	// Provider is the name of the provider or rotator that produced the layer, if any.
+	Provider string `json:"provider,omitempty"`
+
+	// Description is the description of the builtin that produced the layer, as returned by Describe. Only layers
+	// defined by the environment itself are described.
+	Description string `json:"description,omitempty"`
+
+	// References holds the references made by the expression that defined the layer.
+	References []Reference `json:"references,omitempty"`
+
+	// Secret is true if the layer's value is secret.
+	Secret bool `json:"secret,omitempty"`
+
+	// SecretSource describes why the layer's value is secret, if it is secret.
+	SecretSource SecretSource `json:"secretSource,omitempty"`
+}
+
+// An Explanation describes the provenance of a value within an environment.
+type Explanation struct {
+	// Value is the value being explained.
+	Value esc.Value `json:"value"`
+
+	// Layers holds the provenance chain of the value. The first layer is the layer that is visible in the
+	// environment. Each subsequent layer is the base of the layer that precedes it, e.g. the definition of the value
+	// within an imported environment.
+	Layers []Layer `json:"layers"`
+}
+
+// Explain returns the provenance chain of the value at the given path. The chain is built from the value's trace and
+// from the expressions that defined each layer of the value. Layers defined by expressions that are not present in
+// the environment, e.g. properties that are only defined by an imported environment, only carry their range and
+// secretness.
+func (a *Analysis) Explain(path resource.PropertyPath) (*Explanation, bool) {
+	v, ok := valueAtPath(esc.NewValue(a.env.Properties), path)
+	if !ok {
+		return nil, false
+	}
+
+	var layers []Layer
+	for layer := v; layer != nil; layer = layer.Trace.Base {
+		l := Layer{Range: layer.Trace.Def, Secret: layer.Secret}
+		if base := layer.Trace.Base; base != nil {
+			l.Relation = RelationOverrides
+			if _, ok := layer.Value.(map[string]esc.Value); ok {
+				if _, ok := base.Value.(map[string]esc.Value); ok {
+					l.Relation = RelationMerges
+				}
+			}
+		}
+
+		x, hasExpr := a.expressionAtRange(layer.Trace.Def)
+		if hasExpr {
+			if x.Builtin != nil {
+				l.Builtin, l.Provider = x.Builtin.Name, builtinProvider(x.Builtin)
+				l.Description = a.describeRange(x.Range)
+			}
+			l.References = references(*x, nil)
+		}
+
+		if l.Secret {
+			switch {
+			case l.Builtin == "fn::secret":
+				l.SecretSource = SecretDeclared
+			case l.Provider != "":
+				l.SecretSource = SecretProvider
+			case hasExpr:
+				l.SecretSource = SecretInherited
+			}
+		}
+
+		layers = append(layers, l)
+	}
+
+	return &Explanation{Value: *v, Layers: layers}, true
+}
+
+// builtinProvider returns the name of the provider or rotator called by an fn::open or fn::rotate builtin, if any.
+func builtinProvider(builtin *esc.BuiltinExpr) string {
+	for _, prefix := range []string{"fn::open", "fn::rotate"} {
+		switch {
+		case builtin.Name == prefix:
+			if provider, ok := builtin.Arg.Object["provider"].Literal.(string); ok {
+				return provider
+			}
+			return ""
+		case strings.HasPrefix(builtin.Name, prefix+"::"):
+			return strings.TrimPrefix(builtin.Name, prefix+"::")
+		}
+	}
+	return ""
+}
+
+// describeRange returns the description of the expression with the given range, if that expression is defined by the
+// environment itself. Expressions defined by imported environments cannot be located by position.
+func (a *Analysis) describeRange(rng esc.Range) string {
+	if x, ok := a.ExpressionAtPos(rng.Begin); !ok || x.Range != rng {
+		return ""
+	}
+	description, _ := a.Describe(rng.Begin)
+	return description
+}
+
+// expressionAtRange returns the expression with the given range. The environment's expressions and their bases are
+// searched.
+func (a *Analysis) expressionAtRange(rng esc.Range) (*esc.Expr, bool) {
+	for _, key := range slices.Sorted(maps.Keys(a.env.Exprs)) {
+		if x, ok := expressionAtRange(a.env.Exprs[key], rng); ok {
+			return x, true
+		}
+	}
+	return nil, false
+}
+
+func expressionAtRange(root esc.Expr, rng esc.Range) (*esc.Expr, bool) {
+	if root.Range == rng {
+		return &root, true
+	}
+
+	switch {
+	case len(root.List) != 0:
+		for _, element := range root.List {
+			if x, ok := expressionAtRange(element, rng); ok {
+				return x, true
+			}
+		}
+	case len(root.Object) != 0:
+		for _, key := range slices.Sorted(maps.Keys(root.Object)) {
+			if x, ok := expressionAtRange(root.Object[key], rng); ok {
+				return x, true
+			}
+		}
+	case root.Builtin != nil:
+		// Some builtins (e.g. fn::secret) produce values that are defined by their argument. Attribute such values
+		// to the builtin itself.
+		if root.Builtin.Arg.Range == rng {
+			return &root, true
+		}
+		if x, ok := expressionAtRange(root.Builtin.Arg, rng); ok {
+			return x, true
+		}
+	}
+
+	if root.Base != nil {
+		return expressionAtRange(*root.Base, rng)
+	}
+	return nil, false
+}
+
+// references returns the references made by the given expression and its subexpressions.
+func references(x esc.Expr, refs []Reference) []Reference {
+	switch {
+	case len(x.Symbol) != 0:
+		refs = append(refs, newReference(x.Symbol))
+	case len(x.Interpolate) != 0:
+		for _, i := range x.Interpolate {
+			if len(i.Value) != 0 {
+				refs = append(refs, newReference(i.Value))
+			}
+		}
+	case len(x.List) != 0:
+		for _, element := range x.List {
+			refs = references(element, refs)
+		}
+	case len(x.Object) != 0:
+		for _, key := range slices.Sorted(maps.Keys(x.Object)) {
+			refs = references(x.Object[key], refs)
+		}
+	case x.Builtin != nil:
+		refs = references(x.Builtin.Arg, refs)
+	}
+	return refs
+}
+
+func newReference(accessors []esc.PropertyAccessor) Reference {
+	var path strings.Builder
+	for i, a := range accessors {
+		switch {
+		case a.Index != nil:
+			fmt.Fprintf(&path, "[%d]", *a.Index)
+		case a.Key != nil:
+			if i != 0 {
+				path.WriteByte('.')
+			}
+			path.WriteString(*a.Key)
+		}
+	}
+	return Reference{Path: path.String(), Range: accessors[len(accessors)-1].Value}
+}
+
+// valueAtPath returns the value at the given path, if any.
+func valueAtPath(root esc.Value, path resource.PropertyPath) (*esc.Value, bool) {
+	v := &root
+	for _, k := range path {
+		switch repr := v.Value.(type) {
+		case []esc.Value:
+			index, ok := k.(int)
+			if !ok || index < 0 || index >= len(repr) {
+				return nil, false
+			}
+			v = &repr[index]
+		case map[string]esc.Value:
+			key, ok := k.(string)
+			if !ok {
+				return nil, false
+			}
+			e, ok := repr[key]
+			if !ok {
+				return nil, false
+			}
+			v = &e
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
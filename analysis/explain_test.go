@@ -0,0 +1,137 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/eval"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type explainEnvironments map[string]string
+
+func (e explainEnvironments) LoadEnvironment(ctx context.Context, name string) ([]byte, eval.Decrypter, error) {
+	def, ok := e[name]
+	if !ok {
+		return nil, nil, errors.New("not found")
+	}
+	return []byte(def), nil, nil
+}
+
+func TestExplain(t *testing.T) {
+	environments := explainEnvironments{
+		"base": `values:
+  region: us-east-1
+  db:
+    host: db.example.com
+    password:
+      fn::secret: hunter2
+`,
+	}
+
+	const app = `imports:
+  - base
+values:
+  region: us-west-2
+  db:
+    port: 5432
+  open:
+    fn::open::test:
+      address: some-url
+  conn: ${db.host}:${db.password}
+`
+
+	syntax, diags, err := eval.LoadYAMLBytes("app", []byte(app))
+	require.NoError(t, err)
+	require.Empty(t, diags)
+
+	execContext, err := esc.NewExecContext(make(map[string]esc.Value))
+	require.NoError(t, err)
+
+	env, diags := eval.EvalEnvironment(context.Background(), "app", syntax, nil, testProviders{}, environments, execContext)
+	require.Empty(t, diags)
+
+	analysis := New(*env, nil)
+
+	explain := func(t *testing.T, path string) *Explanation {
+		p, err := resource.ParsePropertyPath(path)
+		require.NoError(t, err)
+		explanation, ok := analysis.Explain(p)
+		require.True(t, ok)
+		return explanation
+	}
+
+	t.Run("override", func(t *testing.T) {
+		explanation := explain(t, "region")
+		assert.Equal(t, "us-west-2", explanation.Value.Value)
+		require.Len(t, explanation.Layers, 2)
+		assert.Equal(t, "app", explanation.Layers[0].Range.Environment)
+		assert.Equal(t, RelationOverrides, explanation.Layers[0].Relation)
+		assert.Equal(t, "base", explanation.Layers[1].Range.Environment)
+		assert.Equal(t, Relation(""), explanation.Layers[1].Relation)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		explanation := explain(t, "db")
+		require.Len(t, explanation.Layers, 2)
+		assert.Equal(t, RelationMerges, explanation.Layers[0].Relation)
+	})
+
+	t.Run("declared secret", func(t *testing.T) {
+		explanation := explain(t, "db.password")
+		require.Len(t, explanation.Layers, 1)
+		layer := explanation.Layers[0]
+		assert.Equal(t, "base", layer.Range.Environment)
+		assert.Equal(t, "fn::secret", layer.Builtin)
+		assert.True(t, layer.Secret)
+		assert.Equal(t, SecretDeclared, layer.SecretSource)
+
+		// Builtins in imported environments are not described.
+		assert.Empty(t, layer.Description)
+	})
+
+	t.Run("inherited secret", func(t *testing.T) {
+		explanation := explain(t, "conn")
+		require.Len(t, explanation.Layers, 1)
+		layer := explanation.Layers[0]
+		assert.True(t, layer.Secret)
+		assert.Equal(t, SecretInherited, layer.SecretSource)
+		require.Len(t, layer.References, 2)
+		assert.Equal(t, "db.host", layer.References[0].Path)
+		assert.Equal(t, "db.password", layer.References[1].Path)
+		assert.Equal(t, "base", layer.References[1].Range.Environment)
+	})
+
+	t.Run("provider", func(t *testing.T) {
+		explanation := explain(t, "open.address")
+		require.Len(t, explanation.Layers, 1)
+		layer := explanation.Layers[0]
+		assert.Equal(t, "fn::open::test", layer.Builtin)
+		assert.Equal(t, "test", layer.Provider)
+		assert.Equal(t, "Fetches values from an external source when the environment is opened.", layer.Description)
+		assert.False(t, layer.Secret)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, ok := analysis.Explain(resource.PropertyPath{"nope"})
+		assert.False(t, ok)
+	})
+}
@@ -72,7 +72,7 @@ func (d *ArrayDecl[T]) GetElements() []T {
 func (d *ArrayDecl[T]) parse(name string, node syntax.Node) syntax.Diagnostics {
 	list, ok := node.(*syntax.ArrayNode)
 	if !ok {
-		return syntax.Diagnostics{syntax.NodeError(node, fmt.Sprintf("%v must be a list", name))}
+		return syntax.Diagnostics{syntax.NodeError(node, fmt.Sprintf("%v must be a list", name)).WithCode(syntax.CodeInvalidType)}
 	}
 
 	var diags syntax.Diagnostics
@@ -117,7 +117,7 @@ func (d *MapDecl[T]) parse(name string, node syntax.Node) syntax.Diagnostics {
 
 	obj, ok := node.(*syntax.ObjectNode)
 	if !ok {
-		return syntax.Diagnostics{syntax.NodeError(node, fmt.Sprintf("%v must be an object", name))}
+		return syntax.Diagnostics{syntax.NodeError(node, fmt.Sprintf("%v must be an object", name)).WithCode(syntax.CodeInvalidType)}
 	}
 
 	var diags syntax.Diagnostics
@@ -129,7 +129,7 @@ func (d *MapDecl[T]) parse(name string, node syntax.Node) syntax.Diagnostics {
 		var v T
 		vname := name + "." + kvp.Key.Value()
 		if strings.HasPrefix(kvp.Key.Value(), "fn::") {
-			diags.Extend(syntax.NodeError(kvp.Key, fmt.Sprintf("builtin function call %q not allowed at the top level", kvp.Key.Value())))
+			diags.Extend(syntax.NodeError(kvp.Key, fmt.Sprintf("builtin function call %q not allowed at the top level", kvp.Key.Value())).WithCode(syntax.CodeTopLevelBuiltin))
 		}
 		vdiags := parseNode(vname, &v, kvp.Value)
 		diags.Extend(vdiags...)
@@ -146,16 +146,117 @@ func (d *MapDecl[T]) parse(name string, node syntax.Node) syntax.Diagnostics {
 
 }
 
+// An ImportMetaDecl holds the metadata associated with an import.
 type ImportMetaDecl struct {
 	declNode
 
+	// Merge determines whether or not the import is merged into the importing environment. Defaults to true.
 	Merge *BooleanExpr
+
+	// Path selects a subtree of the imported environment's values (e.g. `aws.creds`). If Path is absent, the entire
+	// environment is imported.
+	Path *StringExpr
+
+	// As mounts the imported values under the given top-level key instead of merging them into the root.
+	As *StringExpr
+
+	// Optional determines whether or not a missing environment is skipped rather than reported as an error.
+	Optional *BooleanExpr
+
+	// When holds the condition under which the environment is imported. If When is absent, the environment is always
+	// imported.
+	When *ImportConditionDecl
+
+	path *PropertyAccess
 }
 
 func (d *ImportMetaDecl) recordSyntax() *syntax.Node {
 	return &d.syntax
 }
 
+// PropertyPath returns the parsed form of the import's path, if any.
+func (d *ImportMetaDecl) PropertyPath() *PropertyAccess {
+	if d == nil {
+		return nil
+	}
+	return d.path
+}
+
+func (d *ImportMetaDecl) parsePath() syntax.Diagnostics {
+	if d.Path == nil {
+		return nil
+	}
+
+	// The property access parser expects a closing brace.
+	_, rest, access, diags := parsePropertyAccess(d.Path.Syntax(), 0, d.Path.Value+"}")
+	switch {
+	case rest != "":
+		diags.Extend(ExprError(d.Path, fmt.Sprintf("invalid import path %q", d.Path.Value)).WithCode(syntax.CodeInvalidImportPath))
+	case d.Path.Value == "":
+		diags.Extend(ExprError(d.Path, "import path must not be empty").WithCode(syntax.CodeInvalidImportPath))
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	d.path = access
+	return diags
+}
+
+func (d *ImportMetaDecl) checkAlias() syntax.Diagnostics {
+	switch {
+	case d.As == nil:
+		return nil
+	case d.As.Value == "":
+		return syntax.Diagnostics{ExprError(d.As, "import alias must not be empty").WithCode(syntax.CodeInvalidImportAlias)}
+	case strings.HasPrefix(d.As.Value, "fn::"):
+		return syntax.Diagnostics{ExprError(d.As, fmt.Sprintf("import alias %q must not use the reserved prefix 'fn::'", d.As.Value)).WithCode(syntax.CodeInvalidImportAlias)}
+	case d.Merge != nil && !d.Merge.Value:
+		// Unmerged imports are only accessible via `imports`, so the alias is never used.
+		diag := ExprError(d.As, fmt.Sprintf("import alias %q has no effect because the import is not merged", d.As.Value))
+		diag.Severity = hcl.DiagWarning
+		return syntax.Diagnostics{diag.WithCode(syntax.CodeUnusedImportAlias)}
+	default:
+		return nil
+	}
+}
+
+// An ImportConditionDecl holds the condition under which an environment is imported.
+//
+// If neither Equals nor Matches is present, Value must evaluate to a boolean.
+type ImportConditionDecl struct {
+	declNode
+
+	// Value is the value to test. Value may only refer to properties of the execution context.
+	Value Expr
+
+	// Equals, if present, is the literal that Value must be equal to.
+	Equals Expr
+
+	// Matches, if present, is a regular expression that Value must match.
+	Matches *StringExpr
+}
+
+func (d *ImportConditionDecl) recordSyntax() *syntax.Node {
+	return &d.syntax
+}
+
+func (d *ImportConditionDecl) check() syntax.Diagnostics {
+	// If the condition has no syntax, it failed to parse and the error has already been reported.
+	if d == nil || d.syntax == nil {
+		return nil
+	}
+
+	var diags syntax.Diagnostics
+	if d.Value == nil {
+		diags.Extend(syntax.NodeError(d.syntax, "import condition is missing 'value'").WithCode(syntax.CodeInvalidImportCond))
+	}
+	if d.Equals != nil && d.Matches != nil {
+		diags.Extend(syntax.NodeError(d.syntax, "import condition must not specify both 'equals' and 'matches'").WithCode(syntax.CodeInvalidImportCond))
+	}
+	return diags
+}
+
 type ImportDecl struct {
 	declNode
 
@@ -172,16 +273,81 @@ func (d *ImportDecl) parse(name string, node syntax.Node) syntax.Diagnostics {
 	case *syntax.ObjectNode:
 		// single key
 		if node.Len() != 1 {
-			return syntax.Diagnostics{syntax.NodeError(node, "import must have a single key")}
+			return syntax.Diagnostics{syntax.NodeError(node, "import must have a single key").WithCode(syntax.CodeInvalidImport)}
 		}
 		kvp := node.Index(0)
 		d.Environment = StringSyntax(kvp.Key)
 
 		d.Meta = &ImportMetaDecl{}
-		return parseRecord("import", d.Meta, kvp.Value, false)
+		diags := parseRecord("import", d.Meta, kvp.Value, false)
+		diags.Extend(d.Meta.parsePath()...)
+		diags.Extend(d.Meta.checkAlias()...)
+		diags.Extend(d.Meta.When.check()...)
+		return diags
 	default:
-		return syntax.Diagnostics{syntax.NodeError(node, "import must be a string or an object")}
+		return syntax.Diagnostics{syntax.NodeError(node, "import must be a string or an object").WithCode(syntax.CodeInvalidImport)}
+	}
+}
+
+// A DiagnosticsDecl configures the diagnostics that are reported for an environment.
+type DiagnosticsDecl struct {
+	declNode
+
+	// Allow lists the codes of warnings that are not reported for the environment.
+	Allow *ArrayDecl[*StringExpr]
+}
+
+func (d *DiagnosticsDecl) recordSyntax() *syntax.Node {
+	return &d.syntax
+}
+
+// check issues warnings for codes in Allow that are not known diagnostic codes.
+func (d *DiagnosticsDecl) check() syntax.Diagnostics {
+	var diags syntax.Diagnostics
+	for _, code := range d.GetAllow() {
+		if _, ok := syntax.LookupCode(code.GetValue()); !ok && code.Syntax() != nil {
+			diag := ExprError(code, fmt.Sprintf("unknown diagnostic code %q", code.GetValue()))
+			diag.Severity = hcl.DiagWarning
+			diags.Extend(diag.WithCode(syntax.CodeUnknownDiagnosticCode))
+		}
 	}
+	return diags
+}
+
+// GetAllow returns the codes listed in Allow.
+func (d *DiagnosticsDecl) GetAllow() []*StringExpr {
+	if d == nil {
+		return nil
+	}
+	return d.Allow.GetElements()
+}
+
+// Allows returns true if the given diagnostic is a warning whose code is listed in Allow.
+func (d *DiagnosticsDecl) Allows(diag *syntax.Diagnostic) bool {
+	if d == nil || diag.Severity != hcl.DiagWarning || diag.Code == "" {
+		return false
+	}
+	for _, code := range d.GetAllow() {
+		if code.GetValue() == diag.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the diagnostics in diags that are not allowed.
+func (d *DiagnosticsDecl) Filter(diags syntax.Diagnostics) syntax.Diagnostics {
+	if d == nil {
+		return diags
+	}
+
+	var filtered syntax.Diagnostics
+	for _, diag := range diags {
+		if !d.Allows(diag) {
+			filtered = append(filtered, diag)
+		}
+	}
+	return filtered
 }
 
 type ImportListDecl = *ArrayDecl[*ImportDecl]
@@ -197,6 +363,11 @@ type EnvironmentDecl struct {
 	Description *StringExpr
 	Imports     ImportListDecl
 	Values      PropertyMapDecl
+	Diagnostics *DiagnosticsDecl
+
+	// Schema is a JSON schema that the environment's values must satisfy. The schema also applies to the values of
+	// each environment that imports this environment.
+	Schema Expr
 }
 
 func (d *EnvironmentDecl) Syntax() syntax.Node {
@@ -241,7 +412,8 @@ func ParseEnvironment(source []byte, node syntax.Node) (*EnvironmentDecl, syntax
 	environment := EnvironmentDecl{source: source}
 
 	diags := parseRecord("environment", &environment, node, true)
-	return &environment, diags
+	diags.Extend(environment.Diagnostics.check()...)
+	return &environment, environment.Diagnostics.Filter(diags)
 }
 
 var parseDeclType = reflect.TypeOf((*parseDecl)(nil)).Elem()
@@ -311,7 +483,7 @@ func parseField(name string, dest reflect.Value, node syntax.Node) syntax.Diagno
 func parseRecord(objName string, dest recordDecl, node syntax.Node, noMatchWarning bool) syntax.Diagnostics {
 	obj, ok := node.(*syntax.ObjectNode)
 	if !ok {
-		return syntax.Diagnostics{syntax.NodeError(node, fmt.Sprintf("%v must be an object", objName))}
+		return syntax.Diagnostics{syntax.NodeError(node, fmt.Sprintf("%v must be an object", objName)).WithCode(syntax.CodeInvalidType)}
 	}
 	*dest.recordSyntax() = obj
 	contract.Assertf(*dest.recordSyntax() == obj, "%s.recordSyntax took by value, so the assignment failed", objName)
@@ -330,6 +502,7 @@ func parseRecord(objName string, dest recordDecl, node syntax.Node, noMatchWarni
 		if seenKeys[key] {
 			nodeError := syntax.NodeError(kvp.Key, fmt.Sprintf("duplicate key %q", key))
 			nodeError.Severity = hcl.DiagWarning
+			nodeError.Code = syntax.CodeDuplicateKey
 			diags = append(diags, nodeError)
 		}
 		seenKeys[key] = true
@@ -358,6 +531,7 @@ func parseRecord(objName string, dest recordDecl, node syntax.Node, noMatchWarni
 			msg := formatter.Message(key, fmt.Sprintf("Field '%s'", key))
 			nodeError := syntax.NodeError(kvp.Key, msg)
 			nodeError.Severity = hcl.DiagWarning
+			nodeError.Code = syntax.CodeUnknownField
 			diags = append(diags, nodeError)
 		}
 	}
@@ -389,7 +563,7 @@ func exprFieldTypeMismatchError(name string, expected interface{}, actual Expr)
 	default:
 		typeName = fmt.Sprintf("a %T", expected)
 	}
-	return ExprError(actual, fmt.Sprintf("%v must be %v", name, typeName))
+	return ExprError(actual, fmt.Sprintf("%v must be %v", name, typeName)).WithCode(syntax.CodeInvalidType)
 }
 
 func camel(s string) string {
@@ -20,7 +20,6 @@ import (
 	"reflect"
 	"strings"
 
-	"github.com/hashicorp/hcl/v2"
 	"github.com/pulumi/esc/syntax"
 )
 
@@ -53,32 +52,33 @@ func (x *exprNode) Syntax() syntax.Node {
 	return x.syntax
 }
 
-func exprPosition(expr Expr) (*hcl.Range, string) {
+func exprPosition(expr Expr) syntax.Syntax {
 	inner := reflect.ValueOf(expr)
 	if inner.IsValid() && !inner.IsZero() {
-		if syntax := expr.Syntax(); syntax != nil {
-			return syntax.Syntax().Range(), syntax.Syntax().Path()
+		if node := expr.Syntax(); node != nil {
+			return node.Syntax()
 		}
 	}
-	return nil, ""
+	return syntax.NoSyntax
 }
 
 // ExprError creates an error-level diagnostic associated with the given expression. If the expression is non-nil and
 // has an underlying syntax node, the error will cover the underlying textual range.
 func ExprError(expr Expr, summary string) *syntax.Diagnostic {
-	rng, path := exprPosition(expr)
-	return syntax.Error(rng, summary, path)
+	s := exprPosition(expr)
+	return syntax.Error(s.Range(), summary, s.Path()).WithAliasRange(s)
 }
 
 // AccessorError creates an error-level diagnostic associated with the given expression and accessor. If the accessor
 // has range information, the error will cover its textual range. Otherwise, the error will cover the textual range of
 // the parent expression.
 func AccessorError(parent Expr, accessor PropertyAccessor, summary string) *syntax.Diagnostic {
-	rng, path := exprPosition(parent)
+	s := exprPosition(parent)
+	rng := s.Range()
 	if r := accessor.Range(); r != nil {
 		rng = r
 	}
-	return syntax.Error(rng, summary, path)
+	return syntax.Error(rng, summary, s.Path()).WithAliasRange(s)
 }
 
 // A NullExpr represents a null literal.
@@ -193,7 +193,7 @@ func InterpolateSyntax(node *syntax.StringNode) (*InterpolateExpr, syntax.Diagno
 	parts, diags := parseInterpolate(node, node.Value())
 	for _, part := range parts {
 		if part.Value != nil && len(part.Value.Accessors) == 0 {
-			diags.Extend(syntax.NodeError(node, "Property access expressions cannot be empty"))
+			diags.Extend(syntax.NodeError(node, "Property access expressions cannot be empty").WithCode(syntax.CodeInvalidAccessSyntax))
 		}
 	}
 
@@ -358,7 +358,7 @@ func ParseExpr(node syntax.Node) (Expr, syntax.Diagnostics) {
 
 			k, ok := kx.(*StringExpr)
 			if !ok {
-				diags.Extend(syntax.NodeError(kvp.Key, "object keys must be strings"))
+				diags.Extend(syntax.NodeError(kvp.Key, "object keys must be strings").WithCode(syntax.CodeNonStringKey))
 			}
 
 			v, vdiags := ParseExpr(kvp.Value)
@@ -368,7 +368,7 @@ func ParseExpr(node syntax.Node) (Expr, syntax.Diagnostics) {
 		}
 		return ObjectSyntax(node, kvps...), diags
 	default:
-		return nil, syntax.Diagnostics{syntax.NodeError(node, fmt.Sprintf("unexpected syntax node of type %T", node))}
+		return nil, syntax.Diagnostics{syntax.NodeError(node, fmt.Sprintf("unexpected syntax node of type %T", node)).WithCode(syntax.CodeInternalError)}
 	}
 }
 
@@ -723,7 +723,7 @@ func tryParseFunction(node *syntax.ObjectNode) (Expr, syntax.Diagnostics, bool)
 	if node.Len() != 1 {
 		for i := 0; i < node.Len(); i++ {
 			if k := node.Index(i).Key.Value(); strings.HasPrefix(k, "fn::") {
-				diags = append(diags, syntax.NodeError(node, fmt.Sprintf("illegal call to builtin function: %q must be the only key within its containing object", k)))
+				diags = append(diags, syntax.NodeError(node, fmt.Sprintf("illegal call to builtin function: %q must be the only key within its containing object", k)).WithCode(syntax.CodeBuiltinNotAlone))
 
 			}
 		}
@@ -732,48 +732,12 @@ func tryParseFunction(node *syntax.ObjectNode) (Expr, syntax.Diagnostics, bool)
 
 	kvp := node.Index(0)
 
-	var parse func(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, syntax.Diagnostics)
-	switch kvp.Key.Value() {
-	case "fn::concat":
-		parse = parseConcat
-	case "fn::final":
-		parse = parseFinal
-	case "fn::validate":
-		parse = parseValidate
-	case "fn::fromJSON":
-		parse = parseFromJSON
-	case "fn::fromBase64":
-		parse = parseFromBase64
-	case "fn::join":
-		parse = parseJoin
-	case "fn::open":
-		parse = parseOpen
-	case "fn::rotate":
-		parse = parseRotate
-	case "fn::secret":
-		parse = parseSecret
-	case "fn::split":
-		parse = parseSplit
-	case "fn::toBase64":
-		parse = parseToBase64
-	case "fn::toJSON":
-		parse = parseToJSON
-	case "fn::toString":
-		parse = parseToString
-	default:
-		if strings.HasPrefix(kvp.Key.Value(), "fn::open::") {
-			parse = parseShortOpen
-			break
-		}
-		if strings.HasPrefix(kvp.Key.Value(), "fn::rotate::") {
-			parse = parseShortRotate
-			break
-		}
-
+	parse, ok := lookupBuiltin(kvp.Key.Value())
+	if !ok {
 		if strings.HasPrefix(strings.ToLower(kvp.Key.Value()), "fn::") {
 			diags = append(diags, syntax.Error(kvp.Key.Syntax().Range(),
 				"'fn::' is a reserved prefix",
-				node.Syntax().Path()))
+				node.Syntax().Path()).WithCode(syntax.CodeReservedPrefix).WithAliasRange(kvp.Key.Syntax()))
 		}
 		return nil, diags, false
 	}
@@ -797,10 +761,52 @@ func tryParseFunction(node *syntax.ObjectNode) (Expr, syntax.Diagnostics, bool)
 	return expr, diags, true
 }
 
+type builtinParser func(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, syntax.Diagnostics)
+
+// lookupBuiltin returns the parser for the named builtin function, if any.
+func lookupBuiltin(name string) (builtinParser, bool) {
+	switch name {
+	case "fn::concat":
+		return parseConcat, true
+	case "fn::final":
+		return parseFinal, true
+	case "fn::validate":
+		return parseValidate, true
+	case "fn::fromJSON":
+		return parseFromJSON, true
+	case "fn::fromBase64":
+		return parseFromBase64, true
+	case "fn::join":
+		return parseJoin, true
+	case "fn::open":
+		return parseOpen, true
+	case "fn::rotate":
+		return parseRotate, true
+	case "fn::secret":
+		return parseSecret, true
+	case "fn::split":
+		return parseSplit, true
+	case "fn::toBase64":
+		return parseToBase64, true
+	case "fn::toJSON":
+		return parseToJSON, true
+	case "fn::toString":
+		return parseToString, true
+	default:
+		if strings.HasPrefix(name, "fn::open::") {
+			return parseShortOpen, true
+		}
+		if strings.HasPrefix(name, "fn::rotate::") {
+			return parseShortRotate, true
+		}
+		return nil, false
+	}
+}
+
 func parseOpen(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, syntax.Diagnostics) {
 	obj, ok := args.(*ObjectExpr)
 	if !ok {
-		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::open must be an object containing 'provider' and 'inputs'")}
+		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::open must be an object containing 'provider' and 'inputs'").WithCode(syntax.CodeInvalidBuiltinArgs)}
 		return OpenSyntax(node, name, args, nil, nil), diags
 	}
 
@@ -821,14 +827,14 @@ func parseOpen(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, synt
 	provider, ok := providerExpr.(*StringExpr)
 	if !ok {
 		if providerExpr == nil {
-			diags.Extend(ExprError(obj, "missing provider name ('provider')"))
+			diags.Extend(ExprError(obj, "missing provider name ('provider')").WithCode(syntax.CodeInvalidBuiltinArgs))
 		} else {
-			diags.Extend(ExprError(providerExpr, "provider name must be a string literal"))
+			diags.Extend(ExprError(providerExpr, "provider name must be a string literal").WithCode(syntax.CodeInvalidBuiltinArgs))
 		}
 	}
 
 	if inputs == nil {
-		diags.Extend(ExprError(obj, "missing provider inputs ('inputs')"))
+		diags.Extend(ExprError(obj, "missing provider inputs ('inputs')").WithCode(syntax.CodeInvalidBuiltinArgs))
 	}
 
 	return OpenSyntax(node, name, obj, provider, inputs), diags
@@ -838,7 +844,7 @@ func parseShortOpen(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr,
 	kvp := node.Index(0)
 	provider := StringSyntaxValue(name.Syntax().(*syntax.StringNode), strings.TrimPrefix(kvp.Key.Value(), "fn::open::"))
 	if args == nil {
-		diags := syntax.Diagnostics{ExprError(name, "missing provider inputs")}
+		diags := syntax.Diagnostics{ExprError(name, "missing provider inputs").WithCode(syntax.CodeInvalidBuiltinArgs)}
 		return OpenSyntax(node, name, args, provider, nil), diags
 	}
 
@@ -848,7 +854,7 @@ func parseShortOpen(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr,
 func parseRotate(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, syntax.Diagnostics) {
 	obj, ok := args.(*ObjectExpr)
 	if !ok {
-		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::rotate must be an object containing 'provider', 'inputs' and 'state'")}
+		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::rotate must be an object containing 'provider', 'inputs' and 'state'").WithCode(syntax.CodeInvalidBuiltinArgs)}
 		return RotateSyntax(node, name, args, nil, nil, nil), diags
 	}
 
@@ -871,20 +877,20 @@ func parseRotate(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, sy
 	provider, ok := providerExpr.(*StringExpr)
 	if !ok {
 		if providerExpr == nil {
-			diags.Extend(ExprError(obj, "missing provider name ('provider')"))
+			diags.Extend(ExprError(obj, "missing provider name ('provider')").WithCode(syntax.CodeInvalidBuiltinArgs))
 		} else {
-			diags.Extend(ExprError(providerExpr, "provider name must be a string literal"))
+			diags.Extend(ExprError(providerExpr, "provider name must be a string literal").WithCode(syntax.CodeInvalidBuiltinArgs))
 		}
 	}
 
 	if inputs == nil {
-		diags.Extend(ExprError(obj, "missing provider inputs ('inputs')"))
+		diags.Extend(ExprError(obj, "missing provider inputs ('inputs')").WithCode(syntax.CodeInvalidBuiltinArgs))
 	}
 
 	if state == nil {
 		state = Null()
 	} else if _, ok := state.(*ObjectExpr); !ok {
-		diags.Extend(ExprError(state, "rotation state must be an object literal"))
+		diags.Extend(ExprError(state, "rotation state must be an object literal").WithCode(syntax.CodeInvalidBuiltinArgs))
 	}
 
 	return RotateSyntax(node, name, obj, provider, inputs, state), diags
@@ -896,7 +902,7 @@ func parseShortRotate(node *syntax.ObjectNode, name *StringExpr, args Expr) (Exp
 
 	obj, ok := args.(*ObjectExpr)
 	if !ok {
-		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::rotate must be an object containing 'inputs' and 'state'")}
+		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::rotate must be an object containing 'inputs' and 'state'").WithCode(syntax.CodeInvalidBuiltinArgs)}
 		return RotateSyntax(node, name, args, nil, nil, nil), diags
 	}
 
@@ -915,13 +921,13 @@ func parseShortRotate(node *syntax.ObjectNode, name *StringExpr, args Expr) (Exp
 	}
 
 	if inputs == nil {
-		diags.Extend(ExprError(obj, "missing provider inputs ('inputs')"))
+		diags.Extend(ExprError(obj, "missing provider inputs ('inputs')").WithCode(syntax.CodeInvalidBuiltinArgs))
 	}
 
 	if state == nil {
 		state = Null()
 	} else if _, ok := state.(*ObjectExpr); !ok {
-		diags.Extend(ExprError(state, "rotation state must be an object literal"))
+		diags.Extend(ExprError(state, "rotation state must be an object literal").WithCode(syntax.CodeInvalidBuiltinArgs))
 	}
 
 	return RotateSyntax(node, name, args, provider, inputs, state), diags
@@ -930,7 +936,7 @@ func parseShortRotate(node *syntax.ObjectNode, name *StringExpr, args Expr) (Exp
 func parseConcat(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, syntax.Diagnostics) {
 	list, ok := args.(*ArrayExpr)
 	if !ok {
-		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::concat must be an array of arrays")}
+		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::concat must be an array of arrays").WithCode(syntax.CodeInvalidBuiltinArgs)}
 		return ConcatSyntax(node, name, args), diags
 	}
 
@@ -940,7 +946,7 @@ func parseConcat(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, sy
 func parseJoin(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, syntax.Diagnostics) {
 	list, ok := args.(*ArrayExpr)
 	if !ok || len(list.Elements) != 2 {
-		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::join must be a two-valued list")}
+		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::join must be a two-valued list").WithCode(syntax.CodeInvalidBuiltinArgs)}
 		return JoinSyntax(node, name, args, nil, nil), diags
 	}
 
@@ -950,7 +956,7 @@ func parseJoin(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, synt
 func parseSplit(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, syntax.Diagnostics) {
 	list, ok := args.(*ArrayExpr)
 	if !ok || len(list.Elements) != 2 {
-		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::split must be a two-valued list")}
+		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::split must be a two-valued list").WithCode(syntax.CodeInvalidBuiltinArgs)}
 		return SplitSyntax(node, name, args, nil, nil), diags
 	}
 
@@ -995,7 +1001,7 @@ func parseSecret(node *syntax.ObjectNode, name *StringExpr, value Expr) (Expr, s
 	str, ok := value.(*StringExpr)
 	if !ok {
 		str = String("")
-		diags = syntax.Diagnostics{ExprError(value, "secret values must be string literals")}
+		diags = syntax.Diagnostics{ExprError(value, "secret values must be string literals").WithCode(syntax.CodeInvalidBuiltinArgs)}
 	}
 	return PlaintextSyntax(node, name, str), diags
 }
@@ -1003,7 +1009,7 @@ func parseSecret(node *syntax.ObjectNode, name *StringExpr, value Expr) (Expr, s
 func parseValidate(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr, syntax.Diagnostics) {
 	obj, ok := args.(*ObjectExpr)
 	if !ok {
-		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::validate must be an object containing 'schema' and 'value'")}
+		diags := syntax.Diagnostics{ExprError(args, "the argument to fn::validate must be an object containing 'schema' and 'value'").WithCode(syntax.CodeInvalidBuiltinArgs)}
 		return ValidateSyntax(node, name, args, nil, nil), diags
 	}
 
@@ -1020,10 +1026,10 @@ func parseValidate(node *syntax.ObjectNode, name *StringExpr, args Expr) (Expr,
 	}
 
 	if schemaExpr == nil {
-		diags.Extend(ExprError(obj, "missing required property 'schema'"))
+		diags.Extend(ExprError(obj, "missing required property 'schema'").WithCode(syntax.CodeInvalidBuiltinArgs))
 	}
 	if valueExpr == nil {
-		diags.Extend(ExprError(obj, "missing required property 'value'"))
+		diags.Extend(ExprError(obj, "missing required property 'value'").WithCode(syntax.CodeInvalidBuiltinArgs))
 	}
 
 	return ValidateSyntax(node, name, obj, schemaExpr, valueExpr), diags
@@ -0,0 +1,278 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pulumi/esc/syntax"
+	"github.com/pulumi/esc/syntax/encoding"
+	"gopkg.in/yaml.v3"
+)
+
+// Print serializes an environment declaration to YAML.
+//
+// The declaration's top-level sections are written in the order description, imports, diagnostics, values, schema.
+// Sections that are nil are omitted. Expressions are printed as described by ExprNode. The result of parsing the
+// printed source prints to the same source.
+func Print(env *EnvironmentDecl) ([]byte, syntax.Diagnostics) {
+	return printYAML(EnvironmentNode(env))
+}
+
+// PrintJSON serializes an environment declaration to JSON. See Print for details.
+func PrintJSON(env *EnvironmentDecl) ([]byte, syntax.Diagnostics) {
+	var b bytes.Buffer
+	if diags := encoding.EncodeJSON(&b, EnvironmentNode(env)); diags.HasErrors() {
+		return nil, diags
+	}
+	return b.Bytes(), nil
+}
+
+// PrintExpr serializes an expression to YAML. See ExprNode for details.
+func PrintExpr(x Expr) ([]byte, syntax.Diagnostics) {
+	return printYAML(ExprNode(x))
+}
+
+// stringNode returns a string node that is explicitly tagged as a YAML string. The YAML encoder quotes tagged strings
+// that would otherwise be decoded as other types, e.g. "0x1F" or ".inf".
+func stringNode(value string) *syntax.StringNode {
+	return syntax.StringSyntax(encoding.YAMLSyntax{Node: &yaml.Node{Tag: "!!str"}}, value)
+}
+
+func printYAML(n syntax.Node) ([]byte, syntax.Diagnostics) {
+	yamlNode, diags := encoding.MarshalYAML(n)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode); err != nil {
+		return nil, syntax.Diagnostics{syntax.Error(nil, err.Error(), "").WithCode(syntax.CodeYAMLEncode)}
+	}
+	return b.Bytes(), nil
+}
+
+// EnvironmentNode converts an environment declaration into a syntax tree that can be encoded using the syntax/encoding
+// package. The result does not reuse the syntax associated with the declaration, so comments are not preserved.
+func EnvironmentNode(env *EnvironmentDecl) syntax.Node {
+	if env == nil {
+		return syntax.Object()
+	}
+
+	var entries []syntax.ObjectPropertyDef
+	if env.Description != nil {
+		entries = append(entries, syntax.ObjectProperty(stringNode("description"), ExprNode(env.Description)))
+	}
+	if env.Imports != nil {
+		imports := make([]syntax.Node, len(env.Imports.Elements))
+		for i, imp := range env.Imports.Elements {
+			imports[i] = importNode(imp)
+		}
+		entries = append(entries, syntax.ObjectProperty(stringNode("imports"), syntax.Array(imports...)))
+	}
+	if env.Diagnostics != nil {
+		var diagnostics []syntax.ObjectPropertyDef
+		if env.Diagnostics.Allow != nil {
+			allow := make([]syntax.Node, len(env.Diagnostics.Allow.Elements))
+			for i, code := range env.Diagnostics.Allow.Elements {
+				allow[i] = ExprNode(code)
+			}
+			diagnostics = append(diagnostics, syntax.ObjectProperty(stringNode("allow"), syntax.Array(allow...)))
+		}
+		entries = append(entries, syntax.ObjectProperty(stringNode("diagnostics"), syntax.Object(diagnostics...)))
+	}
+	if env.Values != nil {
+		values := make([]syntax.ObjectPropertyDef, len(env.Values.Entries))
+		for i, entry := range env.Values.Entries {
+			values[i] = syntax.ObjectProperty(stringNode(entry.Key.GetValue()), ExprNode(entry.Value))
+		}
+		entries = append(entries, syntax.ObjectProperty(stringNode("values"), syntax.Object(values...)))
+	}
+	if env.Schema != nil {
+		entries = append(entries, syntax.ObjectProperty(stringNode("schema"), ExprNode(env.Schema)))
+	}
+	return syntax.Object(entries...)
+}
+
+func importNode(imp *ImportDecl) syntax.Node {
+	name := imp.Environment.GetValue()
+	if imp.Meta == nil {
+		return stringNode(name)
+	}
+
+	var meta []syntax.ObjectPropertyDef
+	addMeta := func(key string, value Expr) {
+		meta = append(meta, syntax.ObjectProperty(stringNode(key), ExprNode(value)))
+	}
+	if imp.Meta.Merge != nil {
+		addMeta("merge", imp.Meta.Merge)
+	}
+	if imp.Meta.Path != nil {
+		addMeta("path", imp.Meta.Path)
+	}
+	if imp.Meta.As != nil {
+		addMeta("as", imp.Meta.As)
+	}
+	if imp.Meta.Optional != nil {
+		addMeta("optional", imp.Meta.Optional)
+	}
+	if when := imp.Meta.When; when != nil {
+		var cond []syntax.ObjectPropertyDef
+		if when.Value != nil {
+			cond = append(cond, syntax.ObjectProperty(stringNode("value"), ExprNode(when.Value)))
+		}
+		if when.Equals != nil {
+			cond = append(cond, syntax.ObjectProperty(stringNode("equals"), ExprNode(when.Equals)))
+		}
+		if when.Matches != nil {
+			cond = append(cond, syntax.ObjectProperty(stringNode("matches"), ExprNode(when.Matches)))
+		}
+		meta = append(meta, syntax.ObjectProperty(stringNode("when"), syntax.Object(cond...)))
+	}
+	return syntax.Object(syntax.ObjectProperty(stringNode(name), syntax.Object(meta...)))
+}
+
+// ExprNode converts an expression into a syntax tree that can be encoded using the syntax/encoding package.
+//
+// String literals are escaped so that they are not parsed as interpolations, and symbols and interpolations are
+// written using `${...}` syntax. Builtin function calls are written in their idiomatic forms: calls to fn::open and
+// fn::rotate use the short forms `fn::open::<provider>` and `fn::rotate::<provider>`, calls to fn::join and fn::split
+// take two-element lists, and secrets are written as `fn::secret: <plaintext>` or
+// `fn::secret: {ciphertext: <ciphertext>}`. Builtin calls that are missing required arguments are written using their
+// original arguments. A nil expression is written as null.
+func ExprNode(x Expr) syntax.Node {
+	switch x := x.(type) {
+	case nil:
+		return syntax.Null()
+	case *NullExpr:
+		return syntax.Null()
+	case *BooleanExpr:
+		return syntax.Boolean(x.Value)
+	case *NumberExpr:
+		return syntax.Number(x.Value)
+	case *StringExpr:
+		return stringNode(escapeText(x.GetValue(), false))
+	case *SymbolExpr:
+		return stringNode(x.String())
+	case *InterpolateExpr:
+		var str strings.Builder
+		for _, p := range x.Parts {
+			str.WriteString(escapeText(p.Text, p.Value != nil))
+			if p.Value != nil {
+				str.WriteString("${" + p.Value.String() + "}")
+			}
+		}
+		return stringNode(str.String())
+	case *ArrayExpr:
+		elements := make([]syntax.Node, len(x.Elements))
+		for i, e := range x.Elements {
+			elements[i] = ExprNode(e)
+		}
+		return syntax.Array(elements...)
+	case *ObjectExpr:
+		entries := make([]syntax.ObjectPropertyDef, len(x.Entries))
+		for i, entry := range x.Entries {
+			entries[i] = syntax.ObjectProperty(stringNode(escapeText(entry.Key.GetValue(), false)), ExprNode(entry.Value))
+		}
+		return syntax.Object(entries...)
+	case *OpenExpr:
+		if x.Provider.GetValue() == "" || x.Inputs == nil {
+			return printBuiltin(x.Name().GetValue(), x.Args())
+		}
+		return printBuiltin("fn::open::"+x.Provider.Value, x.Inputs)
+	case *RotateExpr:
+		if x.Provider.GetValue() == "" || x.Inputs == nil {
+			return printBuiltin(x.Name().GetValue(), x.Args())
+		}
+		args := []syntax.ObjectPropertyDef{syntax.ObjectProperty(stringNode("inputs"), ExprNode(x.Inputs))}
+		if _, isNull := x.State.(*NullExpr); x.State != nil && !isNull {
+			args = append(args, syntax.ObjectProperty(stringNode("state"), ExprNode(x.State)))
+		}
+		return builtinCall("fn::rotate::"+x.Provider.Value, syntax.Object(args...))
+	case *JoinExpr:
+		if x.Delimiter == nil || x.Values == nil {
+			return printBuiltin("fn::join", x.Args())
+		}
+		return builtinCall("fn::join", syntax.Array(ExprNode(x.Delimiter), ExprNode(x.Values)))
+	case *SplitExpr:
+		if x.Delimiter == nil || x.String == nil {
+			return printBuiltin("fn::split", x.Args())
+		}
+		return builtinCall("fn::split", syntax.Array(ExprNode(x.Delimiter), ExprNode(x.String)))
+	case *SecretExpr:
+		if x.Ciphertext != nil {
+			return builtinCall("fn::secret", syntax.Object(syntax.ObjectProperty(stringNode("ciphertext"), ExprNode(x.Ciphertext))))
+		}
+		return printBuiltin("fn::secret", x.Plaintext)
+	case *ValidateExpr:
+		if x.Schema == nil || x.Value == nil {
+			return printBuiltin("fn::validate", x.Args())
+		}
+		return builtinCall("fn::validate", syntax.Object(
+			syntax.ObjectProperty(stringNode("schema"), ExprNode(x.Schema)),
+			syntax.ObjectProperty(stringNode("value"), ExprNode(x.Value)),
+		))
+	case *ConcatExpr:
+		return printBuiltin("fn::concat", x.Arrays)
+	case *ToJSONExpr:
+		return printBuiltin("fn::toJSON", x.Value)
+	case *FromJSONExpr:
+		return printBuiltin("fn::fromJSON", x.String)
+	case *ToStringExpr:
+		return printBuiltin("fn::toString", x.Value)
+	case *ToBase64Expr:
+		return printBuiltin("fn::toBase64", x.Value)
+	case *FromBase64Expr:
+		return printBuiltin("fn::fromBase64", x.String)
+	case *FinalExpr:
+		return printBuiltin("fn::final", x.Value)
+	case BuiltinExpr:
+		return printBuiltin(x.Name().GetValue(), x.Args())
+	default:
+		return syntax.Null()
+	}
+}
+
+// printBuiltin returns the syntax for a call to the named builtin with the given argument expression.
+func printBuiltin(name string, args Expr) syntax.Node {
+	return builtinCall(name, ExprNode(args))
+}
+
+func builtinCall(name string, args syntax.Node) syntax.Node {
+	return syntax.Object(syntax.ObjectProperty(stringNode(name), args))
+}
+
+// escapeText escapes the dollar signs in s that would otherwise be parsed as the start of an escape sequence (`$$`) or
+// an interpolation (`${`). If interpolation is true, s is followed by an interpolation.
+func escapeText(s string, interpolation bool) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		b.WriteByte(s[i])
+		if s[i] != '$' {
+			continue
+		}
+		if i+1 < len(s) && (s[i+1] == '$' || s[i+1] == '{') || i+1 == len(s) && interpolation {
+			b.WriteByte('$')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,151 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/esc/syntax/encoding"
+)
+
+func TestPrint(t *testing.T) {
+	path := filepath.Join("testdata", "print")
+	entries, err := os.ReadDir(path)
+	require.NoError(t, err)
+	for _, e := range entries {
+		t.Run(e.Name(), func(t *testing.T) {
+			basePath := filepath.Join(path, e.Name())
+			yamlPath, jsonPath := filepath.Join(basePath, "printed.yaml"), filepath.Join(basePath, "printed.json")
+
+			envBytes, err := os.ReadFile(filepath.Join(basePath, "env.yaml"))
+			require.NoError(t, err)
+
+			decl, diags, err := loadYAMLBytes(e.Name(), envBytes)
+			require.NoError(t, err)
+			require.Empty(t, diags)
+
+			printedYAML, diags := Print(decl)
+			require.Empty(t, diags)
+			printedJSON, diags := PrintJSON(decl)
+			require.Empty(t, diags)
+
+			if accept() {
+				require.NoError(t, os.WriteFile(yamlPath, printedYAML, 0o600))
+				require.NoError(t, os.WriteFile(jsonPath, printedJSON, 0o600))
+				return
+			}
+
+			expectedYAML, err := os.ReadFile(yamlPath)
+			require.NoError(t, err)
+			assert.Equal(t, string(expectedYAML), string(printedYAML))
+
+			expectedJSON, err := os.ReadFile(jsonPath)
+			require.NoError(t, err)
+			assert.Equal(t, string(expectedJSON), string(printedJSON))
+
+			// Printing the result of parsing the printed source must be stable.
+			reparsed, diags, err := loadYAMLBytes(e.Name(), printedYAML)
+			require.NoError(t, err)
+			require.Empty(t, diags)
+			reprinted, diags := Print(reparsed)
+			require.Empty(t, diags)
+			assert.Equal(t, string(printedYAML), string(reprinted))
+
+			syn, diags := encoding.DecodeJSONBytes(e.Name(), printedJSON)
+			require.Empty(t, diags)
+			reparsed, diags = ParseEnvironment(printedJSON, syn)
+			require.Empty(t, diags)
+			reprinted, diags = Print(reparsed)
+			require.Empty(t, diags)
+			assert.Equal(t, string(printedYAML), string(reprinted))
+		})
+	}
+}
+
+func TestPrintConstructed(t *testing.T) {
+	t.Parallel()
+
+	env := Environment(
+		String("generated"),
+		&ArrayDecl[*ImportDecl]{Elements: []*ImportDecl{{Environment: String("base")}}},
+		&MapDecl[Expr]{Entries: []PropertyMapEntry{
+			{Key: String("aws"), Value: Open("aws-login", Object(
+				ObjectProperty{Key: String("duration"), Value: String("1h")},
+			))},
+			{Key: String("region"), Value: Symbol(&PropertyName{Name: "base"}, &PropertyName{Name: "region"})},
+			{Key: String("url"), Value: MustInterpolate("https://${host}:${port}/")},
+			{Key: String("hosts"), Value: Join(String(","), Array(String("a"), String("b")))},
+			{Key: String("password"), Value: Plaintext(String("pa$$word"))},
+			{Key: String("count"), Value: Number(3)},
+			{Key: String("rotated"), Value: Rotate("aws-iam", Object(
+				ObjectProperty{Key: String("user"), Value: String("ci")},
+			), Null())},
+		}},
+	)
+
+	const expected = `description: generated
+imports:
+  - base
+values:
+  aws:
+    fn::open::aws-login:
+      duration: 1h
+  region: ${base.region}
+  url: https://${host}:${port}/
+  hosts:
+    fn::join:
+      - ','
+      - - a
+        - b
+  password:
+    fn::secret: pa$$$word
+  count: 3
+  rotated:
+    fn::rotate::aws-iam:
+      inputs:
+        user: ci
+`
+
+	printed, diags := Print(env)
+	require.Empty(t, diags)
+	assert.Equal(t, expected, string(printed))
+}
+
+func TestPrintExpr(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expr     Expr
+		expected string
+	}{
+		{nil, "null\n"},
+		{String("${literal}"), "$${literal}\n"},
+		{MustInterpolate("$$${x}"), "$$${x}\n"},
+		{ToJSON(Object(ObjectProperty{Key: String("a"), Value: Boolean(true)})), "fn::toJSON:\n  a: true\n"},
+		{Ciphertext(String("abcd")), "fn::secret:\n  ciphertext: abcd\n"},
+		{Validate(Object(ObjectProperty{Key: String("type"), Value: String("string")}), String("x")),
+			"fn::validate:\n  schema:\n    type: string\n  value: x\n"},
+	}
+	for _, c := range cases {
+		printed, diags := PrintExpr(c.expr)
+		require.Empty(t, diags)
+		assert.Equal(t, c.expected, string(printed))
+	}
+}
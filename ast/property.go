@@ -103,9 +103,9 @@ type propertyAccessParser struct {
 func (p *propertyAccessParser) error(start int, msg string) {
 	rng := p.rangeFrom(start)
 	if rng != nil {
-		p.diags.Extend(syntax.Error(rng, msg, p.parent.Syntax().Path()))
+		p.diags.Extend(syntax.Error(rng, msg, p.parent.Syntax().Path()).WithCode(syntax.CodeInvalidAccessSyntax).WithAliasRange(p.parent.Syntax()))
 	} else {
-		p.diags.Extend(syntax.NodeError(p.parent, msg))
+		p.diags.Extend(syntax.NodeError(p.parent, msg).WithCode(syntax.CodeInvalidAccessSyntax))
 	}
 }
 
@@ -0,0 +1,475 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/pulumi/esc/syntax"
+)
+
+// SkipChildren is used as a return value from Visitor callbacks to indicate that the children of the current expression
+// are not to be visited. It is not returned as an error by any function.
+var SkipChildren = errors.New("skip children")
+
+// A Visitor holds the callbacks that Walk calls for each expression. Each callback is optional.
+//
+// For each expression, Walk calls Expr, then Builtin if the expression is a call to a builtin function, and then the
+// callback for the expression's kind. For symbols and interpolated strings, PropertyAccess is then called for each
+// property access in the expression. If any of these callbacks returns SkipChildren, the expression's children are not
+// visited. Any other non-nil error stops the walk.
+type Visitor struct {
+	Expr           func(x Expr) error
+	PropertyAccess func(x Expr, access *PropertyAccess) error
+
+	Null        func(x *NullExpr) error
+	Boolean     func(x *BooleanExpr) error
+	Number      func(x *NumberExpr) error
+	String      func(x *StringExpr) error
+	Interpolate func(x *InterpolateExpr) error
+	Symbol      func(x *SymbolExpr) error
+	Array       func(x *ArrayExpr) error
+	Object      func(x *ObjectExpr) error
+
+	Builtin    func(x BuiltinExpr) error
+	Open       func(x *OpenExpr) error
+	Rotate     func(x *RotateExpr) error
+	ToJSON     func(x *ToJSONExpr) error
+	FromJSON   func(x *FromJSONExpr) error
+	ToString   func(x *ToStringExpr) error
+	Join       func(x *JoinExpr) error
+	Concat     func(x *ConcatExpr) error
+	Split      func(x *SplitExpr) error
+	Secret     func(x *SecretExpr) error
+	ToBase64   func(x *ToBase64Expr) error
+	FromBase64 func(x *FromBase64Expr) error
+	Final      func(x *FinalExpr) error
+	Validate   func(x *ValidateExpr) error
+}
+
+// Walk walks the tree rooted at the given expression in depth-first order. The visitor's callbacks are called for an
+// expression before its children are walked (i.e. visitation is pre-order).
+//
+// The children of an array are its elements, and the children of an object are its property values. The only child of
+// a builtin function call is its argument expression (see BuiltinExpr.Args), which contains the expressions that are
+// referenced by the call's typed fields.
+func Walk(x Expr, v *Visitor) error {
+	if isNilExpr(x) {
+		return nil
+	}
+
+	skip := false
+	err := visit(v.Expr, x, &skip)
+	if err == nil {
+		switch x := x.(type) {
+		case *NullExpr:
+			err = visit(v.Null, x, &skip)
+		case *BooleanExpr:
+			err = visit(v.Boolean, x, &skip)
+		case *NumberExpr:
+			err = visit(v.Number, x, &skip)
+		case *StringExpr:
+			err = visit(v.String, x, &skip)
+		case *InterpolateExpr:
+			err = visit(v.Interpolate, x, &skip)
+			for _, part := range x.Parts {
+				if err != nil {
+					break
+				}
+				if part.Value != nil {
+					err = v.visitPropertyAccess(x, part.Value, &skip)
+				}
+			}
+		case *SymbolExpr:
+			err = visit(v.Symbol, x, &skip)
+			if err == nil {
+				err = v.visitPropertyAccess(x, x.Property, &skip)
+			}
+		case *ArrayExpr:
+			err = visit(v.Array, x, &skip)
+		case *ObjectExpr:
+			err = visit(v.Object, x, &skip)
+		case BuiltinExpr:
+			err = visit(v.Builtin, x, &skip)
+			if err == nil {
+				err = v.visitBuiltin(x, &skip)
+			}
+		}
+	}
+	if err != nil || skip {
+		return err
+	}
+
+	for _, child := range children(x) {
+		if err := Walk(child, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkEnvironment walks each expression in the given environment declaration. The description, the metadata of each
+// import, the allowed diagnostic codes, the values, and the schema are walked in that order. See Walk for details.
+func WalkEnvironment(env *EnvironmentDecl, v *Visitor) error {
+	if env == nil {
+		return nil
+	}
+
+	exprs := []Expr{env.Description}
+	for _, imp := range env.Imports.GetElements() {
+		if meta := imp.Meta; meta != nil {
+			exprs = append(exprs, meta.Merge, meta.Path, meta.As, meta.Optional)
+			if when := meta.When; when != nil {
+				exprs = append(exprs, when.Value, when.Equals, when.Matches)
+			}
+		}
+	}
+	for _, code := range env.Diagnostics.GetAllow() {
+		exprs = append(exprs, code)
+	}
+	for _, entry := range env.Values.GetEntries() {
+		exprs = append(exprs, entry.Value)
+	}
+	exprs = append(exprs, env.Schema)
+
+	for _, x := range exprs {
+		if err := Walk(x, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func visit[T Expr](f func(x T) error, x T, skip *bool) error {
+	if f == nil {
+		return nil
+	}
+	return skipChildren(f(x), skip)
+}
+
+func skipChildren(err error, skip *bool) error {
+	if err == SkipChildren {
+		*skip = true
+		return nil
+	}
+	return err
+}
+
+func (v *Visitor) visitPropertyAccess(x Expr, access *PropertyAccess, skip *bool) error {
+	if v.PropertyAccess == nil {
+		return nil
+	}
+	return skipChildren(v.PropertyAccess(x, access), skip)
+}
+
+func (v *Visitor) visitBuiltin(x BuiltinExpr, skip *bool) error {
+	switch x := x.(type) {
+	case *OpenExpr:
+		return visit(v.Open, x, skip)
+	case *RotateExpr:
+		return visit(v.Rotate, x, skip)
+	case *ToJSONExpr:
+		return visit(v.ToJSON, x, skip)
+	case *FromJSONExpr:
+		return visit(v.FromJSON, x, skip)
+	case *ToStringExpr:
+		return visit(v.ToString, x, skip)
+	case *JoinExpr:
+		return visit(v.Join, x, skip)
+	case *ConcatExpr:
+		return visit(v.Concat, x, skip)
+	case *SplitExpr:
+		return visit(v.Split, x, skip)
+	case *SecretExpr:
+		return visit(v.Secret, x, skip)
+	case *ToBase64Expr:
+		return visit(v.ToBase64, x, skip)
+	case *FromBase64Expr:
+		return visit(v.FromBase64, x, skip)
+	case *FinalExpr:
+		return visit(v.Final, x, skip)
+	case *ValidateExpr:
+		return visit(v.Validate, x, skip)
+	default:
+		return nil
+	}
+}
+
+// children returns the children of the given expression.
+func children(x Expr) []Expr {
+	switch x := x.(type) {
+	case *ArrayExpr:
+		return x.Elements
+	case *ObjectExpr:
+		values := make([]Expr, len(x.Entries))
+		for i, entry := range x.Entries {
+			values[i] = entry.Value
+		}
+		return values
+	case BuiltinExpr:
+		return []Expr{x.Args()}
+	default:
+		return nil
+	}
+}
+
+// Rewrite rewrites the tree rooted at the given expression. The rewriter is called after the expression's children have
+// been rewritten (i.e. rewriting is post-order), and its result replaces the expression in the tree. A rewriter that
+// does not change an expression returns the expression itself. See Walk for the children of each kind of expression.
+//
+// Expressions whose children are replaced are rebuilt with new syntax nodes that carry the syntax of the original
+// expressions, so the syntax of the result can be encoded with its comments intact. If the rewriter returns an
+// expression that has no syntax, the expression is copied and the copy is given the comments of the expression it
+// replaces. Builtin function calls whose arguments are replaced are checked again, and any problems are reported in
+// the returned diagnostics.
+func Rewrite(x Expr, rewriter func(x Expr) (Expr, syntax.Diagnostics, error)) (Expr, syntax.Diagnostics, error) {
+	if isNilExpr(x) {
+		return x, nil, nil
+	}
+
+	x, diags, err := rewriteChildren(x, rewriter)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	y, d, err := rewriter(x)
+	diags.Extend(d...)
+	if err != nil {
+		return nil, diags, err
+	}
+	if y != x && !isNilExpr(y) && syntaxOf(y) == syntax.NoSyntax {
+		if trivia := syntax.CopyTrivia(syntaxOf(x)); trivia != syntax.NoSyntax {
+			y = withSyntax(y, trivia)
+		}
+	}
+	return y, diags, nil
+}
+
+// RewriteEnvironment rewrites the values and the schema of the given environment declaration in place. The syntax of
+// the declaration is updated to match, so it can be encoded with its comments intact. See Rewrite for details.
+func RewriteEnvironment(env *EnvironmentDecl, rewriter func(x Expr) (Expr, syntax.Diagnostics, error)) (syntax.Diagnostics, error) {
+	if env == nil {
+		return nil, nil
+	}
+
+	var diags syntax.Diagnostics
+	if env.Values != nil {
+		obj, _ := env.Values.syntax.(*syntax.ObjectNode)
+		for i, entry := range env.Values.Entries {
+			value, d, err := Rewrite(entry.Value, rewriter)
+			diags.Extend(d...)
+			if err != nil {
+				return diags, err
+			}
+			if value == entry.Value {
+				continue
+			}
+
+			env.Values.Entries[i].Value = value
+			if obj != nil && i < obj.Len() {
+				kvp := obj.Index(i)
+				env.Values.Entries[i].syntax = syntax.ObjectPropertySyntax(kvp.Syntax, kvp.Key, exprSyntax(value))
+				obj.SetIndex(i, env.Values.Entries[i].syntax)
+			}
+		}
+	}
+
+	if env.Schema != nil {
+		schema, d, err := Rewrite(env.Schema, rewriter)
+		diags.Extend(d...)
+		if err != nil {
+			return diags, err
+		}
+		if schema != env.Schema {
+			env.Schema = schema
+			if obj, ok := env.syntax.(*syntax.ObjectNode); ok {
+				for i := 0; i < obj.Len(); i++ {
+					if kvp := obj.Index(i); strings.EqualFold(kvp.Key.Value(), "schema") {
+						obj.SetIndex(i, syntax.ObjectPropertySyntax(kvp.Syntax, kvp.Key, exprSyntax(schema)))
+					}
+				}
+			}
+		}
+	}
+	return diags, nil
+}
+
+func rewriteChildren(x Expr, rewriter func(x Expr) (Expr, syntax.Diagnostics, error)) (Expr, syntax.Diagnostics, error) {
+	var diags syntax.Diagnostics
+	switch x := x.(type) {
+	case *ArrayExpr:
+		var elements []Expr
+		for i, e := range x.Elements {
+			r, d, err := Rewrite(e, rewriter)
+			diags.Extend(d...)
+			if err != nil {
+				return nil, diags, err
+			}
+			if r != e && elements == nil {
+				elements = append([]Expr(nil), x.Elements...)
+			}
+			if elements != nil {
+				elements[i] = r
+			}
+		}
+		if elements == nil {
+			return x, diags, nil
+		}
+
+		nodes := make([]syntax.Node, len(elements))
+		for i, e := range elements {
+			nodes[i] = exprSyntax(e)
+		}
+		return ArraySyntax(syntax.ArraySyntax(syntaxOf(x), nodes...), elements...), diags, nil
+	case *ObjectExpr:
+		var entries []ObjectProperty
+		for i, entry := range x.Entries {
+			r, d, err := Rewrite(entry.Value, rewriter)
+			diags.Extend(d...)
+			if err != nil {
+				return nil, diags, err
+			}
+			if r != entry.Value && entries == nil {
+				entries = append([]ObjectProperty(nil), x.Entries...)
+			}
+			if entries != nil {
+				entries[i].Value = r
+			}
+		}
+		if entries == nil {
+			return x, diags, nil
+		}
+
+		defs := make([]syntax.ObjectPropertyDef, len(entries))
+		for i, entry := range entries {
+			entries[i].syntax = syntax.ObjectPropertySyntax(entry.syntax.Syntax, keySyntax(entry.Key), exprSyntax(entry.Value))
+			defs[i] = entries[i].syntax
+		}
+		return ObjectSyntax(syntax.ObjectSyntax(syntaxOf(x), defs...), entries...), diags, nil
+	case BuiltinExpr:
+		args, d, err := Rewrite(x.Args(), rewriter)
+		diags.Extend(d...)
+		if err != nil {
+			return nil, diags, err
+		}
+		if args == x.Args() {
+			return x, diags, nil
+		}
+
+		var callSyntax syntax.Syntax
+		if node, ok := syntaxNode(x).(*syntax.ObjectNode); ok && node.Len() == 1 {
+			callSyntax = node.Index(0).Syntax
+		}
+		call := syntax.ObjectPropertySyntax(callSyntax, keySyntax(x.Name()), exprSyntax(args))
+		node := syntax.ObjectSyntax(syntaxOf(x), call)
+
+		parse, ok := lookupBuiltin(x.Name().GetValue())
+		if !ok {
+			return ObjectSyntax(node, ObjectProperty{syntax: call, Key: x.Name(), Value: args}), diags, nil
+		}
+		y, d := parse(node, x.Name(), args)
+		diags.Extend(d...)
+		return y, diags, nil
+	default:
+		return x, nil, nil
+	}
+}
+
+// isNilExpr returns true if x is nil or is a nil pointer.
+func isNilExpr(x Expr) bool {
+	if x == nil {
+		return true
+	}
+	v := reflect.ValueOf(x)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// syntaxNode returns the syntax node associated with the given expression, if any.
+func syntaxNode(x Expr) syntax.Node {
+	if isNilExpr(x) {
+		return nil
+	}
+	node := x.Syntax()
+	if v := reflect.ValueOf(node); !v.IsValid() || v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil
+	}
+	return node
+}
+
+// exprSyntax returns the syntax node for the given expression. Expressions that were constructed without syntax nodes
+// are converted using ExprNode.
+func exprSyntax(x Expr) syntax.Node {
+	if node := syntaxNode(x); node != nil {
+		return node
+	}
+	return ExprNode(x)
+}
+
+// syntaxOf returns the syntax associated with the given expression's syntax node.
+func syntaxOf(x Expr) syntax.Syntax {
+	if node := syntaxNode(x); node != nil {
+		return node.Syntax()
+	}
+	return syntax.NoSyntax
+}
+
+func keySyntax(key *StringExpr) *syntax.StringNode {
+	if node, ok := syntaxNode(key).(*syntax.StringNode); ok {
+		return node
+	}
+	return syntax.String(key.GetValue())
+}
+
+// withSyntax returns a copy of the given expression whose syntax node is a copy of the expression's syntax node with
+// the given syntax.
+func withSyntax(x Expr, s syntax.Syntax) Expr {
+	var node syntax.Node
+	switch n := exprSyntax(x).(type) {
+	case *syntax.NullNode:
+		node = syntax.NullSyntax(s)
+	case *syntax.BooleanNode:
+		node = syntax.BooleanSyntax(s, n.Value())
+	case *syntax.NumberNode:
+		node = syntax.NumberSyntax(s, n.Value())
+	case *syntax.StringNode:
+		node = syntax.StringSyntax(s, n.Value())
+	case *syntax.ArrayNode:
+		elements := make([]syntax.Node, n.Len())
+		for i := range elements {
+			elements[i] = n.Index(i)
+		}
+		node = syntax.ArraySyntax(s, elements...)
+	case *syntax.ObjectNode:
+		entries := make([]syntax.ObjectPropertyDef, n.Len())
+		for i := range entries {
+			entries[i] = n.Index(i)
+		}
+		node = syntax.ObjectSyntax(s, entries...)
+	default:
+		return x
+	}
+
+	v := reflect.New(reflect.TypeOf(x).Elem())
+	v.Elem().Set(reflect.ValueOf(x).Elem())
+	y := v.Interface().(Expr)
+	y.(interface{ setSyntax(node syntax.Node) }).setSyntax(node)
+	return y
+}
+
+func (x *exprNode) setSyntax(node syntax.Node) {
+	x.syntax = node
+}
@@ -0,0 +1,241 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/pulumi/esc/syntax"
+	"github.com/pulumi/esc/syntax/encoding"
+)
+
+const walkExample = `imports:
+  - base
+values:
+  # The AWS credentials.
+  aws:
+    fn::open::aws-login:
+      oidc:
+        roleArn: ${base.roleArn} # the role
+        sessionName: ${base.name}-session
+  password:
+    fn::secret: hunter2
+  token:
+    fn::secret:
+      ciphertext: ZXNjeAAAAAEAAAEA
+  json:
+    fn::toJSON:
+      region: ${base.region}
+  hosts: [a, b]
+`
+
+func parseWalkExample(t *testing.T) *EnvironmentDecl {
+	env, diags, err := loadYAMLBytes("walk", []byte(walkExample))
+	require.NoError(t, err)
+	require.Empty(t, diags)
+	return env
+}
+
+func encodeEnvironment(t *testing.T, env *EnvironmentDecl) string {
+	node, diags := encoding.MarshalYAML(env.Syntax())
+	require.Empty(t, diags)
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	require.NoError(t, enc.Encode(node))
+	return b.String()
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	env := parseWalkExample(t)
+
+	var providers, references, ciphertexts, plaintexts []string
+	var builtins, strings int
+	err := WalkEnvironment(env, &Visitor{
+		Builtin: func(x BuiltinExpr) error {
+			builtins++
+			return nil
+		},
+		Open: func(x *OpenExpr) error {
+			providers = append(providers, x.Provider.Value)
+			return nil
+		},
+		Secret: func(x *SecretExpr) error {
+			if x.Ciphertext != nil {
+				ciphertexts = append(ciphertexts, x.Ciphertext.Value)
+			} else {
+				plaintexts = append(plaintexts, x.Plaintext.Value)
+			}
+			return nil
+		},
+		ToJSON: func(x *ToJSONExpr) error {
+			return SkipChildren
+		},
+		PropertyAccess: func(x Expr, access *PropertyAccess) error {
+			references = append(references, access.String())
+			return nil
+		},
+		String: func(x *StringExpr) error {
+			strings++
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"aws-login"}, providers)
+	assert.Equal(t, []string{"base.roleArn", "base.name"}, references)
+	assert.Equal(t, []string{"ZXNjeAAAAAEAAAEA"}, ciphertexts)
+	assert.Equal(t, []string{"hunter2"}, plaintexts)
+	assert.Equal(t, 4, builtins)
+	// hunter2, the ciphertext, a, and b. Import names are not expressions.
+	assert.Equal(t, 4, strings)
+}
+
+func TestWalkError(t *testing.T) {
+	t.Parallel()
+
+	env := parseWalkExample(t)
+
+	stop := errors.New("stop")
+	visited := 0
+	err := WalkEnvironment(env, &Visitor{
+		Open: func(x *OpenExpr) error {
+			return stop
+		},
+		Expr: func(x Expr) error {
+			visited++
+			return nil
+		},
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, visited)
+}
+
+func TestRewrite(t *testing.T) {
+	t.Parallel()
+
+	env := parseWalkExample(t)
+
+	diags, err := RewriteEnvironment(env, func(x Expr) (Expr, syntax.Diagnostics, error) {
+		switch x := x.(type) {
+		case *SymbolExpr:
+			if x.Property.RootName() == "base" {
+				accessors := append([]PropertyAccessor{&PropertyName{Name: "shared"}}, x.Property.Accessors[1:]...)
+				return Symbol(accessors...), nil, nil
+			}
+		case *SecretExpr:
+			if x.Plaintext != nil {
+				return Ciphertext(String("Y2lwaGVydGV4dA==")), nil, nil
+			}
+		case *StringExpr:
+			if x.Value == "a" {
+				return String("c"), nil, nil
+			}
+		}
+		return x, nil, nil
+	})
+	require.NoError(t, err)
+	require.Empty(t, diags)
+
+	const expected = `imports:
+  - base
+values:
+  # The AWS credentials.
+  aws:
+    fn::open::aws-login:
+      oidc:
+        roleArn: ${shared.roleArn} # the role
+        sessionName: ${base.name}-session
+  password:
+    fn::secret:
+      ciphertext: Y2lwaGVydGV4dA==
+  token:
+    fn::secret:
+      ciphertext: ZXNjeAAAAAEAAAEA
+  json:
+    fn::toJSON:
+      region: ${shared.region}
+  hosts: [c, b]
+`
+	assert.Equal(t, expected, encodeEnvironment(t, env))
+
+	// Rewritten builtins keep their typed fields.
+	open, ok := env.Values.Entries[0].Value.(*OpenExpr)
+	require.True(t, ok)
+	assert.Equal(t, "aws-login", open.Provider.Value)
+	inputs, ok := open.Inputs.(*ObjectExpr)
+	require.True(t, ok)
+	roleArn, ok := inputs.Entries[0].Value.(*ObjectExpr).Entries[0].Value.(*SymbolExpr)
+	require.True(t, ok)
+	assert.Equal(t, "shared.roleArn", roleArn.Property.String())
+
+	secret, ok := env.Values.Entries[1].Value.(*SecretExpr)
+	require.True(t, ok)
+	assert.Equal(t, "Y2lwaGVydGV4dA==", secret.Ciphertext.Value)
+
+	// The rewritten environment can be parsed again.
+	reparsed, diags, err := loadYAMLBytes("walk", []byte(encodeEnvironment(t, env)))
+	require.NoError(t, err)
+	require.Empty(t, diags)
+	printed, diags := Print(reparsed)
+	require.Empty(t, diags)
+	expectedPrinted, diags := Print(env)
+	require.Empty(t, diags)
+	assert.Equal(t, string(expectedPrinted), string(printed))
+}
+
+func TestRewriteUnchanged(t *testing.T) {
+	t.Parallel()
+
+	env := parseWalkExample(t)
+	values := env.Values.Entries[0].Value
+
+	diags, err := RewriteEnvironment(env, func(x Expr) (Expr, syntax.Diagnostics, error) {
+		return x, nil, nil
+	})
+	require.NoError(t, err)
+	require.Empty(t, diags)
+	assert.Same(t, values, env.Values.Entries[0].Value)
+	assert.Equal(t, walkExample, encodeEnvironment(t, env))
+}
+
+func TestRewriteDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	x, diags := ParseExpr(syntax.Object(syntax.ObjectProperty(syntax.String("fn::join"),
+		syntax.Array(syntax.String(","), syntax.Array(syntax.String("a"))))))
+	require.Empty(t, diags)
+
+	// Replacing the arguments to fn::join with a string is reported.
+	rewritten, diags, err := Rewrite(x, func(x Expr) (Expr, syntax.Diagnostics, error) {
+		if array, ok := x.(*ArrayExpr); ok && len(array.Elements) == 2 {
+			return String("oops"), nil, nil
+		}
+		return x, nil, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, diags, 1)
+	assert.Equal(t, "the argument to fn::join must be a two-valued list", diags[0].Summary)
+	assert.IsType(t, &JoinExpr{}, rewritten)
+}
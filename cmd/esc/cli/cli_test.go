@@ -26,14 +26,12 @@ import (
 	"time"
 
 	"github.com/gofrs/uuid"
-	"github.com/hashicorp/hcl/v2"
 	"github.com/pgavlin/fx/v2"
 	"github.com/pgavlin/fx/v2/maps"
 	"github.com/pulumi/esc"
 	"github.com/pulumi/esc/cmd/esc/cli/client"
 	"github.com/pulumi/esc/eval"
 	"github.com/pulumi/esc/schema"
-	"github.com/pulumi/esc/syntax"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
@@ -241,6 +239,27 @@ func (testProvider) Open(ctx context.Context, inputs map[string]esc.Value, conte
 	return esc.NewValue(inputs), nil
 }
 
+type testRotator struct{}
+
+func (testRotator) Schema() (*schema.Schema, *schema.Schema, *schema.Schema) {
+	state := schema.Object().Properties(schema.BuilderMap{
+		"current":  schema.String(),
+		"previous": schema.String(),
+	}).Schema()
+	return schema.Always(), state, state
+}
+
+func (testRotator) Open(ctx context.Context, inputs, state map[string]esc.Value, context esc.EnvExecContext) (esc.Value, error) {
+	return esc.NewValue(state), nil
+}
+
+func (testRotator) Rotate(ctx context.Context, inputs, state map[string]esc.Value, context esc.EnvExecContext) (esc.Value, error) {
+	return esc.NewValue(map[string]esc.Value{
+		"current":  esc.NewValue("next"),
+		"previous": state["current"],
+	}), nil
+}
+
 type testProviders struct{}
 
 func (testProviders) LoadProvider(ctx context.Context, name string) (esc.Provider, error) {
@@ -252,6 +271,9 @@ func (testProviders) LoadProvider(ctx context.Context, name string) (esc.Provide
 }
 
 func (testProviders) LoadRotator(ctx context.Context, name string) (esc.Rotator, error) {
+	if name == "test" {
+		return testRotator{}, nil
+	}
 	return nil, fmt.Errorf("unknown rotator %q", name)
 }
 
@@ -277,6 +299,10 @@ type testEnvironments struct {
 }
 
 func (e *testEnvironments) LoadEnvironment(ctx context.Context, ref string) ([]byte, eval.Decrypter, error) {
+	return e.LoadEnvironmentVersion(ctx, ref, "")
+}
+
+func (e *testEnvironments) LoadEnvironmentVersion(ctx context.Context, ref, version string) ([]byte, eval.Decrypter, error) {
 	var name string
 
 	// This "emulates" the backend behavior of resolving refs
@@ -289,9 +315,23 @@ func (e *testEnvironments) LoadEnvironment(ctx context.Context, ref string) ([]b
 
 	env, ok := e.environments[name]
 	if !ok {
-		return nil, nil, errors.New("not found")
+		return nil, nil, fmt.Errorf("%v: %w", ref, eval.ErrEnvironmentNotFound)
+	}
+
+	revision := len(env.revisions)
+	if version != "" {
+		if n, err := strconv.Atoi(version); err == nil {
+			revision = n
+		} else if n, ok := env.revisionTags[version]; ok {
+			revision = n
+		} else {
+			return nil, nil, fmt.Errorf("%v@%v: %w", ref, version, eval.ErrEnvironmentNotFound)
+		}
 	}
-	return env.latest().yaml, rot128{}, nil
+	if revision < 1 || revision > len(env.revisions) {
+		return nil, nil, fmt.Errorf("%v@%v: %w", ref, version, eval.ErrEnvironmentNotFound)
+	}
+	return env.revisions[revision-1].yaml, rot128{}, nil
 }
 
 type testEnvironmentRetract struct {
@@ -398,44 +438,6 @@ func (lm *testLoginManager) LoginWithOIDCToken(
 	return &acct, nil
 }
 
-func mapDiags(diags syntax.Diagnostics) []client.EnvironmentDiagnostic {
-	if len(diags) == 0 {
-		return nil
-	}
-	out := make([]client.EnvironmentDiagnostic, len(diags))
-	for i, d := range diags {
-		var rng *esc.Range
-		if d.Subject != nil {
-			rng = &esc.Range{
-				Environment: d.Subject.Filename,
-				Begin: esc.Pos{
-					Line:   d.Subject.Start.Line,
-					Column: d.Subject.Start.Column,
-					Byte:   d.Subject.Start.Byte,
-				},
-				End: esc.Pos{
-					Line:   d.Subject.End.Line,
-					Column: d.Subject.End.Column,
-					Byte:   d.Subject.End.Byte,
-				},
-			}
-		}
-
-		severity := client.DiagError
-		if d.Severity == hcl.DiagWarning {
-			severity = client.DiagWarning
-		}
-
-		out[i] = client.EnvironmentDiagnostic{
-			Range:    rng,
-			Summary:  d.Summary,
-			Detail:   d.Detail,
-			Severity: severity,
-		}
-	}
-	return out
-}
-
 func (c *testPulumiClient) getEnvironment(orgName, projectName, envName, version string) (*testEnvironment, *testEnvironmentRevision, error) {
 	name := path.Join(orgName, projectName, envName)
 
@@ -470,7 +472,7 @@ func (c *testPulumiClient) checkEnvironment(ctx context.Context, orgName, envNam
 		return nil, nil, fmt.Errorf("loading environment: %w", err)
 	}
 	if diags.HasErrors() {
-		return nil, mapDiags(diags), nil
+		return nil, clientDiagnostics(diags), nil
 	}
 
 	providers := &testProviders{}
@@ -488,7 +490,7 @@ func (c *testPulumiClient) checkEnvironment(ctx context.Context, orgName, envNam
 
 	checked, checkDiags := eval.CheckEnvironment(ctx, envName, environment, rot128{}, providers, envLoader, execContext, showSecrets)
 	diags.Extend(checkDiags...)
-	return checked, mapDiags(diags), nil
+	return checked, clientDiagnostics(diags), nil
 }
 
 func (c *testPulumiClient) openEnvironment(ctx context.Context, orgName, name string, yaml []byte) (string, []client.EnvironmentDiagnostic, error) {
@@ -502,7 +504,7 @@ func (c *testPulumiClient) openEnvironment(ctx context.Context, orgName, name st
 		return "", nil, fmt.Errorf("loading environment: %w", err)
 	}
 	if diags.HasErrors() {
-		return "", mapDiags(diags), nil
+		return "", clientDiagnostics(diags), nil
 	}
 
 	providers := &testProviders{}
@@ -517,11 +519,11 @@ func (c *testPulumiClient) openEnvironment(ctx context.Context, orgName, name st
 	diags.Extend(evalDiags...)
 
 	if diags.HasErrors() {
-		return "", mapDiags(diags), nil
+		return "", clientDiagnostics(diags), nil
 	}
 
 	c.openEnvs[id.String()] = openEnv
-	return id.String(), mapDiags(diags), nil
+	return id.String(), clientDiagnostics(diags), nil
 }
 
 // Returns true if this client is insecure (i.e. has TLS disabled).
@@ -35,6 +35,7 @@ type EnvironmentDiagnostic struct {
 	Summary  string                        `json:"summary,omitempty"`
 	Detail   string                        `json:"detail,omitempty"`
 	Severity EnvironmentDiagnosticSeverity `json:"severity,omitempty"`
+	Code     string                        `json:"code,omitempty"`
 }
 
 func (d EnvironmentDiagnostic) IsError() bool {
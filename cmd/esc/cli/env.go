@@ -35,6 +35,7 @@ type envCommand struct {
 	esc *escCommand
 
 	envNameFlag string
+	diagsFormat diagnosticsFormat
 }
 
 func newEnvCmd(esc *escCommand) *cobra.Command {
@@ -61,11 +62,17 @@ func newEnvCmd(esc *escCommand) *cobra.Command {
 	env := &envCommand{esc: esc}
 
 	cmd.PersistentFlags().StringVar(&env.envNameFlag, "env", "", "The name of the environment to operate on.")
+	cmd.PersistentFlags().Var(&env.diagsFormat, "diagnostics-format",
+		"The format used to print diagnostics. One of 'text', 'json', or 'sarif'.")
 
 	cmd.AddCommand(newEnvInitCmd(env))
 	cmd.AddCommand(newEnvCloneCmd(env))
 	cmd.AddCommand(newEnvEditCmd(env))
+	cmd.AddCommand(newEnvCheckCmd(env))
+	cmd.AddCommand(newEnvFmtCmd(env))
+	cmd.AddCommand(newEnvCodegenCmd(env))
 	cmd.AddCommand(newEnvGetCmd(env))
+	cmd.AddCommand(newEnvExplainCmd(env))
 	cmd.AddCommand(newEnvDiffCmd(env))
 	cmd.AddCommand(newEnvSetCmd(env))
 	cmd.AddCommand(newEnvVersionCmd(env))
@@ -333,11 +340,27 @@ func sortEnvironmentDiagnostics(diags []client.EnvironmentDiagnostic) {
 	})
 }
 
+// writeYAMLEnvironmentDiagnostics writes diagnostics for the given environment definition in the format selected by
+// the --diagnostics-format flag.
 func (cmd *envCommand) writeYAMLEnvironmentDiagnostics(
 	out io.Writer,
 	envName string,
 	yaml []byte,
 	diags []client.EnvironmentDiagnostic,
+) error {
+	if ok, err := cmd.diagsFormat.writeMachineDiagnostics(out, diags); ok {
+		return err
+	}
+	return cmd.writeYAMLEnvironmentDiagnosticsText(out, envName, yaml, diags)
+}
+
+// writeYAMLEnvironmentDiagnosticsText writes diagnostics for the given environment definition as text annotated with
+// snippets of the definition.
+func (cmd *envCommand) writeYAMLEnvironmentDiagnosticsText(
+	out io.Writer,
+	envName string,
+	yaml []byte,
+	diags []client.EnvironmentDiagnostic,
 ) error {
 	width, color := 0, false
 	if file, ok := out.(*os.File); ok {
@@ -378,9 +401,14 @@ func (cmd *envCommand) writeYAMLEnvironmentDiagnostics(
 		if d.Severity == client.DiagWarning {
 			severity = hcl.DiagWarning
 		}
+		summary := d.Summary
+		if d.Code != "" {
+			summary = fmt.Sprintf("%v [%v]", summary, d.Code)
+		}
 		err := writer.WriteDiagnostic(&hcl.Diagnostic{
 			Severity: severity,
-			Summary:  d.Summary,
+			Summary:  summary,
+			Detail:   d.Detail,
 			Subject:  subject,
 		})
 		if err != nil {
@@ -392,6 +420,10 @@ func (cmd *envCommand) writeYAMLEnvironmentDiagnostics(
 }
 
 func (cmd *envCommand) writePropertyEnvironmentDiagnostics(out io.Writer, diags []client.EnvironmentDiagnostic) error {
+	if ok, err := cmd.diagsFormat.writeMachineDiagnostics(out, diags); ok {
+		return err
+	}
+
 	sortEnvironmentDiagnostics(diags)
 
 	var b strings.Builder
@@ -0,0 +1,114 @@
+// Copyright 2026, Pulumi Corporation.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/esc/cmd/esc/cli/client"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+func newEnvCheckCmd(env *envCommand) *cobra.Command {
+	var file string
+	var strict bool
+
+	cmd := &cobra.Command{
+		Use:   "check [<org-name>/][<project-name>/]<environment-name>[@<version>]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Check an environment definition for problems",
+		Long: "Check an environment definition for problems\n" +
+			"\n" +
+			"This command checks the definition of the named environment and reports any errors\n" +
+			"or warnings. If --file is given, the definition is read from the given file instead\n" +
+			"and checked within the context of the named environment's organization.\n" +
+			"\n" +
+			"When --strict is set, warnings are reported as errors. Specific warnings may be\n" +
+			"allowed by listing their codes in the environment definition:\n" +
+			"\n" +
+			"  diagnostics:\n" +
+			"    allow: [duplicate-key]\n",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			if err := env.esc.getCachedClient(ctx); err != nil {
+				return err
+			}
+
+			ref, args, err := env.getExistingEnvRef(ctx, args)
+			if err != nil {
+				return err
+			}
+			_ = args
+
+			var yaml []byte
+			switch file {
+			case "":
+				yaml, _, _, err = env.esc.client.GetEnvironment(ctx, ref.orgName, ref.projectName, ref.envName, ref.version, false)
+				if err != nil {
+					return fmt.Errorf("getting environment definition: %w", err)
+				}
+			case "-":
+				yaml, err = io.ReadAll(env.esc.stdin)
+			default:
+				yaml, err = fs.ReadFile(env.esc.fs, file)
+			}
+			if err != nil {
+				return fmt.Errorf("reading environment definition: %w", err)
+			}
+
+			_, diags, err := env.esc.client.CheckYAMLEnvironment(ctx, ref.orgName, yaml)
+			if err != nil {
+				return fmt.Errorf("checking environment definition: %w", err)
+			}
+			if strict {
+				diags = strictDiagnostics(diags)
+			}
+
+			// Machine-readable diagnostics are always written to stdout, even if there are no diagnostics, so that they
+			// can be consumed by other tools.
+			if env.diagsFormat.isMachineReadable() {
+				err = env.writeYAMLEnvironmentDiagnostics(env.esc.stdout, ref.projectName+"/"+ref.envName, yaml, diags)
+				contract.IgnoreError(err)
+			} else if len(diags) != 0 {
+				err = env.writeYAMLEnvironmentDiagnostics(env.esc.stderr, ref.projectName+"/"+ref.envName, yaml, diags)
+				contract.IgnoreError(err)
+			}
+			if client.DiagnosticsHaveErrors(diags) {
+				return errors.New("environment definition has errors")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file,
+		"file", "f", "",
+		"the file that contains the environment definition to check, if any. Pass `-` to read from standard input.")
+
+	cmd.Flags().BoolVar(
+		&strict, "strict", false,
+		"report warnings as errors")
+
+	return cmd
+}
+
+// strictDiagnostics returns a copy of diags in which each warning has been promoted to an error.
+func strictDiagnostics(diags []client.EnvironmentDiagnostic) []client.EnvironmentDiagnostic {
+	if len(diags) == 0 {
+		return diags
+	}
+
+	strict := make([]client.EnvironmentDiagnostic, len(diags))
+	for i, d := range diags {
+		if d.Severity == client.DiagWarning {
+			d.Severity = client.DiagError
+		}
+		strict[i] = d
+	}
+	return strict
+}
@@ -0,0 +1,107 @@
+// Copyright 2026, Pulumi Corporation.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/esc/codegen"
+)
+
+func newEnvCodegenCmd(env *envCommand) *cobra.Command {
+	var lang string
+	var packageName string
+	var typeName string
+	var validator bool
+
+	cmd := &cobra.Command{
+		Use:   "codegen [<org-name>/][<project-name>/]<environment-name>[@<version>]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Generate typed bindings for an environment.",
+		Long: "Generate typed bindings for an environment\n" +
+			"\n" +
+			"This command generates types that describe the values of an environment from the\n" +
+			"environment's schema, and writes them to standard output. Secret values are marked\n" +
+			"in the generated types. The schema is inferred from the environment's current values,\n" +
+			"so the generated types describe the types of those values rather than the values\n" +
+			"themselves: strings are typed as strings rather than literals, and arrays as arrays\n" +
+			"rather than fixed-length tuples.\n" +
+			"\n" +
+			"The supported languages are Go, TypeScript, and Python. The generated Go code includes\n" +
+			"a Load function that decodes the value of an opened environment into the generated\n" +
+			"types. Secret values are wrapped in a type that redacts itself when printed.\n" +
+			"\n" +
+			"For TypeScript, the command generates declarations suitable for a .d.ts file. With\n" +
+			"--validator, the command instead generates a module that validates values at runtime\n" +
+			"using zod.\n" +
+			"\n" +
+			"For Python, the command generates TypedDict classes. The generated code requires\n" +
+			"Python 3.11 or later.\n",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			switch lang {
+			case "go", "typescript", "python":
+				// OK
+			case "":
+				return fmt.Errorf("please specify a language with --lang")
+			default:
+				return fmt.Errorf("unsupported language %q; supported languages are 'go', 'typescript', and 'python'", lang)
+			}
+			if validator && lang != "typescript" {
+				return fmt.Errorf("--validator is only supported for TypeScript")
+			}
+
+			if err := env.esc.getCachedClient(ctx); err != nil {
+				return err
+			}
+
+			ref, args, err := env.getExistingEnvRef(ctx, args)
+			if err != nil {
+				return err
+			}
+			_ = args
+
+			s, err := env.getEnvironmentSchema(ctx, ref)
+			if err != nil {
+				return err
+			}
+
+			switch lang {
+			case "typescript":
+				opts := codegen.TypeScriptOptions{TypeName: typeName, Inferred: true}
+				if validator {
+					return codegen.GenerateTypeScriptValidator(env.esc.stdout, s, opts)
+				}
+				return codegen.GenerateTypeScript(env.esc.stdout, s, opts)
+			case "python":
+				return codegen.GeneratePython(env.esc.stdout, s, codegen.PythonOptions{
+					TypeName: typeName,
+					Inferred: true,
+				})
+			default:
+				return codegen.GenerateGo(env.esc.stdout, s, codegen.GoOptions{
+					PackageName: packageName,
+					TypeName:    typeName,
+				})
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&lang, "lang", "",
+		"the language to generate. Must be 'go', 'typescript', or 'python'")
+	cmd.Flags().StringVar(
+		&packageName, "package", "config",
+		"the name of the generated Go package")
+	cmd.Flags().StringVar(
+		&typeName, "type", "Environment",
+		"the name of the generated type for the environment's values")
+	cmd.Flags().BoolVar(
+		&validator, "validator", false,
+		"generate a zod validator module rather than TypeScript declarations")
+
+	return cmd
+}
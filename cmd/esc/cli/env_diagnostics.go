@@ -0,0 +1,194 @@
+// Copyright 2026, Pulumi Corporation.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/esc/cmd/esc/cli/client"
+	"github.com/pulumi/esc/syntax"
+)
+
+// diagnosticsFormat is the format used to print environment diagnostics. The zero value prints human-readable text.
+type diagnosticsFormat string
+
+const (
+	diagnosticsFormatText  diagnosticsFormat = "text"
+	diagnosticsFormatJSON  diagnosticsFormat = "json"
+	diagnosticsFormatSARIF diagnosticsFormat = "sarif"
+)
+
+func (f *diagnosticsFormat) String() string {
+	if *f == "" {
+		return string(diagnosticsFormatText)
+	}
+	return string(*f)
+}
+
+func (f *diagnosticsFormat) Set(v string) error {
+	switch diagnosticsFormat(v) {
+	case diagnosticsFormatText, diagnosticsFormatJSON, diagnosticsFormatSARIF:
+		*f = diagnosticsFormat(v)
+		return nil
+	default:
+		return fmt.Errorf("must be one of %q, %q, or %q", diagnosticsFormatText, diagnosticsFormatJSON, diagnosticsFormatSARIF)
+	}
+}
+
+func (f *diagnosticsFormat) Type() string {
+	return "format"
+}
+
+// isMachineReadable returns true if the format is a machine-readable format.
+func (f diagnosticsFormat) isMachineReadable() bool {
+	return f == diagnosticsFormatJSON || f == diagnosticsFormatSARIF
+}
+
+// writeMachineDiagnostics writes diagnostics in a machine-readable format. It returns false if the format is not a
+// machine-readable format.
+func (f diagnosticsFormat) writeMachineDiagnostics(out io.Writer, diags []client.EnvironmentDiagnostic) (bool, error) {
+	switch f {
+	case diagnosticsFormatJSON:
+		return true, writeJSONDiagnostics(out, diags)
+	case diagnosticsFormatSARIF:
+		return true, writeSARIFDiagnostics(out, diags)
+	default:
+		return false, nil
+	}
+}
+
+// writeJSONDiagnostics writes diagnostics as a JSON array.
+func writeJSONDiagnostics(out io.Writer, diags []client.EnvironmentDiagnostic) error {
+	if diags == nil {
+		diags = []client.EnvironmentDiagnostic{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diags)
+}
+
+// The types below describe the subset of the Static Analysis Results Interchange Format (SARIF) 2.1.0 used to
+// report diagnostics. See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// writeSARIFDiagnostics writes diagnostics as a SARIF log. Each diagnostic code that appears in the diagnostics is
+// described by a rule.
+func writeSARIFDiagnostics(out io.Writer, diags []client.EnvironmentDiagnostic) error {
+	rules := []sarifRule{}
+	results := make([]sarifResult, len(diags))
+	for i, d := range diags {
+		level := "error"
+		if d.Severity == client.DiagWarning {
+			level = "warning"
+		}
+
+		result := sarifResult{
+			RuleID:  d.Code,
+			Level:   level,
+			Message: sarifMessage{Text: d.Summary},
+		}
+		if d.Range != nil {
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: d.Range.Environment}}
+			if d.Range.Begin.Line != 0 {
+				location.Region = &sarifRegion{
+					StartLine:   d.Range.Begin.Line,
+					StartColumn: d.Range.Begin.Column,
+					EndLine:     d.Range.End.Line,
+					EndColumn:   d.Range.End.Column,
+				}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+		results[i] = result
+
+		if d.Code != "" && !slices.ContainsFunc(rules, func(r sarifRule) bool { return r.ID == d.Code }) {
+			rule := sarifRule{ID: d.Code, DefaultConfiguration: sarifConfiguration{Level: level}}
+			if info, ok := syntax.LookupCode(d.Code); ok {
+				rule.ShortDescription.Text = info.Description
+				rule.DefaultConfiguration.Level = info.Severity
+			}
+			rules = append(rules, rule)
+		}
+	}
+	slices.SortFunc(rules, func(a, b sarifRule) int { return strings.Compare(a.ID, b.ID) })
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "esc",
+				InformationURI: "https://www.pulumi.com/docs/esc",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	})
+}
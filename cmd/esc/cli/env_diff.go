@@ -4,11 +4,15 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/spf13/cobra"
 
+	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/cmd/esc/cli/client"
 	"github.com/pulumi/esc/cmd/esc/cli/style"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
@@ -33,7 +37,11 @@ func newEnvDiffCmd(env *envCommand) *cobra.Command {
 			"The first argument is the base environment for the diff and the second argument\n" +
 			"is the comparison environment. If the environment name portion of the second\n" +
 			"argument is omitted, the name of the base environment is used. If the version portion of\n" +
-			"the second argument is omitted, the 'latest' tag is used.",
+			"the second argument is omitted, the 'latest' tag is used.\n" +
+			"\n" +
+			"With --format patch, the command compares the values of the two environments\n" +
+			"structurally and prints the changes as an RFC 6902 JSON Patch. Secrets are redacted\n" +
+			"unless --show-secrets is set.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
@@ -68,6 +76,8 @@ func newEnvDiffCmd(env *envCommand) *cobra.Command {
 			switch format {
 			case "":
 				// OK
+			case "patch":
+				return diff.diffPatch(ctx, baseRef, compareRef, path, showSecrets)
 			case "detailed", "json", "string":
 				return diff.diffValue(ctx, baseRef, compareRef, path, format, showSecrets)
 			case "dotenv":
@@ -127,7 +137,7 @@ func newEnvDiffCmd(env *envCommand) *cobra.Command {
 
 	cmd.Flags().StringVarP(
 		&format, "format", "f", "",
-		"the output format to use. May be 'dotenv', 'json', 'yaml', 'detailed', or 'shell'")
+		"the output format to use. May be 'dotenv', 'json', 'yaml', 'detailed', 'shell', or 'patch'")
 	cmd.Flags().BoolVar(
 		&showSecrets, "show-secrets", false,
 		"Show static secrets in plaintext rather than ciphertext")
@@ -137,3 +147,50 @@ func newEnvDiffCmd(env *envCommand) *cobra.Command {
 
 	return cmd
 }
+
+// diffPatch writes the structural changes between the values of two environments as a JSON Patch.
+func (get *envGetCommand) diffPatch(
+	ctx context.Context,
+	baseRef environmentRef,
+	compareRef environmentRef,
+	path resource.PropertyPath,
+	showSecrets bool,
+) error {
+	base, err := get.getValue(ctx, baseRef, path, showSecrets)
+	if err != nil {
+		return err
+	}
+	compare, err := get.getValue(ctx, compareRef, path, showSecrets)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(get.env.esc.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(esc.DiffValues(base, compare).Patch(!showSecrets))
+}
+
+// getValue returns the value of an environment at the given path. Missing values are null.
+func (get *envGetCommand) getValue(
+	ctx context.Context,
+	ref environmentRef,
+	path resource.PropertyPath,
+	showSecrets bool,
+) (esc.Value, error) {
+	def, _, _, err := get.env.esc.client.GetEnvironment(ctx, ref.orgName, ref.projectName, ref.envName, ref.version, showSecrets)
+	if err != nil {
+		return esc.Value{}, fmt.Errorf("getting environment definition: %w", err)
+	}
+	env, _, err := get.env.esc.client.CheckYAMLEnvironment(ctx, ref.orgName, def, client.CheckYAMLOption{ShowSecrets: showSecrets})
+	if err != nil {
+		return esc.Value{}, fmt.Errorf("getting environment: %w", err)
+	}
+	if env == nil {
+		return esc.NewValue(map[string]esc.Value{}), nil
+	}
+
+	if v, ok := getEnvValue(esc.NewValue(env.Properties), path); ok {
+		return *v, nil
+	}
+	return esc.Value{}, nil
+}
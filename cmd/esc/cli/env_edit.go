@@ -4,6 +4,7 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -30,6 +31,7 @@ func newEnvEditCmd(env *envCommand) *cobra.Command {
 	var file string
 	var showSecrets bool
 	var draft string
+	var strict bool
 
 	edit := &envEditCommand{env: env}
 
@@ -73,7 +75,7 @@ func newEnvEditCmd(env *envCommand) *cobra.Command {
 					return fmt.Errorf("reading environment definition: %w", err)
 				}
 
-				diags, err := edit.env.esc.updateEnvironment(ctx, ref, draft, yaml, "", "Environment updated.")
+				diags, err := edit.updateEnvironment(ctx, ref, draft, yaml, "", strict)
 				if err != nil {
 					return err
 				}
@@ -128,7 +130,7 @@ func newEnvEditCmd(env *envCommand) *cobra.Command {
 					return nil
 				}
 
-				diags, err := edit.env.esc.updateEnvironment(ctx, ref, draft, newYAML, tag, "Environment updated.")
+				diags, err := edit.updateEnvironment(ctx, ref, draft, newYAML, tag, strict)
 				if err != nil {
 					return err
 				}
@@ -173,9 +175,35 @@ func newEnvEditCmd(env *envCommand) *cobra.Command {
 	// Allow no value to be specified with the flag and create a new change request in that case
 	cmd.Flag("draft").NoOptDefVal = "new"
 
+	cmd.Flags().BoolVar(
+		&strict, "strict", false,
+		"reject the updated definition if it produces any warnings")
+
 	return cmd
 }
 
+// updateEnvironment saves the updated definition for the environment. If strict is true, the definition is first
+// checked in strict mode, and is not saved if the check reports any problems.
+func (edit *envEditCommand) updateEnvironment(
+	ctx context.Context,
+	ref environmentRef,
+	draft string,
+	yaml []byte,
+	tag string,
+	strict bool,
+) ([]client.EnvironmentDiagnostic, error) {
+	if strict {
+		_, diags, err := edit.env.esc.client.CheckYAMLEnvironment(ctx, ref.orgName, yaml)
+		if err != nil {
+			return nil, fmt.Errorf("checking environment definition: %w", err)
+		}
+		if diags = strictDiagnostics(diags); client.DiagnosticsHaveErrors(diags) {
+			return diags, nil
+		}
+	}
+	return edit.env.esc.updateEnvironment(ctx, ref, draft, yaml, tag, "Environment updated.")
+}
+
 func parseEditorCommand(editor string) []string {
 	var command []string
 	for {
@@ -255,7 +283,7 @@ func (edit *envEditCommand) editWithYAMLEditor(
 		var tmp bytes.Buffer
 		fmt.Fprintln(&tmp, "# Diagnostics")
 		fmt.Fprintln(&tmp, "")
-		err := edit.env.writeYAMLEnvironmentDiagnostics(&tmp, envName, yaml, diags)
+		err := edit.env.writeYAMLEnvironmentDiagnosticsText(&tmp, envName, yaml, diags)
 		contract.IgnoreError(err)
 
 		fmt.Fprintln(&details, "---")
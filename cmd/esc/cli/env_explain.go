@@ -0,0 +1,161 @@
+// Copyright 2026, Pulumi Corporation.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/analysis"
+	"github.com/pulumi/esc/cmd/esc/cli/client"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func newEnvExplainCmd(env *envCommand) *cobra.Command {
+	var open bool
+	var duration time.Duration
+	var showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "explain [<org-name>/][<project-name>/]<environment-name>[@<version>] <path>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Explain where a value within an environment comes from.",
+		Long: "Explain where a value within an environment comes from\n" +
+			"\n" +
+			"This command prints the provenance chain of the value at the given Pulumi property\n" +
+			"path: where the value is defined, which definitions in imported environments it\n" +
+			"overrides or merges with, which provider produced it, and why it is secret.\n" +
+			"\n" +
+			"By default, the environment is checked rather than opened, so values produced by\n" +
+			"providers are unknown. Pass --open to open the environment and explain the values\n" +
+			"produced by its providers.\n",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			if err := env.esc.getCachedClient(ctx); err != nil {
+				return err
+			}
+
+			ref, args, err := env.getExistingEnvRef(ctx, args)
+			if err != nil {
+				return err
+			}
+
+			path, err := resource.ParsePropertyPath(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid path: %w", err)
+			}
+
+			var environment *esc.Environment
+			if open {
+				opened, diags, err := env.openEnvironment(ctx, ref, duration, "")
+				if err != nil {
+					return err
+				}
+				if len(diags) != 0 {
+					return env.writePropertyEnvironmentDiagnostics(env.esc.stderr, diags)
+				}
+				environment = opened
+			} else {
+				def, _, _, err := env.esc.client.GetEnvironment(ctx, ref.orgName, ref.projectName, ref.envName, ref.version, showSecrets)
+				if err != nil {
+					return fmt.Errorf("getting environment definition: %w", err)
+				}
+				checked, diags, err := env.esc.client.CheckYAMLEnvironment(ctx, ref.orgName, def, client.CheckYAMLOption{ShowSecrets: showSecrets})
+				if err != nil {
+					return fmt.Errorf("checking environment definition: %w", err)
+				}
+				if client.DiagnosticsHaveErrors(diags) {
+					return env.writeYAMLEnvironmentDiagnostics(env.esc.stderr, ref.envName, def, diags)
+				}
+				environment = checked
+			}
+
+			explanation, ok := analysis.New(*environment, nil).Explain(path)
+			if !ok {
+				return fmt.Errorf("no value at path %v", args[0])
+			}
+			return writeExplanation(env.esc.stdout, ref.envName, explanation, showSecrets)
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&open, "open", false,
+		"open the environment in order to explain values produced by providers")
+	cmd.Flags().DurationVarP(
+		&duration, "lifetime", "l", 2*time.Hour,
+		"the lifetime of the opened environment in the form HhMm (e.g. 2h, 1h30m, 15m). Only used with --open.")
+	cmd.Flags().BoolVar(
+		&showSecrets, "show-secrets", false,
+		"Show secret values in plaintext rather than redacting them")
+
+	return cmd
+}
+
+func writeExplanation(w io.Writer, envName string, explanation *analysis.Explanation, showSecrets bool) error {
+	value, err := json.Marshal(explanation.Value.ToJSON(!showSecrets))
+	if err != nil {
+		return fmt.Errorf("encoding value: %w", err)
+	}
+	fmt.Fprintf(w, "value: %s\n", value)
+	if explanation.Value.Secret {
+		fmt.Fprintln(w, "secret: yes")
+	} else {
+		fmt.Fprintln(w, "secret: no")
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "provenance:")
+	for i, layer := range explanation.Layers {
+		fmt.Fprintf(w, "  %v. %v\n", i+1, explainRange(envName, layer.Range))
+		switch {
+		case layer.Provider != "":
+			fmt.Fprintf(w, "     produced by %v (provider %q)\n", layer.Builtin, layer.Provider)
+		case layer.Builtin != "":
+			fmt.Fprintf(w, "     produced by %v\n", layer.Builtin)
+		}
+		if layer.Description != "" {
+			fmt.Fprintf(w, "     %v\n", layer.Description)
+		}
+		for _, ref := range layer.References {
+			fmt.Fprintf(w, "     references %v (%v)\n", ref.Path, explainRange(envName, ref.Range))
+		}
+		if layer.Secret {
+			switch layer.SecretSource {
+			case analysis.SecretDeclared:
+				fmt.Fprintf(w, "     secret: declared with fn::secret\n")
+			case analysis.SecretProvider:
+				fmt.Fprintf(w, "     secret: marked secret by provider %q\n", layer.Provider)
+			case analysis.SecretInherited:
+				fmt.Fprintf(w, "     secret: inherited from a referenced secret\n")
+			default:
+				fmt.Fprintf(w, "     secret: yes\n")
+			}
+		}
+		if i+1 < len(explanation.Layers) {
+			base := explainRange(envName, explanation.Layers[i+1].Range)
+			switch layer.Relation {
+			case analysis.RelationMerges:
+				fmt.Fprintf(w, "     merges with %v\n", base)
+			default:
+				fmt.Fprintf(w, "     overrides %v\n", base)
+			}
+		}
+	}
+	return nil
+}
+
+// explainRange formats the beginning of a range as `environment:line:column`. Ranges within the explained
+// environment's own definition are attributed to the environment.
+func explainRange(envName string, rng esc.Range) string {
+	env := rng.Environment
+	if env == "<yaml>" {
+		env = envName
+	}
+	return fmt.Sprintf("%v:%v:%v", env, rng.Begin.Line, rng.Begin.Column)
+}
@@ -0,0 +1,179 @@
+// Copyright 2026, Pulumi Corporation.
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/esc/format"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+func newEnvFmtCmd(env *envCommand) *cobra.Command {
+	var files []string
+	var write bool
+	var check bool
+	var sortKeys bool
+
+	cmd := &cobra.Command{
+		Use:   "fmt [<org-name>/][<project-name>/]<environment-name>[@<version>]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Format an environment definition",
+		Long: "Format an environment definition\n" +
+			"\n" +
+			"This command rewrites an environment definition in canonical form: YAML is written\n" +
+			"in block style with two-space indentation and minimal quoting, long-form calls to\n" +
+			"fn::open and fn::rotate are rewritten to their short forms (e.g. fn::open::<provider>),\n" +
+			"and comments are preserved. JSON definitions are written as indented JSON. If\n" +
+			"--sort-keys is set, the keys of each object within the environment's values are\n" +
+			"sorted by name.\n" +
+			"\n" +
+			"By default, the formatted definition of the named environment is written to stdout.\n" +
+			"If one or more --file flags are given, the definitions in those files are formatted\n" +
+			"instead. Pass `-` to read a definition from standard input.\n" +
+			"\n" +
+			"If --write is set, the formatted definition replaces the original: files are\n" +
+			"rewritten in place, and the named environment is updated. If --check is set, nothing\n" +
+			"is written. Instead, the names of any definitions that are not formatted are printed\n" +
+			"and the command fails, which makes --check suitable for use in CI.\n",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			if write && check {
+				return errors.New("--write and --check may not be used together")
+			}
+
+			opts := format.Options{SortKeys: sortKeys}
+
+			if len(files) != 0 {
+				if len(args) != 0 {
+					return errors.New("an environment name may not be given with --file")
+				}
+
+				unformatted := 0
+				for _, file := range files {
+					var source []byte
+					var err error
+					if file == "-" {
+						if write {
+							return errors.New("--write may not be used with standard input")
+						}
+						source, err = io.ReadAll(env.esc.stdin)
+					} else {
+						source, err = fs.ReadFile(env.esc.fs, file)
+					}
+					if err != nil {
+						return fmt.Errorf("reading environment definition: %w", err)
+					}
+
+					formatted, ok := env.formatDefinition(file, source, opts)
+					if !ok {
+						return errors.New("environment definition has errors")
+					}
+
+					switch {
+					case check:
+						if !bytes.Equal(source, formatted) {
+							fmt.Fprintln(env.esc.stdout, file)
+							unformatted++
+						}
+					case write:
+						if !bytes.Equal(source, formatted) {
+							info, err := fs.Stat(env.esc.fs, file)
+							if err != nil {
+								return fmt.Errorf("writing %v: %w", file, err)
+							}
+							if err := env.esc.fs.LockedWrite(file, bytes.NewReader(formatted), info.Mode().Perm()); err != nil {
+								return fmt.Errorf("writing %v: %w", file, err)
+							}
+						}
+					default:
+						_, err = env.esc.stdout.Write(formatted)
+						contract.IgnoreError(err)
+					}
+				}
+				if unformatted != 0 {
+					return fmt.Errorf("%v of %v definitions are not formatted", unformatted, len(files))
+				}
+				return nil
+			}
+
+			if err := env.esc.getCachedClient(ctx); err != nil {
+				return err
+			}
+
+			ref, _, err := env.getExistingEnvRef(ctx, args)
+			if err != nil {
+				return err
+			}
+			if write && ref.version != "" {
+				return errors.New("the fmt command does not accept versions with --write")
+			}
+
+			source, tag, _, err := env.esc.client.GetEnvironment(ctx, ref.orgName, ref.projectName, ref.envName, ref.version, false)
+			if err != nil {
+				return fmt.Errorf("getting environment definition: %w", err)
+			}
+
+			name := ref.projectName + "/" + ref.envName
+			formatted, ok := env.formatDefinition(name, source, opts)
+			if !ok {
+				return errors.New("environment definition has errors")
+			}
+
+			switch {
+			case check:
+				if !bytes.Equal(source, formatted) {
+					fmt.Fprintln(env.esc.stdout, ref.String())
+					return fmt.Errorf("environment %v is not formatted", ref.String())
+				}
+			case write:
+				if !bytes.Equal(source, formatted) {
+					diags, err := env.esc.updateEnvironment(ctx, ref, "", formatted, tag, "")
+					if err != nil {
+						return err
+					}
+					if len(diags) != 0 {
+						return env.writeYAMLEnvironmentDiagnostics(env.esc.stderr, name, formatted, diags)
+					}
+				}
+			default:
+				_, err = env.esc.stdout.Write(formatted)
+				contract.IgnoreError(err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&files,
+		"file", "f", nil,
+		"a file that contains an environment definition to format. May be repeated. Pass `-` to read from standard input.")
+	cmd.Flags().BoolVarP(
+		&write, "write", "w", false,
+		"replace the original definitions with their formatted forms")
+	cmd.Flags().BoolVar(
+		&check, "check", false,
+		"list the definitions that are not formatted and fail if there are any")
+	cmd.Flags().BoolVar(
+		&sortKeys, "sort-keys", false,
+		"sort the keys of each object within the environment's values")
+
+	return cmd
+}
+
+// formatDefinition formats an environment definition. Any diagnostics are written to stderr. The boolean result is
+// false if the definition could not be formatted.
+func (cmd *envCommand) formatDefinition(name string, source []byte, opts format.Options) ([]byte, bool) {
+	formatted, diags := format.Source(name, source, opts)
+	if len(diags) != 0 {
+		err := cmd.writeYAMLEnvironmentDiagnostics(cmd.esc.stderr, name, source, clientDiagnostics(diags))
+		contract.IgnoreError(err)
+	}
+	return formatted, !diags.HasErrors()
+}
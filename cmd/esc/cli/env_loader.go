@@ -0,0 +1,167 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/esc/cmd/esc/cli/client"
+	"github.com/pulumi/esc/eval"
+)
+
+// A serviceEnvironmentLoader loads imported environment definitions from Pulumi Cloud. Definitions are fetched with
+// their secrets decrypted by the service unless encrypted is set.
+type serviceEnvironmentLoader struct {
+	client    client.Client
+	orgName   string
+	encrypted bool
+}
+
+var _ eval.VersionedEnvironmentLoader = (*serviceEnvironmentLoader)(nil)
+
+// LoadEnvironment loads the latest revision of the named environment.
+func (l *serviceEnvironmentLoader) LoadEnvironment(ctx context.Context, name string) ([]byte, eval.Decrypter, error) {
+	return l.LoadEnvironmentVersion(ctx, name, "")
+}
+
+// LoadEnvironmentVersion loads the given version of the named environment. The version may be a revision number or
+// a revision tag. Tags are resolved to revision numbers prior to fetching the definition. Missing environments and
+// revisions are reported as eval.ErrEnvironmentNotFound.
+func (l *serviceEnvironmentLoader) LoadEnvironmentVersion(
+	ctx context.Context,
+	name string,
+	version string,
+) ([]byte, eval.Decrypter, error) {
+	projectName, envName := client.DefaultProject, name
+	if before, after, ok := strings.Cut(name, "/"); ok {
+		projectName, envName = before, after
+	}
+
+	if version != "" {
+		revision, err := l.resolveRevision(ctx, projectName, envName, version)
+		if err != nil {
+			return nil, nil, err
+		}
+		version = strconv.Itoa(revision)
+	}
+
+	yaml, _, _, err := l.client.GetEnvironment(ctx, l.orgName, projectName, envName, version, !l.encrypted)
+	if err != nil {
+		if client.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("%v: %w", name, eval.ErrEnvironmentNotFound)
+		}
+		return nil, nil, err
+	}
+	if l.encrypted {
+		return yaml, encryptedDecrypter{}, nil
+	}
+	return yaml, decryptedDecrypter{}, nil
+}
+
+// resolveRevision resolves a revision number or revision tag to a revision number.
+func (l *serviceEnvironmentLoader) resolveRevision(ctx context.Context, projectName, envName, version string) (int, error) {
+	if n, err := strconv.Atoi(version); err == nil {
+		rev, err := l.client.GetEnvironmentRevision(ctx, l.orgName, projectName, envName, n)
+		if err != nil {
+			return 0, fmt.Errorf("resolving %v/%v@%v: %w", projectName, envName, version, err)
+		}
+		if rev == nil || rev.Number != n {
+			return 0, fmt.Errorf("resolving %v/%v@%v: %w", projectName, envName, version, eval.ErrEnvironmentNotFound)
+		}
+		if rev.Retracted != nil {
+			return 0, fmt.Errorf("resolving %v/%v@%v: revision has been retracted", projectName, envName, version)
+		}
+		return rev.Number, nil
+	}
+
+	tag, err := l.client.GetEnvironmentRevisionTag(ctx, l.orgName, projectName, envName, version)
+	if err != nil {
+		if client.IsNotFound(err) {
+			err = eval.ErrEnvironmentNotFound
+		}
+		return 0, fmt.Errorf("resolving %v/%v@%v: %w", projectName, envName, version, err)
+	}
+	return tag.Revision, nil
+}
+
+// decryptedDecrypter is the decrypter for definitions whose secrets have already been decrypted by the service.
+type decryptedDecrypter struct{}
+
+func (decryptedDecrypter) Decrypt(_ context.Context, _ []byte) ([]byte, error) {
+	return nil, errors.New("unexpected ciphertext in decrypted environment definition")
+}
+
+// encryptedDecrypter is the decrypter for definitions that are fetched without decrypting their secrets. Such
+// definitions are only evaluated without decrypting secrets.
+type encryptedDecrypter struct{}
+
+func (encryptedDecrypter) Decrypt(_ context.Context, _ []byte) ([]byte, error) {
+	return nil, errors.New("secrets in this environment definition are not decrypted")
+}
+
+// A localEnvironmentLoader loads imported environment definitions from a local directory. The definition of the
+// environment `name` is read from `<dir>/<name>.yaml`, and the definition of the environment `name@version` is read
+// from `<dir>/<name>@<version>.yaml`. Names of the form `project/env` map to subdirectories. Like all paths into an
+// fs.FS, dir is slash-separated.
+//
+// If a definition is not present in the directory and fallback is non-nil, the definition is loaded using fallback.
+// Otherwise, the missing definition is reported as eval.ErrEnvironmentNotFound.
+type localEnvironmentLoader struct {
+	fs        fs.FS
+	dir       string
+	decrypter eval.Decrypter
+	fallback  func(ctx context.Context) (eval.VersionedEnvironmentLoader, error)
+}
+
+var _ eval.VersionedEnvironmentLoader = (*localEnvironmentLoader)(nil)
+
+// LoadEnvironment loads the named environment.
+func (l *localEnvironmentLoader) LoadEnvironment(ctx context.Context, name string) ([]byte, eval.Decrypter, error) {
+	return l.LoadEnvironmentVersion(ctx, name, "")
+}
+
+// LoadEnvironmentVersion loads the given version of the named environment.
+func (l *localEnvironmentLoader) LoadEnvironmentVersion(
+	ctx context.Context,
+	name string,
+	version string,
+) ([]byte, eval.Decrypter, error) {
+	filename := name
+	if version != "" {
+		filename += "@" + version
+	}
+
+	yaml, err := fs.ReadFile(l.fs, path.Join(l.dir, filename+".yaml"))
+	switch {
+	case err == nil:
+		return yaml, l.decrypter, nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, nil, err
+	case l.fallback == nil:
+		return nil, nil, fmt.Errorf("%v: %w", filename, eval.ErrEnvironmentNotFound)
+	}
+
+	fallback, err := l.fallback(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading %v from Pulumi Cloud: %w", filename, err)
+	}
+	return fallback.LoadEnvironmentVersion(ctx, name, version)
+}
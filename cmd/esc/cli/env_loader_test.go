@@ -0,0 +1,111 @@
+// Copyright 2026, Pulumi Corporation.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pulumi/esc/eval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceEnvironmentLoader(t *testing.T) {
+	env := &testEnvironment{
+		revisions: []*testEnvironmentRevision{
+			{number: 1, yaml: []byte("values: {v: 1}\n")},
+			{number: 2, yaml: []byte("values: {v: 2}\n"), retracted: &testEnvironmentRetract{replacement: 3}},
+			{number: 3, yaml: []byte("values: {v: 3}\n")},
+			{number: 4, yaml: []byte("values: {v: 4}\n")},
+		},
+		revisionTags: map[string]int{"latest": 4, "stable": 3},
+	}
+	loader := &serviceEnvironmentLoader{
+		client: &testPulumiClient{
+			environments: map[string]*testEnvironment{"org/project/env": env},
+		},
+		orgName: "org",
+	}
+
+	load := func(name, version string) (string, error) {
+		yaml, _, err := loader.LoadEnvironmentVersion(context.Background(), name, version)
+		return string(yaml), err
+	}
+
+	t.Run("latest", func(t *testing.T) {
+		yaml, err := load("project/env", "")
+		require.NoError(t, err)
+		assert.Equal(t, "values: {v: 4}\n", yaml)
+	})
+
+	t.Run("revision", func(t *testing.T) {
+		yaml, err := load("project/env", "1")
+		require.NoError(t, err)
+		assert.Equal(t, "values: {v: 1}\n", yaml)
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		yaml, err := load("project/env", "stable")
+		require.NoError(t, err)
+		assert.Equal(t, "values: {v: 3}\n", yaml)
+	})
+
+	t.Run("retracted", func(t *testing.T) {
+		_, err := load("project/env", "2")
+		assert.ErrorContains(t, err, "revision has been retracted")
+	})
+
+	t.Run("missing tag", func(t *testing.T) {
+		_, err := load("project/env", "nope")
+		assert.ErrorIs(t, err, eval.ErrEnvironmentNotFound)
+	})
+}
+
+func TestLocalEnvironmentLoader(t *testing.T) {
+	fs := testFS{MapFS: fstest.MapFS{
+		"envs/a.yaml":         {Data: []byte("values: {a: 1}\n")},
+		"envs/a@v1.yaml":      {Data: []byte("values: {a: 0}\n")},
+		"envs/project/b.yaml": {Data: []byte("values: {b: 1}\n")},
+	}}
+
+	fallbackCalls := 0
+	loader := &localEnvironmentLoader{
+		fs:        fs,
+		dir:       "envs",
+		decrypter: noLocalDecrypter{},
+		fallback: func(ctx context.Context) (eval.VersionedEnvironmentLoader, error) {
+			fallbackCalls++
+			return nil, errors.New("offline")
+		},
+	}
+
+	load := func(name, version string) (string, error) {
+		yaml, _, err := loader.LoadEnvironmentVersion(context.Background(), name, version)
+		return string(yaml), err
+	}
+
+	yaml, err := load("a", "")
+	require.NoError(t, err)
+	assert.Equal(t, "values: {a: 1}\n", yaml)
+
+	yaml, err = load("a", "v1")
+	require.NoError(t, err)
+	assert.Equal(t, "values: {a: 0}\n", yaml)
+
+	yaml, err = load("project/b", "")
+	require.NoError(t, err)
+	assert.Equal(t, "values: {b: 1}\n", yaml)
+	assert.Equal(t, 0, fallbackCalls)
+
+	_, err = load("c", "")
+	assert.ErrorContains(t, err, "offline")
+	assert.Equal(t, 1, fallbackCalls)
+
+	loader.fallback = nil
+	_, err = load("c", "")
+	assert.ErrorIs(t, err, eval.ErrEnvironmentNotFound)
+	assert.Equal(t, 1, fallbackCalls)
+}
@@ -3,6 +3,7 @@
 package cli
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net/http"
@@ -88,25 +89,47 @@ func newEnvRmCmd(env *envCommand) *cobra.Command {
 				return fmt.Errorf("getting environment definition: %w", err)
 			}
 
-			var docNode yaml.Node
-			if err := yaml.Unmarshal(def, &docNode); err != nil {
-				return fmt.Errorf("unmarshaling environment definition: %w", err)
-			}
-			if docNode.Kind != yaml.DocumentNode {
-				return nil
-			}
-			valuesNode, ok := encoding.YAMLSyntax{Node: &docNode}.Get(resource.PropertyPath{"values"})
-			if !ok {
-				return nil
-			}
-			err = encoding.YAMLSyntax{Node: valuesNode}.Delete(nil, path)
-			if err != nil {
-				return err
-			}
+			var newYAML []byte
+			if encoding.IsJSON(def) {
+				docNode, diags := encoding.ParseJSON("", def)
+				if diags.HasErrors() {
+					return fmt.Errorf("unmarshaling environment definition: %w", diags)
+				}
+				valuesNode, ok := encoding.JSONSyntax{JSONNode: docNode}.Get(resource.PropertyPath{"values"})
+				if !ok {
+					return nil
+				}
+				err = encoding.JSONSyntax{JSONNode: valuesNode}.Delete(nil, path)
+				if err != nil {
+					return err
+				}
 
-			newYAML, err := yaml.Marshal(docNode.Content[0])
-			if err != nil {
-				return fmt.Errorf("marshaling definition: %w", err)
+				var b bytes.Buffer
+				if err := encoding.WriteJSON(&b, docNode); err != nil {
+					return fmt.Errorf("marshaling definition: %w", err)
+				}
+				newYAML = b.Bytes()
+			} else {
+				var docNode yaml.Node
+				if err := yaml.Unmarshal(def, &docNode); err != nil {
+					return fmt.Errorf("unmarshaling environment definition: %w", err)
+				}
+				if docNode.Kind != yaml.DocumentNode {
+					return nil
+				}
+				valuesNode, ok := encoding.YAMLSyntax{Node: &docNode}.Get(resource.PropertyPath{"values"})
+				if !ok {
+					return nil
+				}
+				err = encoding.YAMLSyntax{Node: valuesNode}.Delete(nil, path)
+				if err != nil {
+					return err
+				}
+
+				newYAML, err = yaml.Marshal(docNode.Content[0])
+				if err != nil {
+					return fmt.Errorf("marshaling definition: %w", err)
+				}
 			}
 
 			diags, err := env.esc.client.UpdateEnvironmentWithProject(ctx, ref.orgName, ref.projectName, ref.envName, newYAML, tag)
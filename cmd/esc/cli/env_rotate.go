@@ -3,22 +3,37 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/pulumi/esc"
 	"github.com/pulumi/esc/cmd/esc/cli/client"
+	"github.com/pulumi/esc/eval"
+	"github.com/pulumi/esc/schema"
+	"github.com/pulumi/esc/syntax"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/spf13/cobra"
 )
 
 func newEnvRotateCmd(envcmd *envCommand) *cobra.Command {
+	var dryRun bool
+
 	cmd := &cobra.Command{
 		Use:   "rotate [<org-name>/][<project-name>/]<environment-name> [path(s) to rotate]",
 		Short: "Rotate secrets in an environment",
 		Long: "Rotate secrets in an environment\n" +
 			"\n" +
-			"Optionally accepts any number of Property Paths as additional arguments. If given any paths, will only rotate secrets at those paths.\n",
+			"Optionally accepts any number of Property Paths as additional arguments. If given any paths, will only rotate secrets at those paths.\n" +
+			"\n" +
+			"If --dry-run is set, the secrets are not rotated. Instead, the command lists the secrets that would\n" +
+			"be rotated, the rotators that would be invoked, the state that would be written back to the\n" +
+			"environment, and any problems that would block the rotation.\n",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
@@ -44,6 +59,10 @@ func newEnvRotateCmd(envcmd *envCommand) *cobra.Command {
 				rotationPaths = append(rotationPaths, arg)
 			}
 
+			if dryRun {
+				return envcmd.planRotation(ctx, ref, rotationPaths)
+			}
+
 			resp, diags, err := envcmd.esc.client.RotateEnvironment(ctx, ref.orgName, ref.projectName, ref.envName, rotationPaths)
 			if err != nil {
 				return err
@@ -92,5 +111,200 @@ func newEnvRotateCmd(envcmd *envCommand) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the rotations that would be performed without performing them")
+
 	return cmd
 }
+
+// planRotation describes the rotations that rotating an environment would perform. The plan is built locally: the
+// environment is checked by the service, then evaluated in-process without invoking any providers or rotators. The
+// definition and its imports are fetched with their secrets encrypted, and secrets are never decrypted.
+func (envcmd *envCommand) planRotation(ctx context.Context, ref environmentRef, rotationPaths []string) error {
+	paths := make([]resource.PropertyPath, len(rotationPaths))
+	for i, p := range rotationPaths {
+		path, err := resource.ParsePropertyPath(p)
+		if err != nil {
+			return fmt.Errorf("'%s' is an invalid property path: %w", p, err)
+		}
+		paths[i] = path
+	}
+
+	yaml, _, _, err := envcmd.esc.client.GetEnvironment(ctx, ref.orgName, ref.projectName, ref.envName, "", false)
+	if err != nil {
+		return fmt.Errorf("getting environment definition: %w", err)
+	}
+
+	name := ref.projectName + "/" + ref.envName
+
+	// Errors in the environment block all rotations. This includes calls to rotators that are unknown to the service and
+	// rotator inputs that do not match the rotators' schemas.
+	checked, diags, err := envcmd.esc.client.CheckYAMLEnvironment(ctx, ref.orgName, yaml)
+	if err != nil {
+		return fmt.Errorf("checking environment definition: %w", err)
+	}
+	if len(diags) != 0 {
+		err := envcmd.writeYAMLEnvironmentDiagnostics(envcmd.esc.stderr, name, yaml, diags)
+		contract.IgnoreError(err)
+	}
+	if client.DiagnosticsHaveErrors(diags) {
+		return errors.New("rotation would be blocked")
+	}
+
+	plan, planDiags, err := envcmd.planRotationLocally(ctx, ref, name, yaml, newCheckedRotators(checked), paths)
+	if err != nil {
+		return err
+	}
+	if planDiags.HasErrors() {
+		err := envcmd.writeYAMLEnvironmentDiagnostics(envcmd.esc.stderr, name, yaml, clientDiagnostics(planDiags))
+		contract.IgnoreError(err)
+		return errors.New("rotation would be blocked")
+	}
+
+	if len(plan) == 0 {
+		fmt.Fprintln(envcmd.esc.stdout, "No secrets would be rotated.")
+		return nil
+	}
+	if err := envcmd.writeRotationPlan(plan); err != nil {
+		return err
+	}
+
+	for _, rotation := range plan {
+		if len(rotation.Blockers) != 0 {
+			return errors.New("rotation would be blocked")
+		}
+	}
+	return nil
+}
+
+// planRotationLocally evaluates an environment definition in-process in order to plan its rotations. Imports are
+// loaded from the service with their secrets encrypted. Providers and rotators are never invoked.
+func (envcmd *envCommand) planRotationLocally(
+	ctx context.Context,
+	ref environmentRef,
+	name string,
+	yaml []byte,
+	providers eval.ProviderLoader,
+	paths []resource.PropertyPath,
+) (eval.RotationPlan, syntax.Diagnostics, error) {
+	decl, diags, err := eval.LoadYAMLBytes(name, yaml)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading environment definition: %w", err)
+	}
+	if diags.HasErrors() {
+		return nil, diags, nil
+	}
+
+	execContext, err := esc.NewExecContext(nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating execution context: %w", err)
+	}
+
+	environments := &serviceEnvironmentLoader{client: envcmd.esc.client, orgName: ref.orgName, encrypted: true}
+	plan, planDiags := eval.PlanRotateEnvironment(ctx, name, decl, encryptedDecrypter{}, providers, environments, execContext, paths)
+	diags.Extend(planDiags...)
+
+	slices.SortFunc(plan, func(a, b *eval.PlannedRotation) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return plan, diags, nil
+}
+
+func (envcmd *envCommand) writeRotationPlan(plan eval.RotationPlan) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The following secrets would be rotated:\n")
+	for _, rotation := range plan {
+		fmt.Fprintf(&b, "\n%vPath: %s%v\n", colors.Bold, strings.TrimPrefix(rotation.Path, "values."), colors.Reset)
+		fmt.Fprintf(&b, "  Rotator: %s\n", rotation.Rotator)
+
+		state, err := json.Marshal(rotation.Patch.Replacement.ToJSON(true))
+		if err != nil {
+			return fmt.Errorf("encoding state: %w", err)
+		}
+		fmt.Fprintf(&b, "  Patch:   %s = %s\n", rotation.Patch.DocPath, state)
+
+		for _, blocker := range rotation.Blockers {
+			fmt.Fprintf(&b, "  %vBlocked: %s%v\n", colors.SpecError, blocker, colors.Reset)
+		}
+	}
+
+	fmt.Fprint(envcmd.esc.stdout, envcmd.esc.colors.Colorize(b.String()))
+	return nil
+}
+
+// checkedRotators is the provider registry used to plan rotations. The schemas of the rotators called by an
+// environment are taken from the environment as checked by the service, so that planned rotations are validated
+// against the rotators' real schemas. Rotators that are not called by the checked environment are only called by its
+// imports, which are never rotated, and are replaced by stand-ins, as are all providers.
+type checkedRotators struct {
+	uncheckedProviders
+
+	rotators map[string]checkedRotator
+}
+
+// newCheckedRotators collects the schemas of the rotators called by a checked environment.
+func newCheckedRotators(checked *esc.Environment) checkedRotators {
+	p := checkedRotators{rotators: map[string]checkedRotator{}}
+	if checked != nil {
+		for _, x := range checked.Exprs {
+			p.collect(x)
+		}
+	}
+	return p
+}
+
+func (p checkedRotators) collect(x esc.Expr) {
+	for _, x := range x.List {
+		p.collect(x)
+	}
+	for _, x := range x.Object {
+		p.collect(x)
+	}
+	if x.Builtin == nil {
+		return
+	}
+	p.collect(x.Builtin.Arg)
+
+	name, ok := strings.CutPrefix(x.Builtin.Name, "fn::rotate::")
+	if !ok {
+		if x.Builtin.Name != "fn::rotate" {
+			return
+		}
+		name, _ = x.Builtin.Arg.Object["provider"].Literal.(string)
+	}
+
+	argSchema := x.Builtin.ArgSchema
+	if name == "" || argSchema == nil || argSchema.Properties["inputs"] == nil || argSchema.Properties["state"] == nil {
+		return
+	}
+	p.rotators[name] = checkedRotator{
+		inputs: argSchema.Properties["inputs"],
+		state:  nonNullSchema(argSchema.Properties["state"]),
+		output: valueOrDefault(x.Schema, schema.Always().Schema()),
+	}
+}
+
+func (p checkedRotators) LoadRotator(ctx context.Context, name string) (esc.Rotator, error) {
+	if rotator, ok := p.rotators[name]; ok {
+		return rotator, nil
+	}
+	return p.uncheckedProviders.LoadRotator(ctx, name)
+}
+
+// nonNullSchema removes the null alternative that the evaluator adds to the schema of a rotator's state.
+func nonNullSchema(s *schema.Schema) *schema.Schema {
+	if len(s.OneOf) == 2 && s.OneOf[1].Type == "null" {
+		return s.OneOf[0]
+	}
+	return s
+}
+
+// checkedRotator is a rotator whose schemas were reported by the service. It is never opened.
+type checkedRotator struct {
+	uncheckedRotator
+
+	inputs, state, output *schema.Schema
+}
+
+func (r checkedRotator) Schema() (*schema.Schema, *schema.Schema, *schema.Schema) {
+	return r.inputs, r.state, r.output
+}
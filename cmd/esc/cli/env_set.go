@@ -3,6 +3,7 @@
 package cli
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"regexp"
@@ -146,38 +147,46 @@ func newEnvSetCmd(env *envCommand) *cobra.Command {
 				}
 			}
 
-			var docNode yaml.Node
-			if err := yaml.Unmarshal(def, &docNode); err != nil {
-				return fmt.Errorf("unmarshaling environment definition: %w", err)
-			}
-			if docNode.Kind != yaml.DocumentNode {
-				docNode = yaml.Node{
-					Kind:    yaml.DocumentNode,
-					Content: []*yaml.Node{{}},
+			var newYAML []byte
+			if encoding.IsJSON(def) {
+				newYAML, err = setJSONValue(def, path, yamlValue)
+				if err != nil {
+					return err
 				}
-			}
-
-			if path[0] == "imports" {
-				_, err = encoding.YAMLSyntax{Node: &docNode}.Set(nil, path, yamlValue)
 			} else {
-				valuesNode, ok := encoding.YAMLSyntax{Node: &docNode}.Get(resource.PropertyPath{"values"})
-				if !ok {
-					valuesNode, err = encoding.YAMLSyntax{Node: &docNode}.Set(nil, resource.PropertyPath{"values"}, yaml.Node{
-						Kind: yaml.MappingNode,
-					})
-					if err != nil {
-						return fmt.Errorf("internal error: %w", err)
+				var docNode yaml.Node
+				if err := yaml.Unmarshal(def, &docNode); err != nil {
+					return fmt.Errorf("unmarshaling environment definition: %w", err)
+				}
+				if docNode.Kind != yaml.DocumentNode {
+					docNode = yaml.Node{
+						Kind:    yaml.DocumentNode,
+						Content: []*yaml.Node{{}},
 					}
 				}
-				_, err = encoding.YAMLSyntax{Node: valuesNode}.Set(nil, path, yamlValue)
-			}
-			if err != nil {
-				return err
-			}
 
-			newYAML, err := yaml.Marshal(docNode.Content[0])
-			if err != nil {
-				return fmt.Errorf("marshaling definition: %w", err)
+				if path[0] == "imports" {
+					_, err = encoding.YAMLSyntax{Node: &docNode}.Set(nil, path, yamlValue)
+				} else {
+					valuesNode, ok := encoding.YAMLSyntax{Node: &docNode}.Get(resource.PropertyPath{"values"})
+					if !ok {
+						valuesNode, err = encoding.YAMLSyntax{Node: &docNode}.Set(nil, resource.PropertyPath{"values"}, yaml.Node{
+							Kind: yaml.MappingNode,
+						})
+						if err != nil {
+							return fmt.Errorf("internal error: %w", err)
+						}
+					}
+					_, err = encoding.YAMLSyntax{Node: valuesNode}.Set(nil, path, yamlValue)
+				}
+				if err != nil {
+					return err
+				}
+
+				newYAML, err = yaml.Marshal(docNode.Content[0])
+				if err != nil {
+					return fmt.Errorf("marshaling definition: %w", err)
+				}
 			}
 
 			diags, err := env.esc.updateEnvironment(ctx, ref, draft, newYAML, tag, "")
@@ -225,6 +234,49 @@ const (
 	entropyPerCharThreshold = 3.0
 )
 
+// setJSONValue sets the value at the given path in a JSON environment definition and returns the updated definition.
+// The definition is kept in JSON form.
+func setJSONValue(def []byte, path resource.PropertyPath, value yaml.Node) ([]byte, error) {
+	docNode, diags := encoding.ParseJSON("", def)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("unmarshaling environment definition: %w", diags)
+	}
+
+	syn, diags := encoding.UnmarshalYAML("", &value, nil)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("internal error: %w", diags)
+	}
+	jsonValue, diags := encoding.MarshalJSONNode(syn)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("internal error: %w", diags)
+	}
+
+	var err error
+	if path[0] == "imports" {
+		_, err = encoding.JSONSyntax{JSONNode: docNode}.Set(nil, path, *jsonValue)
+	} else {
+		valuesNode, ok := encoding.JSONSyntax{JSONNode: docNode}.Get(resource.PropertyPath{"values"})
+		if !ok {
+			valuesNode, err = encoding.JSONSyntax{JSONNode: docNode}.Set(nil, resource.PropertyPath{"values"}, encoding.JSONNode{
+				Kind: encoding.JSONObject,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("internal error: %w", err)
+			}
+		}
+		_, err = encoding.JSONSyntax{JSONNode: valuesNode}.Set(nil, path, *jsonValue)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := encoding.WriteJSON(&b, docNode); err != nil {
+		return nil, fmt.Errorf("marshaling definition: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
 // looksLikeSecret returns true if a configuration value "looks" like a secret. This is always going to be a heuristic
 // that suffers from false positives, but is better (a) than our prior approach of unconditionally printing a warning
 // for all plaintext values, and (b)  to be paranoid about such things. Inspired by the gas linter and securego project.
@@ -70,6 +70,7 @@ func newEnvVersionCmd(env *envCommand) *cobra.Command {
 	cmd.AddCommand(newEnvVersionHistoryCmd(env))
 	cmd.AddCommand(newEnvVersionRetractCmd(env))
 	cmd.AddCommand(newEnvVersionRollbackCmd(env))
+	cmd.AddCommand(newEnvVersionDiffCmd(env))
 
 	cmd.Flags().BoolVar(&utc, "utc", false, "display times in UTC")
 
@@ -0,0 +1,171 @@
+// Copyright 2026, Pulumi Corporation.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/cmd/esc/cli/client"
+	"github.com/pulumi/esc/schema"
+)
+
+func newEnvVersionDiffCmd(env *envCommand) *cobra.Command {
+	var schemaOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "diff [<org-name>/][<project-name>/]<environment-name>@<version> [[[<org-name>/][<project-name>/]<environment-name>]@<version>]",
+		Args:  cobra.RangeArgs(1, 2),
+		Short: "Show schema changes between versions.",
+		Long: "Show schema changes between versions\n" +
+			"\n" +
+			"With --schema, this command compares the schemas of two versions of an environment\n" +
+			"and classifies each change as breaking or non-breaking. Removed properties, changed\n" +
+			"or narrowed types, newly-required properties, and secrets that became plaintext are\n" +
+			"breaking changes. The command fails if any breaking changes are found.\n" +
+			"\n" +
+			"The first argument is the base version for the diff and the second argument is the\n" +
+			"comparison version. If the environment name portion of the second argument is omitted,\n" +
+			"the name of the base environment is used. If the second argument is omitted, the\n" +
+			"'latest' tag is used.\n" +
+			"\n" +
+			"To compare the values of two versions, use `esc env diff`.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			if !schemaOnly {
+				return errors.New("only schema diffs are supported; pass --schema, or use `esc env diff` to compare values")
+			}
+
+			if err := env.esc.getCachedClient(ctx); err != nil {
+				return err
+			}
+
+			baseRef, args, err := env.getExistingEnvRef(ctx, args)
+			if err != nil {
+				return err
+			}
+			if baseRef.version == "" {
+				baseRef.version = "latest"
+			}
+
+			compareRef := environmentRef{baseRef.orgName, baseRef.projectName, baseRef.envName, "latest", baseRef.isUsingLegacyID, baseRef.hasAmbiguousPath}
+			if len(args) != 0 {
+				compareRef, err = env.getExistingEnvRefWithRelative(ctx, args[0], &baseRef)
+				if err != nil {
+					return err
+				}
+			}
+
+			baseSchema, err := env.getEnvironmentSchema(ctx, baseRef)
+			if err != nil {
+				return err
+			}
+			compareSchema, err := env.getEnvironmentSchema(ctx, compareRef)
+			if err != nil {
+				return err
+			}
+
+			changes, err := schema.Diff(baseSchema, compareSchema)
+			if err != nil {
+				return fmt.Errorf("comparing schemas: %w", err)
+			}
+			writeSchemaChanges(env.esc.stdout, changes)
+
+			if changes.Breaking() {
+				return fmt.Errorf("%v contains breaking schema changes", compareRef.String())
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&schemaOnly, "schema", false,
+		"Compare the schemas of the two versions")
+
+	return cmd
+}
+
+// getEnvironmentSchema returns the schema of the given environment version. Schemas of secret values are marked as
+// secret.
+func (env *envCommand) getEnvironmentSchema(ctx context.Context, ref environmentRef) (*schema.Schema, error) {
+	def, _, _, err := env.esc.client.GetEnvironment(ctx, ref.orgName, ref.projectName, ref.envName, ref.version, false)
+	if err != nil {
+		return nil, fmt.Errorf("getting environment definition: %w", err)
+	}
+	checked, _, err := env.esc.client.CheckYAMLEnvironment(ctx, ref.orgName, def, client.CheckYAMLOption{})
+	if err != nil {
+		return nil, fmt.Errorf("getting environment metadata: %w", err)
+	}
+	if checked == nil || checked.Schema == nil {
+		return nil, fmt.Errorf("environment %v has errors; run `esc env check` for details", ref.String())
+	}
+
+	markSecretSchemas(checked.Schema, esc.NewValue(checked.Properties))
+	return checked.Schema, nil
+}
+
+// markSecretSchemas marks the schemas of secret values as secret.
+func markSecretSchemas(s *schema.Schema, v esc.Value) {
+	if s == nil {
+		return
+	}
+	if v.Secret {
+		s.Secret = true
+	}
+
+	switch repr := v.Value.(type) {
+	case []esc.Value:
+		for i, e := range repr {
+			if i < len(s.PrefixItems) {
+				markSecretSchemas(s.PrefixItems[i], e)
+			} else {
+				markSecretSchemas(s.Items, e)
+			}
+		}
+	case map[string]esc.Value:
+		for k, e := range repr {
+			markSecretSchemas(s.Properties[k], e)
+		}
+	}
+}
+
+func writeSchemaChanges(w io.Writer, changes schema.Changes) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "no schema changes")
+		return
+	}
+
+	var breaking, nonBreaking schema.Changes
+	for _, c := range changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		} else {
+			nonBreaking = append(nonBreaking, c)
+		}
+	}
+
+	writeSection := func(title string, changes schema.Changes) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "%v:\n", title)
+		for _, c := range changes {
+			path := c.Path
+			if path == "" {
+				path = "<root>"
+			}
+			fmt.Fprintf(w, "  %v: %v (%v)\n", path, c.Description, c.Kind)
+		}
+	}
+	writeSection("breaking changes", breaking)
+	if len(breaking) != 0 && len(nonBreaking) != 0 {
+		fmt.Fprintln(w)
+	}
+	writeSection("non-breaking changes", nonBreaking)
+}
@@ -138,6 +138,7 @@ func New(opts *Options) *cobra.Command {
 	cmd.AddCommand(&openCmdCopy)
 	cmd.AddCommand(&runCmdCopy)
 
+	cmd.AddCommand(newEvalCmd(&envCommand{esc: esc}))
 	cmd.AddCommand(newLoginCmd(esc))
 	cmd.AddCommand(newLogoutCmd(esc))
 	cmd.AddCommand(newVersionCmd(esc))
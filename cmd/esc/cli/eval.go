@@ -0,0 +1,323 @@
+// Copyright 2026, Pulumi Corporation.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/cmd/esc/cli/client"
+	"github.com/pulumi/esc/eval"
+	"github.com/pulumi/esc/schema"
+	"github.com/pulumi/esc/syntax"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+func newEvalCmd(env *envCommand) *cobra.Command {
+	var importsDir string
+	var serviceFallback bool
+	var orgName string
+	var check bool
+	var strict bool
+	var format string
+	var diagsFormat diagnosticsFormat
+
+	cmd := &cobra.Command{
+		Use:   "eval <file> [property path]",
+		Args:  cobra.RangeArgs(1, 2),
+		Short: "Evaluate a local environment definition.",
+		Long: "Evaluate a local environment definition\n" +
+			"\n" +
+			"This command evaluates the environment definition in the given file entirely\n" +
+			"in-process, without contacting Pulumi Cloud. The result is written to stdout as\n" +
+			"JSON. If a property path is specified, only that property is written. Pass `-` to\n" +
+			"read the definition from standard input.\n" +
+			"\n" +
+			"Imports are loaded from YAML files in the directory given by --imports-dir, which\n" +
+			"defaults to the directory that contains the definition. The import `name` is read\n" +
+			"from `<dir>/<name>.yaml`, and the import `name@version` is read from\n" +
+			"`<dir>/<name>@<version>.yaml`. If --service-fallback is set, imports that are not\n" +
+			"present locally are loaded from Pulumi Cloud.\n" +
+			"\n" +
+			"Evaluation is provider-less: the esc CLI does not include any providers or\n" +
+			"rotators, so calls to fn::open and fn::rotate fail. Use --check to evaluate an\n" +
+			"environment without opening its providers. Secrets must be written in plaintext\n" +
+			"(`fn::secret: <value>`); secrets encrypted by Pulumi Cloud cannot be decrypted\n" +
+			"locally.\n",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			var propertyPath resource.PropertyPath
+			if len(args) == 2 {
+				p, err := resource.ParsePropertyPath(args[1])
+				if err != nil {
+					return fmt.Errorf("invalid property path %v: %w", args[1], err)
+				}
+				propertyPath = p
+			}
+
+			switch format {
+			case "detailed", "json", "yaml", "string":
+				// OK
+			case "dotenv", "shell":
+				if len(propertyPath) != 0 {
+					return fmt.Errorf("output format '%s' may not be used with a property path", format)
+				}
+			default:
+				return fmt.Errorf("unknown output format %q", format)
+			}
+
+			// Paths into env.esc.fs are slash-separated.
+			file := filepath.ToSlash(args[0])
+
+			var yaml []byte
+			var err error
+			name := esc.AnonymousEnvironmentName
+			if file == "-" {
+				yaml, err = io.ReadAll(env.esc.stdin)
+			} else {
+				yaml, err = fs.ReadFile(env.esc.fs, file)
+				name = strings.TrimSuffix(path.Base(file), path.Ext(file))
+				if importsDir == "" {
+					importsDir = path.Dir(file)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("reading environment definition: %w", err)
+			}
+
+			loader := &localEnvironmentLoader{
+				fs:        env.esc.fs,
+				dir:       valueOrDefault(filepath.ToSlash(importsDir), "."),
+				decrypter: noLocalDecrypter{},
+			}
+			if serviceFallback {
+				loader.fallback = func(ctx context.Context) (eval.VersionedEnvironmentLoader, error) {
+					if err := env.esc.getCachedClient(ctx); err != nil {
+						return nil, err
+					}
+					return &serviceEnvironmentLoader{
+						client:  env.esc.client,
+						orgName: valueOrDefault(orgName, env.esc.account.DefaultOrg),
+					}, nil
+				}
+			}
+
+			result, diags, err := evalLocalEnvironment(ctx, name, yaml, loader, check)
+			if err != nil {
+				return err
+			}
+			if strict {
+				diags = eval.Strict(diags)
+			}
+			if len(diags) != 0 {
+				// Diagnostics are written to stderr so that they do not interleave with the result.
+				ok, err := diagsFormat.writeMachineDiagnostics(env.esc.stderr, clientDiagnostics(diags))
+				if !ok {
+					err = env.writeYAMLEnvironmentDiagnosticsText(env.esc.stderr, name, yaml, clientDiagnostics(diags))
+				}
+				contract.IgnoreError(err)
+			}
+			if diags.HasErrors() {
+				return errors.New("environment definition has errors")
+			}
+
+			return env.renderValue(env.esc.stdout, result, propertyPath, format, false, true)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&importsDir, "imports-dir", "",
+		"the directory from which imports are loaded. Defaults to the directory that contains the definition.")
+	cmd.Flags().BoolVar(
+		&serviceFallback, "service-fallback", false,
+		"load imports that are not present locally from Pulumi Cloud")
+	cmd.Flags().StringVar(
+		&orgName, "org", "",
+		"the organization from which imports are loaded if --service-fallback is set. Defaults to the default organization.")
+	cmd.Flags().BoolVar(
+		&check, "check", false,
+		"check the environment rather than evaluating it. Providers are not opened.")
+	cmd.Flags().BoolVar(
+		&strict, "strict", false,
+		"report warnings as errors")
+	cmd.Flags().StringVarP(
+		&format, "format", "f", "json",
+		"the output format to use. May be 'dotenv', 'json', 'yaml', 'detailed', 'shell' or 'string'")
+	cmd.Flags().Var(&diagsFormat, "diagnostics-format",
+		"The format used to print diagnostics. One of 'text', 'json', or 'sarif'.")
+
+	return cmd
+}
+
+// evalLocalEnvironment loads and evaluates an environment definition in-process. No providers or rotators are
+// available: if check is false, opening a provider or rotator fails, and if check is true, providers and rotators
+// are replaced by stand-ins that accept any inputs.
+func evalLocalEnvironment(
+	ctx context.Context,
+	name string,
+	yaml []byte,
+	environments eval.EnvironmentLoader,
+	check bool,
+) (*esc.Environment, syntax.Diagnostics, error) {
+	decl, diags, err := eval.LoadYAMLBytes(name, yaml)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading environment definition: %w", err)
+	}
+	if diags.HasErrors() {
+		return nil, diags, nil
+	}
+
+	execContext, err := esc.NewExecContext(nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating execution context: %w", err)
+	}
+
+	var result *esc.Environment
+	var evalDiags syntax.Diagnostics
+	if check {
+		result, evalDiags = eval.CheckEnvironment(ctx, name, decl, noLocalDecrypter{}, uncheckedProviders{}, environments, execContext, true)
+	} else {
+		result, evalDiags = eval.EvalEnvironment(ctx, name, decl, noLocalDecrypter{}, noLocalProviders{}, environments, execContext)
+	}
+	diags.Extend(evalDiags...)
+	return result, diags, nil
+}
+
+// clientDiagnostics converts diagnostics issued by the evaluator into the diagnostics returned by the service.
+func clientDiagnostics(diags syntax.Diagnostics) []client.EnvironmentDiagnostic {
+	if len(diags) == 0 {
+		return nil
+	}
+	out := make([]client.EnvironmentDiagnostic, len(diags))
+	for i, d := range diags {
+		var rng *esc.Range
+		if d.Subject != nil {
+			rng = &esc.Range{
+				Environment: d.Subject.Filename,
+				Begin: esc.Pos{
+					Line:   d.Subject.Start.Line,
+					Column: d.Subject.Start.Column,
+					Byte:   d.Subject.Start.Byte,
+				},
+				End: esc.Pos{
+					Line:   d.Subject.End.Line,
+					Column: d.Subject.End.Column,
+					Byte:   d.Subject.End.Byte,
+				},
+			}
+		}
+
+		severity := client.DiagError
+		if d.Severity == hcl.DiagWarning {
+			severity = client.DiagWarning
+		}
+
+		detail := d.Detail
+		if d.AliasRange != nil {
+			alias := fmt.Sprintf("reached through the alias at line %v, column %v", d.AliasRange.Start.Line, d.AliasRange.Start.Column)
+			if detail == "" {
+				detail = alias
+			} else {
+				detail += "; " + alias
+			}
+		}
+
+		out[i] = client.EnvironmentDiagnostic{
+			Range:    rng,
+			Summary:  d.Summary,
+			Detail:   detail,
+			Severity: severity,
+			Code:     d.Code,
+		}
+	}
+	return out
+}
+
+// noLocalProviders is the provider registry used to evaluate environments locally. The esc CLI does not include any
+// providers, so it fails to load every provider and rotator.
+type noLocalProviders struct{}
+
+func (noLocalProviders) LoadProvider(ctx context.Context, name string) (esc.Provider, error) {
+	return nil, fmt.Errorf("provider %q is not available for local evaluation: the esc CLI does not include any "+
+		"providers; use --check to evaluate without opening providers", name)
+}
+
+func (noLocalProviders) LoadRotator(ctx context.Context, name string) (esc.Rotator, error) {
+	return nil, fmt.Errorf("rotator %q is not available for local evaluation: the esc CLI does not include any "+
+		"rotators; use --check to evaluate without opening rotators", name)
+}
+
+// uncheckedProviders is the provider registry used to check environments locally. Every provider and rotator is
+// replaced by a stand-in that accepts any inputs and produces values of any type. Checking never opens providers, so
+// the stand-ins are never opened.
+type uncheckedProviders struct{}
+
+func (uncheckedProviders) LoadProvider(ctx context.Context, name string) (esc.Provider, error) {
+	return uncheckedProvider{}, nil
+}
+
+func (uncheckedProviders) LoadRotator(ctx context.Context, name string) (esc.Rotator, error) {
+	return uncheckedRotator{}, nil
+}
+
+// uncheckedProvider is a provider whose inputs and outputs may be any value.
+type uncheckedProvider struct{}
+
+func (uncheckedProvider) Schema() (*schema.Schema, *schema.Schema) {
+	return schema.Always().Schema(), schema.Always().Schema()
+}
+
+func (uncheckedProvider) Open(
+	ctx context.Context,
+	inputs map[string]esc.Value,
+	executionContext esc.EnvExecContext,
+) (esc.Value, error) {
+	return esc.Value{}, errors.New("providers are not available for local evaluation")
+}
+
+// uncheckedRotator is a rotator whose inputs and outputs may be any value and whose state may be any object. The state
+// must not accept null, as the evaluator already allows a null state in addition to the rotator's state schema.
+type uncheckedRotator struct{}
+
+func (uncheckedRotator) Schema() (*schema.Schema, *schema.Schema, *schema.Schema) {
+	state := schema.Object().AdditionalProperties(schema.Always()).Schema()
+	return schema.Always().Schema(), state, schema.Always().Schema()
+}
+
+func (uncheckedRotator) Open(
+	ctx context.Context,
+	inputs map[string]esc.Value,
+	state map[string]esc.Value,
+	executionContext esc.EnvExecContext,
+) (esc.Value, error) {
+	return esc.Value{}, errors.New("rotators are not available for local evaluation")
+}
+
+func (uncheckedRotator) Rotate(
+	ctx context.Context,
+	inputs map[string]esc.Value,
+	state map[string]esc.Value,
+	executionContext esc.EnvExecContext,
+) (esc.Value, error) {
+	return esc.Value{}, errors.New("rotators are not available for local evaluation")
+}
+
+// noLocalDecrypter is the decrypter used to evaluate environments locally. Secrets in local definitions must be
+// written in plaintext, as the keys used by Pulumi Cloud to encrypt secrets are not available locally.
+type noLocalDecrypter struct{}
+
+func (noLocalDecrypter) Decrypt(_ context.Context, _ []byte) ([]byte, error) {
+	return nil, errors.New("encrypted secrets cannot be decrypted locally; write secrets in plaintext instead")
+}
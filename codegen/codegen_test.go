@@ -0,0 +1,125 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/esc/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func accept() bool {
+	return cmdutil.IsTruthy(os.Getenv("PULUMI_ACCEPT"))
+}
+
+// testGenerator runs a code generator against each schema in testdata and compares its output with the expected
+// output in the file with the given name.
+func testGenerator(t *testing.T, expectedName string, generate func(w io.Writer, s *schema.Schema) error) {
+	path := filepath.Join("testdata")
+	entries, err := os.ReadDir(path)
+	require.NoError(t, err)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		t.Run(e.Name(), func(t *testing.T) {
+			dir := filepath.Join(path, e.Name())
+
+			schemaJSON, err := os.ReadFile(filepath.Join(dir, "schema.json"))
+			require.NoError(t, err)
+
+			var s schema.Schema
+			require.NoError(t, json.Unmarshal(schemaJSON, &s))
+
+			var actual bytes.Buffer
+			require.NoError(t, generate(&actual, &s))
+
+			expectedPath := filepath.Join(dir, expectedName)
+			if accept() {
+				require.NoError(t, os.WriteFile(expectedPath, actual.Bytes(), 0o600))
+				return
+			}
+
+			expected, err := os.ReadFile(expectedPath)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), actual.String())
+		})
+	}
+}
+
+func TestGenerateGo(t *testing.T) {
+	testGenerator(t, "expected.go", func(w io.Writer, s *schema.Schema) error {
+		return GenerateGo(w, s, GoOptions{})
+	})
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	testGenerator(t, "expected.d.ts", func(w io.Writer, s *schema.Schema) error {
+		return GenerateTypeScript(w, s, TypeScriptOptions{})
+	})
+}
+
+func TestGenerateTypeScriptValidator(t *testing.T) {
+	testGenerator(t, "expected.zod.ts", func(w io.Writer, s *schema.Schema) error {
+		return GenerateTypeScriptValidator(w, s, TypeScriptOptions{})
+	})
+}
+
+func TestGeneratePython(t *testing.T) {
+	testGenerator(t, "expected.py", func(w io.Writer, s *schema.Schema) error {
+		return GeneratePython(w, s, PythonOptions{})
+	})
+}
+
+func TestGenerateInferred(t *testing.T) {
+	// The schema inferred for the values `{region: us-west-2, mode: dev, tags: [web, prod]}`, where mode is declared
+	// by a provider as an enum.
+	s := schema.Record(schema.BuilderMap{
+		"region": schema.String().Const("us-west-2"),
+		"mode":   schema.String().Enum("dev", "prod"),
+		"tags":   schema.Tuple(schema.String().Const("web"), schema.String().Const("prod")),
+	}).Schema()
+
+	var ts bytes.Buffer
+	require.NoError(t, GenerateTypeScript(&ts, s, TypeScriptOptions{Inferred: true}))
+	assert.Contains(t, ts.String(), "    mode: \"dev\" | \"prod\";\n    region: string;\n    tags: string[];\n")
+
+	var py bytes.Buffer
+	require.NoError(t, GeneratePython(&py, s, PythonOptions{Inferred: true}))
+	assert.Contains(t, py.String(), "    mode: Literal[\"dev\", \"prod\"]\n    region: str\n    tags: List[str]\n")
+}
+
+func TestPascalCase(t *testing.T) {
+	cases := map[string]string{
+		"region":               "Region",
+		"accessKeyId":          "AccessKeyId",
+		"aws_region":           "AwsRegion",
+		"AWS_REGION":           "AWSREGION",
+		"environmentVariables": "EnvironmentVariables",
+		"9lives":               "X9lives",
+		"---":                  "",
+	}
+	for name, expected := range cases {
+		assert.Equal(t, expected, pascalCase(name), name)
+	}
+}
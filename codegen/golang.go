@@ -0,0 +1,185 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+
+	"github.com/pulumi/esc/schema"
+)
+
+// GoOptions controls the generation of Go code.
+type GoOptions struct {
+	// PackageName is the name of the generated package. Defaults to "config".
+	PackageName string
+
+	// TypeName is the name of the generated type for the environment's root value. Defaults to "Environment".
+	TypeName string
+}
+
+// GenerateGo writes Go types for the values described by an environment's schema to w. Each object is generated as a
+// struct with JSON tags. Secret values are wrapped in a Secret type that redacts itself when formatted. The generated
+// code includes a Load function that decodes an opened environment's value into the root type.
+func GenerateGo(w io.Writer, s *schema.Schema, opts GoOptions) error {
+	if opts.PackageName == "" {
+		opts.PackageName = "config"
+	}
+	if opts.TypeName == "" {
+		opts.TypeName = "Environment"
+	}
+
+	m, err := newModel(s, modelOptions{}, opts.TypeName, "Secret", "Load")
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	g := goGenerator{w: &b}
+	g.generate(m, opts)
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("internal error: formatting generated code: %w", err)
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+type goGenerator struct {
+	w io.Writer
+}
+
+func (g *goGenerator) printf(format string, args ...any) {
+	fmt.Fprintf(g.w, format, args...)
+}
+
+func (g *goGenerator) generate(m *model, opts GoOptions) {
+	g.printf("// Code generated by esc env codegen. DO NOT EDIT.\n\n")
+	g.printf("package %v\n\n", opts.PackageName)
+	g.printf("import (\n\t\"encoding/json\"\n\t\"fmt\"\n\n\t\"github.com/pulumi/esc\"\n)\n\n")
+
+	g.printf(`// Secret holds a secret value. Formatting a Secret with the fmt package prints "[secret]" rather than the value.
+type Secret[T any] struct {
+	Value T
+}
+
+// String returns a redacted representation of the secret.
+func (s Secret[T]) String() string { return "[secret]" }
+
+// GoString returns a redacted representation of the secret.
+func (s Secret[T]) GoString() string { return "[secret]" }
+
+// MarshalJSON marshals the secret's value.
+func (s Secret[T]) MarshalJSON() ([]byte, error) { return json.Marshal(s.Value) }
+
+// UnmarshalJSON unmarshals the secret's value.
+func (s *Secret[T]) UnmarshalJSON(b []byte) error { return json.Unmarshal(b, &s.Value) }
+
+`)
+
+	g.printf("// Load decodes the value of an opened environment into %v.\n", m.root.name)
+	g.printf("func Load(v esc.Value) (*%v, error) {\n", m.root.name)
+	g.printf("\tbytes, err := json.Marshal(v.ToJSON(false))\n")
+	g.printf("\tif err != nil {\n\t\treturn nil, fmt.Errorf(\"encoding environment: %%w\", err)\n\t}\n")
+	g.printf("\tvar env %v\n", m.root.name)
+	g.printf("\tif err := json.Unmarshal(bytes, &env); err != nil {\n")
+	g.printf("\t\treturn nil, fmt.Errorf(\"decoding environment: %%w\", err)\n\t}\n")
+	g.printf("\treturn &env, nil\n}\n")
+
+	for _, o := range m.objects {
+		g.printf("\n")
+		g.generateObject(o, o == m.root)
+	}
+}
+
+func (g *goGenerator) generateObject(o *objectType, root bool) {
+	if lines := docLines(o.description); len(lines) != 0 {
+		for _, line := range lines {
+			g.printf("// %v\n", line)
+		}
+	} else if root {
+		g.printf("// %v holds the values of an environment.\n", o.name)
+	} else {
+		g.printf("// %v holds the values of an object within an environment.\n", o.name)
+	}
+
+	g.printf("type %v struct {\n", o.name)
+	names := map[string]bool{}
+	for i, f := range o.fields {
+		if i != 0 && len(docLines(f.description)) != 0 {
+			g.printf("\n")
+		}
+		for _, line := range docLines(f.description) {
+			g.printf("\t// %v\n", line)
+		}
+
+		name := goFieldName(f.key, names)
+		typ, tag := g.typeName(f.typ), f.key
+		if !f.required {
+			switch f.typ.kind {
+			case kindAny, kindNull, kindArray, kindMap:
+				// OK
+			default:
+				typ = "*" + typ
+			}
+			tag += ",omitempty"
+		}
+		g.printf("\t%v %v `json:%v`\n", name, typ, strconv.Quote(tag))
+	}
+	g.printf("}\n")
+}
+
+func (g *goGenerator) typeName(t *typeRef) string {
+	var name string
+	switch t.kind {
+	case kindString:
+		name = "string"
+	case kindNumber:
+		name = "float64"
+	case kindBoolean:
+		name = "bool"
+	case kindArray:
+		name = "[]" + g.typeName(t.element)
+	case kindMap:
+		name = "map[string]" + g.typeName(t.element)
+	case kindObject:
+		name = t.object.name
+	default:
+		name = "any"
+	}
+	if t.secret {
+		name = "Secret[" + name + "]"
+	}
+	return name
+}
+
+// goFieldName returns a unique, exported Go identifier for the given property name.
+func goFieldName(key string, names map[string]bool) string {
+	base := pascalCase(key)
+	if base == "" {
+		base = "Field"
+	}
+
+	name := base
+	for i := 2; names[name]; i++ {
+		name = fmt.Sprintf("%v%v", base, i)
+	}
+	names[name] = true
+	return name
+}
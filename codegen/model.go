@@ -0,0 +1,458 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codegen generates typed bindings for the values of an environment from the environment's schema.
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pulumi/esc/schema"
+	"golang.org/x/exp/maps"
+)
+
+// A typeKind is the kind of a generated type.
+type typeKind int
+
+const (
+	kindAny typeKind = iota
+	kindString
+	kindNumber
+	kindBoolean
+	kindNull
+	kindArray
+	kindTuple
+	kindMap
+	kindObject
+	kindUnion
+)
+
+// A typeRef is a language-neutral reference to a generated type.
+type typeRef struct {
+	kind     typeKind
+	secret   bool        // true if the value is secret
+	literals []any       // the values permitted by the type, if the type is a literal or an enum
+	element  *typeRef    // the element type of an array or map, or the rest element of a tuple
+	elements []*typeRef  // the element types of a tuple
+	members  []*typeRef  // the member types of a union
+	object   *objectType // the object type of an object
+}
+
+// modelOptions controls which schema constructs are represented precisely by a model. Constructs that are not
+// represented precisely are approximated.
+type modelOptions struct {
+	literals bool // represent Enum as literal types
+	consts   bool // represent Const as literal types; requires literals
+	tuples   bool // represent PrefixItems as tuple types rather than arrays of a merged element type
+	unions   bool // represent AnyOf and OneOf as union types
+}
+
+// An objectType is a language-neutral description of a generated object type.
+type objectType struct {
+	name        string
+	description string
+	fields      []*field
+}
+
+// A field is a single field of an objectType.
+type field struct {
+	key         string // the field's property name
+	description string
+	required    bool
+	typ         *typeRef
+}
+
+// A model describes the types generated for an environment.
+type model struct {
+	root    *objectType
+	objects []*objectType // all object types, in the order in which they were first referenced
+
+	options modelOptions
+	names   map[string]bool                // the names of all object types
+	schemas map[*schema.Schema]*objectType // the object types generated for each schema
+}
+
+// newModel builds the model for the given schema. The root object type is given the root name. Names in reserved are
+// not used for object types.
+func newModel(s *schema.Schema, options modelOptions, rootName string, reserved ...string) (*model, error) {
+	if err := s.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	m := &model{options: options, names: map[string]bool{}, schemas: map[*schema.Schema]*objectType{}}
+	for _, name := range reserved {
+		m.names[name] = true
+	}
+
+	root := m.typeOf(s, []string{rootName})
+	if root.kind != kindObject {
+		root = &typeRef{kind: kindObject, object: m.newObject([]string{rootName}, s)}
+	}
+	m.root = root.object
+	return m, nil
+}
+
+// newObject creates a new object type for the given path of names. Schemas that are referenced more than once (e.g.
+// definitions) share a single object type.
+func (m *model) newObject(path []string, s *schema.Schema) *objectType {
+	if o, ok := m.schemas[s]; ok {
+		return o
+	}
+
+	o := &objectType{name: m.objectName(path), description: s.Description}
+	m.objects = append(m.objects, o)
+	m.schemas[s] = o
+
+	required := make(map[string]bool, len(s.Required))
+	for _, k := range s.Required {
+		required[k] = true
+	}
+
+	keys := maps.Keys(s.Properties)
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := s.Properties[k]
+		description := ""
+		if r := resolveRef(p); r != nil {
+			description = r.Description
+		}
+		o.fields = append(o.fields, &field{
+			key:         k,
+			description: description,
+			required:    required[k],
+			typ:         m.typeOf(p, appendPath(path, k)),
+		})
+	}
+	return o
+}
+
+// objectName returns a unique name for an object type at the given path. The name is derived from the last element of
+// the path. If that name is already taken, the names of the enclosing elements are prepended until the name is unique.
+func (m *model) objectName(path []string) string {
+	name := ""
+	for i := len(path) - 1; i >= 0; i-- {
+		name = pascalCase(path[i]) + name
+		if name != "" && !m.names[name] {
+			m.names[name] = true
+			return name
+		}
+	}
+
+	base := name
+	if base == "" {
+		base = "Object"
+	}
+	for i := 2; ; i++ {
+		name = fmt.Sprintf("%v%v", base, i)
+		if !m.names[name] {
+			m.names[name] = true
+			return name
+		}
+	}
+}
+
+// typeOf returns the type of values described by the given schema. Object types for definitions are named after the
+// definition.
+func (m *model) typeOf(s *schema.Schema, path []string) *typeRef {
+	if s != nil && s.GetRef() != nil {
+		if name, ok := strings.CutPrefix(s.Ref, "#/$defs/"); ok {
+			path = []string{name}
+		}
+	}
+
+	s = resolveRef(s)
+	if s == nil {
+		return &typeRef{kind: kindAny}
+	}
+
+	t := m.typeOfSchema(s, path)
+	if s.Secret {
+		// The literal values of a secret must not appear in generated code.
+		copy := *t
+		copy.secret, copy.literals = true, nil
+		t = &copy
+	}
+	return t
+}
+
+func (m *model) typeOfSchema(s *schema.Schema, path []string) *typeRef {
+	if s.Always || s.Never {
+		return &typeRef{kind: kindAny}
+	}
+
+	if len(s.AnyOf) != 0 || len(s.OneOf) != 0 {
+		if m.options.unions {
+			return m.unionType(s, path)
+		}
+
+		// Without union types, unions are only supported if each of their members is the same scalar type.
+		var kind typeKind
+		for i, x := range append(append([]*schema.Schema{}, s.AnyOf...), s.OneOf...) {
+			k := scalarKind(resolveRef(x))
+			if k == kindAny || (i != 0 && k != kind) {
+				return &typeRef{kind: kindAny}
+			}
+			kind = k
+		}
+		return &typeRef{kind: kind}
+	}
+
+	switch s.Type {
+	case "string":
+		return &typeRef{kind: kindString, literals: m.literals(s)}
+	case "number":
+		return &typeRef{kind: kindNumber, literals: m.literals(s)}
+	case "boolean":
+		return &typeRef{kind: kindBoolean, literals: m.literals(s)}
+	case "null":
+		return &typeRef{kind: kindNull}
+	case "array":
+		if m.options.tuples && len(s.PrefixItems) != 0 {
+			return m.tupleType(s, path)
+		}
+		return &typeRef{kind: kindArray, element: m.elementType(s, path)}
+	case "object", "":
+		if len(s.Properties) != 0 {
+			return &typeRef{kind: kindObject, object: m.newObject(path, s)}
+		}
+		if s.Type == "object" {
+			element := &typeRef{kind: kindAny}
+			if s.AdditionalProperties != nil && !s.AdditionalProperties.Never {
+				element = m.typeOf(s.AdditionalProperties, suffixPath(path, "value"))
+			}
+			return &typeRef{kind: kindMap, element: element}
+		}
+	}
+	return &typeRef{kind: kindAny, literals: m.literals(s)}
+}
+
+// literals returns the scalar values permitted by a schema's Const or Enum, if literal types are enabled.
+func (m *model) literals(s *schema.Schema) []any {
+	if !m.options.literals {
+		return nil
+	}
+
+	values := s.Enum
+	if s.Const != nil && m.options.consts {
+		values = []any{s.Const}
+	}
+	for _, v := range values {
+		switch v.(type) {
+		case nil, bool, string, json.Number:
+			// OK
+		default:
+			return nil
+		}
+	}
+	return values
+}
+
+// unionType returns the union of the types of a schema's AnyOf and OneOf members.
+func (m *model) unionType(s *schema.Schema, path []string) *typeRef {
+	var members []*typeRef
+	for i, x := range append(append([]*schema.Schema{}, s.AnyOf...), s.OneOf...) {
+		t := m.typeOf(x, suffixPath(path, fmt.Sprintf("option%v", i+1)))
+		if t.kind == kindAny && len(t.literals) == 0 {
+			return t
+		}
+		members = append(members, t)
+	}
+	if len(members) == 1 {
+		return members[0]
+	}
+	return &typeRef{kind: kindUnion, members: members}
+}
+
+// tupleType returns the tuple type of an array with PrefixItems. If the array permits additional items, the type of
+// the additional items is the tuple's rest element.
+func (m *model) tupleType(s *schema.Schema, path []string) *typeRef {
+	t := &typeRef{kind: kindTuple}
+	for i, x := range s.PrefixItems {
+		t.elements = append(t.elements, m.typeOf(x, suffixPath(path, fmt.Sprintf("item%v", i+1))))
+	}
+	if s.Items != nil && !s.Items.Never {
+		t.element = m.typeOf(s.Items, suffixPath(path, "item"))
+	}
+	return t
+}
+
+// elementType returns the element type of an array. The schemas of tuple elements are merged if possible.
+func (m *model) elementType(s *schema.Schema, path []string) *typeRef {
+	path = suffixPath(path, "item")
+	if s.Items != nil && !s.Items.Never {
+		return m.typeOf(s.Items, path)
+	}
+	if len(s.PrefixItems) == 0 {
+		return &typeRef{kind: kindAny}
+	}
+
+	items := make([]*schema.Schema, len(s.PrefixItems))
+	for i, x := range s.PrefixItems {
+		items[i] = resolveRef(x)
+	}
+
+	if kind := scalarKind(items[0]); kind != kindAny {
+		for _, x := range items[1:] {
+			if scalarKind(x) != kind {
+				return &typeRef{kind: kindAny}
+			}
+		}
+		return m.typeOf(items[0], path)
+	}
+
+	if merged, ok := mergeObjects(items); ok {
+		return m.typeOf(merged, path)
+	}
+	return &typeRef{kind: kindAny}
+}
+
+// mergeObjects merges the schemas of a list of objects. The merged schema has the union of the objects' properties.
+// A property is required if it is required by all of the objects. The first schema for each property is used.
+func mergeObjects(objects []*schema.Schema) (*schema.Schema, bool) {
+	properties := map[string]*schema.Schema{}
+	required := map[string]int{}
+	secret := false
+	for _, o := range objects {
+		if o == nil || o.Type != "object" || len(o.Properties) == 0 {
+			return nil, false
+		}
+		for k, p := range o.Properties {
+			if _, ok := properties[k]; !ok {
+				properties[k] = p
+			}
+		}
+		for _, k := range o.Required {
+			required[k]++
+		}
+		secret = secret || o.Secret
+	}
+
+	merged := &schema.Schema{Type: "object", Properties: properties, Secret: secret}
+	for k, n := range required {
+		if n == len(objects) {
+			merged.Required = append(merged.Required, k)
+		}
+	}
+	sort.Strings(merged.Required)
+	return merged, true
+}
+
+// scalarKind returns the kind of a scalar schema, or kindAny if the schema is not a scalar.
+func scalarKind(s *schema.Schema) typeKind {
+	if s == nil {
+		return kindAny
+	}
+	switch s.Type {
+	case "string":
+		return kindString
+	case "number":
+		return kindNumber
+	case "boolean":
+		return kindBoolean
+	default:
+		return kindAny
+	}
+}
+
+// appendPath returns a copy of path with name appended.
+func appendPath(path []string, name string) []string {
+	return append(path[:len(path):len(path)], name)
+}
+
+// suffixPath returns a copy of path with suffix appended to its last name.
+func suffixPath(path []string, suffix string) []string {
+	return appendPath(path[:len(path)-1], path[len(path)-1]+"_"+suffix)
+}
+
+func resolveRef(s *schema.Schema) *schema.Schema {
+	for i := 0; s != nil && s.GetRef() != nil && i < 32; i++ {
+		s = s.GetRef()
+	}
+	return s
+}
+
+// words splits a property name into words. Words are separated by non-alphanumeric characters and by transitions
+// from lowercase to uppercase letters.
+func words(name string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) != 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
+
+// pascalCase converts a property name to PascalCase, e.g. `aws_region` to `AwsRegion`.
+func pascalCase(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		runes := []rune(w)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	s := b.String()
+	if s != "" && unicode.IsDigit([]rune(s)[0]) {
+		s = "X" + s
+	}
+	return s
+}
+
+// docLines splits a description into lines for use in a doc comment.
+func docLines(description string) []string {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return nil
+	}
+	return strings.Split(description, "\n")
+}
+
+// mapSlice applies f to each element of a slice.
+func mapSlice[T, U any](s []T, f func(T) U) []U {
+	result := make([]U, len(s))
+	for i, v := range s {
+		result[i] = f(v)
+	}
+	return result
+}
+
+// jsonString returns the JSON representation of a scalar value.
+func jsonString(v any) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		panic(fmt.Errorf("internal error: encoding %v: %w", v, err))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,264 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codegen
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/esc/schema"
+	"golang.org/x/exp/maps"
+)
+
+// PythonOptions controls the generation of Python code.
+type PythonOptions struct {
+	// TypeName is the name of the generated type for the environment's root value. Defaults to "Environment".
+	TypeName string
+
+	// Inferred is true if the schema was inferred from an environment's values (e.g. by checking the environment)
+	// rather than declared. The constants and tuple elements of an inferred schema describe the environment's current
+	// values rather than their types, so they are not represented in the generated types.
+	Inferred bool
+}
+
+// pythonTypingNames are the names the generated code may import from the typing module.
+var pythonTypingNames = []string{
+	"Annotated", "Any", "Dict", "List", "Literal", "NotRequired", "Tuple", "TypeVar", "TypedDict", "Union",
+}
+
+// GeneratePython writes Python types for the values described by an environment's schema to w. Each object is
+// generated as a TypedDict. Secret values are annotated with the Secret type. The generated code requires Python 3.11
+// or later.
+func GeneratePython(w io.Writer, s *schema.Schema, opts PythonOptions) error {
+	if opts.TypeName == "" {
+		opts.TypeName = "Environment"
+	}
+
+	reserved := append([]string{"Secret", "T"}, pythonTypingNames...)
+	options := modelOptions{literals: true, consts: !opts.Inferred, tuples: !opts.Inferred, unions: true}
+	m, err := newModel(s, options, opts.TypeName, reserved...)
+	if err != nil {
+		return err
+	}
+
+	// Generate the classes first so that we know which names to import. Classes are emitted in reverse order so that
+	// each class is defined before the classes that reference it. References to classes that have not been defined
+	// yet (i.e. recursive references) are quoted.
+	g := pythonGenerator{
+		imports: map[string]bool{"Annotated": true, "TypeVar": true, "TypedDict": true},
+		defined: map[*objectType]bool{},
+	}
+	var body bytes.Buffer
+	for i := len(m.objects) - 1; i >= 0; i-- {
+		body.WriteString("\n\n")
+		g.generateObject(&body, m.objects[i])
+	}
+
+	b := bufio.NewWriter(w)
+	b.WriteString("# Code generated by esc env codegen. DO NOT EDIT.\n\n")
+	imports := maps.Keys(g.imports)
+	sort.Strings(imports)
+	fmt.Fprintf(b, "from typing import %v\n\n", strings.Join(imports, ", "))
+	b.WriteString("T = TypeVar(\"T\")\n\n")
+	b.WriteString("Secret = Annotated[T, \"secret\"]\n")
+	b.WriteString("\"\"\"A secret value.\"\"\"\n")
+	b.Write(body.Bytes())
+	return b.Flush()
+}
+
+type pythonGenerator struct {
+	imports map[string]bool
+	defined map[*objectType]bool
+}
+
+func (g *pythonGenerator) typing(name string) string {
+	g.imports[name] = true
+	return name
+}
+
+func (g *pythonGenerator) generateObject(w *bytes.Buffer, o *objectType) {
+	defer func() { g.defined[o] = true }()
+
+	// Use the functional syntax if any of the object's keys are not valid Python identifiers.
+	functional := false
+	for _, f := range o.fields {
+		if !isPythonIdentifier(f.key) {
+			functional = true
+			break
+		}
+	}
+
+	if functional {
+		fmt.Fprintf(w, "%v = TypedDict(\n    %v,\n    {\n", o.name, jsonString(o.name))
+		for _, f := range o.fields {
+			fmt.Fprintf(w, "        %v: %v,\n", jsonString(f.key), g.fieldType(f))
+		}
+		w.WriteString("    },\n)\n")
+		if lines := docLines(o.description); len(lines) != 0 {
+			fmt.Fprintf(w, "%v.__doc__ = %v\n", o.name, jsonString(strings.Join(lines, "\n")))
+		}
+		return
+	}
+
+	fmt.Fprintf(w, "class %v(TypedDict):\n", o.name)
+	hasDoc := writeDocstring(w, "    ", o.description)
+	if len(o.fields) == 0 {
+		if !hasDoc {
+			w.WriteString("    pass\n")
+		}
+		return
+	}
+	if hasDoc {
+		w.WriteString("\n")
+	}
+	for _, f := range o.fields {
+		fmt.Fprintf(w, "    %v: %v\n", f.key, g.fieldType(f))
+		writeDocstring(w, "    ", f.description)
+	}
+}
+
+func (g *pythonGenerator) fieldType(f *field) string {
+	t := g.typeName(f.typ)
+	if !f.required {
+		t = fmt.Sprintf("%v[%v]", g.typing("NotRequired"), t)
+	}
+	return t
+}
+
+func (g *pythonGenerator) typeName(t *typeRef) string {
+	name := g.baseTypeName(t)
+	if t.secret {
+		name = "Secret[" + name + "]"
+	}
+	return name
+}
+
+func (g *pythonGenerator) baseTypeName(t *typeRef) string {
+	if literals, ok := pythonLiterals(t.literals); ok {
+		return fmt.Sprintf("%v[%v]", g.typing("Literal"), strings.Join(literals, ", "))
+	}
+
+	switch t.kind {
+	case kindString:
+		return "str"
+	case kindNumber:
+		return "float"
+	case kindBoolean:
+		return "bool"
+	case kindNull:
+		return "None"
+	case kindArray:
+		return fmt.Sprintf("%v[%v]", g.typing("List"), g.typeName(t.element))
+	case kindTuple:
+		tuple := g.typing("Tuple")
+		elements := mapSlice(t.elements, g.typeName)
+		if t.element != nil {
+			elements = append(elements, fmt.Sprintf("*%v[%v, ...]", tuple, g.typeName(t.element)))
+		}
+		if len(elements) == 0 {
+			elements = []string{"()"}
+		}
+		return fmt.Sprintf("%v[%v]", tuple, strings.Join(elements, ", "))
+	case kindMap:
+		return fmt.Sprintf("%v[str, %v]", g.typing("Dict"), g.typeName(t.element))
+	case kindObject:
+		if !g.defined[t.object] {
+			return jsonString(t.object.name)
+		}
+		return t.object.name
+	case kindUnion:
+		return fmt.Sprintf("%v[%v]", g.typing("Union"), strings.Join(mapSlice(t.members, g.typeName), ", "))
+	default:
+		return g.typing("Any")
+	}
+}
+
+// pythonLiterals returns the Python representations of a set of literal values. Python's Literal type does not
+// support non-integral numbers, so the literals are only usable if they are all strings, integers, booleans, or None.
+func pythonLiterals(values []any) ([]string, bool) {
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	literals := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+			literals[i] = "None"
+		case bool:
+			literals[i] = "False"
+			if v {
+				literals[i] = "True"
+			}
+		case string:
+			literals[i] = jsonString(v)
+		default:
+			s := jsonString(v)
+			if !pythonIntegerRegexp.MatchString(s) {
+				return nil, false
+			}
+			literals[i] = s
+		}
+	}
+	return literals, true
+}
+
+var pythonIntegerRegexp = regexp.MustCompile(`^-?[0-9]+$`)
+
+var pythonIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true, "async": true, "await": true,
+	"break": true, "class": true, "continue": true, "def": true, "del": true, "elif": true, "else": true,
+	"except": true, "finally": true, "for": true, "from": true, "global": true, "if": true, "import": true,
+	"in": true, "is": true, "lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// isPythonIdentifier returns true if the given name is a valid Python identifier that is not a keyword.
+func isPythonIdentifier(name string) bool {
+	return pythonIdentifierRegexp.MatchString(name) && !pythonKeywords[name]
+}
+
+// writeDocstring writes a docstring for the given description, if any, and returns true if a docstring was written.
+func writeDocstring(w *bytes.Buffer, indent, description string) bool {
+	lines := docLines(description)
+	if len(lines) == 0 {
+		return false
+	}
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(strings.ReplaceAll(line, `\`, `\\`), `"""`, `\"\"\"`)
+	}
+
+	if len(lines) == 1 {
+		fmt.Fprintf(w, "%v\"\"\"%v\"\"\"\n", indent, lines[0])
+		return true
+	}
+	fmt.Fprintf(w, "%v\"\"\"%v\n", indent, lines[0])
+	for _, line := range lines[1:] {
+		if line == "" {
+			w.WriteString("\n")
+		} else {
+			fmt.Fprintf(w, "%v%v\n", indent, line)
+		}
+	}
+	fmt.Fprintf(w, "%v\"\"\"\n", indent)
+	return true
+}
@@ -0,0 +1,53 @@
+// Code generated by esc env codegen. DO NOT EDIT.
+
+/** A secret value. */
+export type Secret<T> = T;
+
+/** Environment holds the configuration of the web service. */
+export interface Environment {
+    "9lives"?: string;
+    aws: Aws;
+    creds?: Label;
+    debug?: boolean;
+    environmentVariables: Record<string, string>;
+    labels?: Record<string, Label>;
+    mixed?: string | number;
+    nine_lives?: string;
+    port: 8080;
+    /** The AWS region to deploy to. */
+    region: "us-west-2";
+    servers: [ServersItem1, ServersItem2];
+    tags: ["web", "prod"];
+    unknown?: unknown;
+}
+
+export interface Aws {
+    /**
+     * Temporary AWS credentials.
+     * Rotated hourly.
+     */
+    creds: Creds;
+}
+
+/**
+ * Temporary AWS credentials.
+ * Rotated hourly.
+ */
+export interface Creds {
+    accessKeyId: string;
+    secretAccessKey: Secret<string>;
+    sessionToken?: Secret<string>;
+}
+
+export interface Label {
+    value: string;
+}
+
+export interface ServersItem1 {
+    host: string;
+    weight: number;
+}
+
+export interface ServersItem2 {
+    host: string;
+}
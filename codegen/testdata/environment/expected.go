@@ -0,0 +1,85 @@
+// Code generated by esc env codegen. DO NOT EDIT.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulumi/esc"
+)
+
+// Secret holds a secret value. Formatting a Secret with the fmt package prints "[secret]" rather than the value.
+type Secret[T any] struct {
+	Value T
+}
+
+// String returns a redacted representation of the secret.
+func (s Secret[T]) String() string { return "[secret]" }
+
+// GoString returns a redacted representation of the secret.
+func (s Secret[T]) GoString() string { return "[secret]" }
+
+// MarshalJSON marshals the secret's value.
+func (s Secret[T]) MarshalJSON() ([]byte, error) { return json.Marshal(s.Value) }
+
+// UnmarshalJSON unmarshals the secret's value.
+func (s *Secret[T]) UnmarshalJSON(b []byte) error { return json.Unmarshal(b, &s.Value) }
+
+// Load decodes the value of an opened environment into Environment.
+func Load(v esc.Value) (*Environment, error) {
+	bytes, err := json.Marshal(v.ToJSON(false))
+	if err != nil {
+		return nil, fmt.Errorf("encoding environment: %w", err)
+	}
+	var env Environment
+	if err := json.Unmarshal(bytes, &env); err != nil {
+		return nil, fmt.Errorf("decoding environment: %w", err)
+	}
+	return &env, nil
+}
+
+// Environment holds the configuration of the web service.
+type Environment struct {
+	X9lives              *string           `json:"9lives,omitempty"`
+	Aws                  Aws               `json:"aws"`
+	Creds                *Label            `json:"creds,omitempty"`
+	Debug                *bool             `json:"debug,omitempty"`
+	EnvironmentVariables map[string]string `json:"environmentVariables"`
+	Labels               map[string]Label  `json:"labels,omitempty"`
+	Mixed                any               `json:"mixed,omitempty"`
+	NineLives            *string           `json:"nine_lives,omitempty"`
+	Port                 float64           `json:"port"`
+
+	// The AWS region to deploy to.
+	Region  string        `json:"region"`
+	Servers []ServersItem `json:"servers"`
+	Tags    []string      `json:"tags"`
+	Unknown any           `json:"unknown,omitempty"`
+}
+
+// Aws holds the values of an object within an environment.
+type Aws struct {
+	// Temporary AWS credentials.
+	// Rotated hourly.
+	Creds Creds `json:"creds"`
+}
+
+// Temporary AWS credentials.
+// Rotated hourly.
+type Creds struct {
+	AccessKeyId     string          `json:"accessKeyId"`
+	SecretAccessKey Secret[string]  `json:"secretAccessKey"`
+	SessionToken    *Secret[string] `json:"sessionToken,omitempty"`
+}
+
+// Label holds the values of an object within an environment.
+type Label struct {
+	Value string `json:"value"`
+}
+
+// ServersItem holds the values of an object within an environment.
+type ServersItem struct {
+	Host   string   `json:"host"`
+	Weight *float64 `json:"weight,omitempty"`
+}
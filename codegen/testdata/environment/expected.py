@@ -0,0 +1,59 @@
+# Code generated by esc env codegen. DO NOT EDIT.
+
+from typing import Annotated, Any, Dict, Literal, NotRequired, Tuple, TypeVar, TypedDict, Union
+
+T = TypeVar("T")
+
+Secret = Annotated[T, "secret"]
+"""A secret value."""
+
+
+class ServersItem2(TypedDict):
+    host: str
+
+
+class ServersItem1(TypedDict):
+    host: str
+    weight: float
+
+
+class Label(TypedDict):
+    value: str
+
+
+class Creds(TypedDict):
+    """Temporary AWS credentials.
+    Rotated hourly.
+    """
+
+    accessKeyId: str
+    secretAccessKey: Secret[str]
+    sessionToken: NotRequired[Secret[str]]
+
+
+class Aws(TypedDict):
+    creds: Creds
+    """Temporary AWS credentials.
+    Rotated hourly.
+    """
+
+
+Environment = TypedDict(
+    "Environment",
+    {
+        "9lives": NotRequired[str],
+        "aws": Aws,
+        "creds": NotRequired[Label],
+        "debug": NotRequired[bool],
+        "environmentVariables": Dict[str, str],
+        "labels": NotRequired[Dict[str, Label]],
+        "mixed": NotRequired[Union[str, float]],
+        "nine_lives": NotRequired[str],
+        "port": Literal[8080],
+        "region": Literal["us-west-2"],
+        "servers": Tuple[ServersItem1, ServersItem2],
+        "tags": Tuple[Literal["web"], Literal["prod"]],
+        "unknown": NotRequired[Any],
+    },
+)
+Environment.__doc__ = "Environment holds the configuration of the web service."
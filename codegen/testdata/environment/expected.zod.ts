@@ -0,0 +1,52 @@
+// Code generated by esc env codegen. DO NOT EDIT.
+
+import { z } from "zod";
+
+export const ServersItem2Schema = z.object({
+    host: z.string(),
+});
+
+export const ServersItem1Schema = z.object({
+    host: z.string(),
+    weight: z.number(),
+});
+
+export const LabelSchema = z.object({
+    value: z.string(),
+});
+
+/**
+ * Temporary AWS credentials.
+ * Rotated hourly.
+ */
+export const CredsSchema = z.object({
+    accessKeyId: z.string(),
+    secretAccessKey: z.string(),
+    sessionToken: z.string().optional(),
+});
+
+export const AwsSchema = z.object({
+    creds: CredsSchema,
+});
+
+/** Environment holds the configuration of the web service. */
+export const EnvironmentSchema = z.object({
+    "9lives": z.string().optional(),
+    aws: AwsSchema,
+    creds: LabelSchema.optional(),
+    debug: z.boolean().optional(),
+    environmentVariables: z.record(z.string(), z.string()),
+    labels: z.record(z.string(), LabelSchema).optional(),
+    mixed: z.union([z.string(), z.number()]).optional(),
+    nine_lives: z.string().optional(),
+    port: z.literal(8080),
+    region: z.literal("us-west-2"),
+    servers: z.tuple([ServersItem1Schema, ServersItem2Schema]),
+    tags: z.tuple([z.literal("web"), z.literal("prod")]),
+    unknown: z.unknown().optional(),
+});
+
+/** Parses and validates the value of an opened environment. */
+export function parseEnvironment(value: unknown): z.infer<typeof EnvironmentSchema> {
+    return EnvironmentSchema.parse(value);
+}
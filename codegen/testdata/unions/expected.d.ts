@@ -0,0 +1,32 @@
+// Code generated by esc env codegen. DO NOT EDIT.
+
+/** A secret value. */
+export type Secret<T> = T;
+
+export interface Environment {
+    class?: string;
+    command?: [string, ...string[]];
+    "content-type"?: string;
+    endpoint: string | EndpointOption2;
+    /** The deployment mode. */
+    mode: "dev" | "staging" | "prod";
+    nothing?: null;
+    point: [number, number];
+    ratio?: 0.5 | 1;
+    replicas?: 1 | 3 | 5;
+    token?: Secret<string>;
+    /** A node in a tree. */
+    tree: Node;
+    version?: "latest" | number | null;
+}
+
+export interface EndpointOption2 {
+    host: string;
+    port?: number;
+}
+
+/** A node in a tree. */
+export interface Node {
+    children?: Node[];
+    name: string;
+}
@@ -0,0 +1,66 @@
+// Code generated by esc env codegen. DO NOT EDIT.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulumi/esc"
+)
+
+// Secret holds a secret value. Formatting a Secret with the fmt package prints "[secret]" rather than the value.
+type Secret[T any] struct {
+	Value T
+}
+
+// String returns a redacted representation of the secret.
+func (s Secret[T]) String() string { return "[secret]" }
+
+// GoString returns a redacted representation of the secret.
+func (s Secret[T]) GoString() string { return "[secret]" }
+
+// MarshalJSON marshals the secret's value.
+func (s Secret[T]) MarshalJSON() ([]byte, error) { return json.Marshal(s.Value) }
+
+// UnmarshalJSON unmarshals the secret's value.
+func (s *Secret[T]) UnmarshalJSON(b []byte) error { return json.Unmarshal(b, &s.Value) }
+
+// Load decodes the value of an opened environment into Environment.
+func Load(v esc.Value) (*Environment, error) {
+	bytes, err := json.Marshal(v.ToJSON(false))
+	if err != nil {
+		return nil, fmt.Errorf("encoding environment: %w", err)
+	}
+	var env Environment
+	if err := json.Unmarshal(bytes, &env); err != nil {
+		return nil, fmt.Errorf("decoding environment: %w", err)
+	}
+	return &env, nil
+}
+
+// Environment holds the values of an environment.
+type Environment struct {
+	Class       *string  `json:"class,omitempty"`
+	Command     []string `json:"command,omitempty"`
+	ContentType *string  `json:"content-type,omitempty"`
+	Endpoint    any      `json:"endpoint"`
+
+	// The deployment mode.
+	Mode     string          `json:"mode"`
+	Nothing  any             `json:"nothing,omitempty"`
+	Point    []float64       `json:"point"`
+	Ratio    *float64        `json:"ratio,omitempty"`
+	Replicas *float64        `json:"replicas,omitempty"`
+	Token    *Secret[string] `json:"token,omitempty"`
+
+	// A node in a tree.
+	Tree    Node `json:"tree"`
+	Version any  `json:"version,omitempty"`
+}
+
+// A node in a tree.
+type Node struct {
+	Children []Node `json:"children,omitempty"`
+	Name     string `json:"name"`
+}
@@ -0,0 +1,39 @@
+# Code generated by esc env codegen. DO NOT EDIT.
+
+from typing import Annotated, List, Literal, NotRequired, Tuple, TypeVar, TypedDict, Union
+
+T = TypeVar("T")
+
+Secret = Annotated[T, "secret"]
+"""A secret value."""
+
+
+class Node(TypedDict):
+    """A node in a tree."""
+
+    children: NotRequired[List["Node"]]
+    name: str
+
+
+class EndpointOption2(TypedDict):
+    host: str
+    port: NotRequired[float]
+
+
+Environment = TypedDict(
+    "Environment",
+    {
+        "class": NotRequired[str],
+        "command": NotRequired[Tuple[str, *Tuple[str, ...]]],
+        "content-type": NotRequired[str],
+        "endpoint": Union[str, EndpointOption2],
+        "mode": Literal["dev", "staging", "prod"],
+        "nothing": NotRequired[None],
+        "point": Tuple[float, float],
+        "ratio": NotRequired[float],
+        "replicas": NotRequired[Literal[1, 3, 5]],
+        "token": NotRequired[Secret[str]],
+        "tree": Node,
+        "version": NotRequired[Union[Literal["latest"], float, None]],
+    },
+)
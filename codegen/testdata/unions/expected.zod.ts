@@ -0,0 +1,43 @@
+// Code generated by esc env codegen. DO NOT EDIT.
+
+import { z } from "zod";
+
+/** A secret value. */
+export type Secret<T> = T;
+
+/** A node in a tree. */
+export interface Node {
+    children?: Node[];
+    name: string;
+}
+
+/** A node in a tree. */
+export const NodeSchema: z.ZodType<Node> = z.object({
+    children: z.array(z.lazy(() => NodeSchema)).optional(),
+    name: z.string(),
+});
+
+export const EndpointOption2Schema = z.object({
+    host: z.string(),
+    port: z.number().optional(),
+});
+
+export const EnvironmentSchema = z.object({
+    class: z.string().optional(),
+    command: z.tuple([z.string()]).rest(z.string()).optional(),
+    "content-type": z.string().optional(),
+    endpoint: z.union([z.string(), EndpointOption2Schema]),
+    mode: z.union([z.literal("dev"), z.literal("staging"), z.literal("prod")]),
+    nothing: z.null().optional(),
+    point: z.tuple([z.number(), z.number()]),
+    ratio: z.union([z.literal(0.5), z.literal(1)]).optional(),
+    replicas: z.union([z.literal(1), z.literal(3), z.literal(5)]).optional(),
+    token: z.string().optional(),
+    tree: NodeSchema,
+    version: z.union([z.literal("latest"), z.number(), z.null()]).optional(),
+});
+
+/** Parses and validates the value of an opened environment. */
+export function parseEnvironment(value: unknown): z.infer<typeof EnvironmentSchema> {
+    return EnvironmentSchema.parse(value);
+}
@@ -0,0 +1,319 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codegen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/esc/schema"
+)
+
+// TypeScriptOptions controls the generation of TypeScript code.
+type TypeScriptOptions struct {
+	// TypeName is the name of the generated type for the environment's root value. Defaults to "Environment".
+	TypeName string
+
+	// Inferred is true if the schema was inferred from an environment's values (e.g. by checking the environment)
+	// rather than declared. The constants and tuple elements of an inferred schema describe the environment's current
+	// values rather than their types, so they are not represented in the generated types.
+	Inferred bool
+}
+
+func (opts *TypeScriptOptions) model(s *schema.Schema) (*model, error) {
+	if opts.TypeName == "" {
+		opts.TypeName = "Environment"
+	}
+	options := modelOptions{literals: true, consts: !opts.Inferred, tuples: !opts.Inferred, unions: true}
+	return newModel(s, options, opts.TypeName, "Secret")
+}
+
+// GenerateTypeScript writes TypeScript declarations (i.e. the contents of a .d.ts file) for the values described by
+// an environment's schema to w. Each object is generated as an interface. Secret values are marked with the Secret
+// type.
+func GenerateTypeScript(w io.Writer, s *schema.Schema, opts TypeScriptOptions) error {
+	m, err := opts.model(s)
+	if err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+	b.WriteString("// Code generated by esc env codegen. DO NOT EDIT.\n\n")
+	b.WriteString("/** A secret value. */\n")
+	b.WriteString("export type Secret<T> = T;\n")
+
+	for _, o := range m.objects {
+		b.WriteString("\n")
+		writeTSInterface(b, o)
+	}
+	return b.Flush()
+}
+
+// writeTSInterface writes the declaration of an object type as an interface.
+func writeTSInterface(b *bufio.Writer, o *objectType) {
+	writeJSDoc(b, "", o.description)
+	fmt.Fprintf(b, "export interface %v {\n", o.name)
+	for _, f := range o.fields {
+		writeJSDoc(b, "    ", f.description)
+		optional := ""
+		if !f.required {
+			optional = "?"
+		}
+		fmt.Fprintf(b, "    %v%v: %v;\n", tsPropertyName(f.key), optional, tsTypeName(f.typ))
+	}
+	b.WriteString("}\n")
+}
+
+// GenerateTypeScriptValidator writes a TypeScript module to w that validates the values described by an environment's
+// schema at runtime using zod. Each object type is generated as a zod schema named after the object type with a
+// Schema suffix. The module exports a parse function for the root type, e.g. parseEnvironment.
+func GenerateTypeScriptValidator(w io.Writer, s *schema.Schema, opts TypeScriptOptions) error {
+	m, err := opts.model(s)
+	if err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+	b.WriteString("// Code generated by esc env codegen. DO NOT EDIT.\n\n")
+	b.WriteString("import { z } from \"zod\";\n")
+
+	// Generate the object schemas in reverse order so that each schema is defined before the schemas that reference
+	// it. References to schemas that have not been defined yet (i.e. recursive references) are lazy.
+	g := zodGenerator{defined: map[*objectType]bool{}, lazy: map[*objectType]bool{}}
+	bodies := make([]string, len(m.objects))
+	for i := len(m.objects) - 1; i >= 0; i-- {
+		o := m.objects[i]
+
+		var body strings.Builder
+		for _, f := range o.fields {
+			t := g.schema(f.typ)
+			if !f.required {
+				t += ".optional()"
+			}
+			fmt.Fprintf(&body, "    %v: %v,\n", tsPropertyName(f.key), t)
+		}
+		bodies[i] = body.String()
+		g.defined[o] = true
+	}
+
+	// TypeScript cannot infer the types of recursive definitions, so the schemas of lazily-referenced objects are
+	// annotated with their types. Declare those types and the types they reference.
+	if declared := reachableObjects(m.objects, g.lazy); len(declared) != 0 {
+		b.WriteString("\n/** A secret value. */\n")
+		b.WriteString("export type Secret<T> = T;\n")
+		for _, o := range declared {
+			b.WriteString("\n")
+			writeTSInterface(b, o)
+		}
+	}
+
+	for i := len(m.objects) - 1; i >= 0; i-- {
+		o := m.objects[i]
+
+		annotation := ""
+		if g.lazy[o] {
+			annotation = fmt.Sprintf(": z.ZodType<%v>", o.name)
+		}
+
+		b.WriteString("\n")
+		writeJSDoc(b, "", o.description)
+		fmt.Fprintf(b, "export const %vSchema%v = z.object({\n%v});\n", o.name, annotation, bodies[i])
+	}
+
+	root := m.root.name
+	fmt.Fprintf(b, "\n/** Parses and validates the value of an opened environment. */\n")
+	fmt.Fprintf(b, "export function parse%v(value: unknown): z.infer<typeof %vSchema> {\n", root, root)
+	fmt.Fprintf(b, "    return %vSchema.parse(value);\n", root)
+	b.WriteString("}\n")
+	return b.Flush()
+}
+
+type zodGenerator struct {
+	defined map[*objectType]bool
+	lazy    map[*objectType]bool // the objects that are referenced before they are defined
+}
+
+// reachableObjects returns the objects that are reachable from the given roots, in the order in which they appear in
+// objects.
+func reachableObjects(objects []*objectType, roots map[*objectType]bool) []*objectType {
+	reachable := map[*objectType]bool{}
+	var visit func(t *typeRef)
+	visit = func(t *typeRef) {
+		if t == nil {
+			return
+		}
+		if t.object != nil && !reachable[t.object] {
+			reachable[t.object] = true
+			for _, f := range t.object.fields {
+				visit(f.typ)
+			}
+		}
+		visit(t.element)
+		for _, e := range t.elements {
+			visit(e)
+		}
+		for _, m := range t.members {
+			visit(m)
+		}
+	}
+	for o := range roots {
+		visit(&typeRef{kind: kindObject, object: o})
+	}
+
+	var result []*objectType
+	for _, o := range objects {
+		if reachable[o] {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+func (g *zodGenerator) schema(t *typeRef) string {
+	if len(t.literals) != 0 {
+		return zodUnion(mapSlice(t.literals, func(v any) string {
+			return fmt.Sprintf("z.literal(%v)", tsLiteral(v))
+		}))
+	}
+
+	switch t.kind {
+	case kindString:
+		return "z.string()"
+	case kindNumber:
+		return "z.number()"
+	case kindBoolean:
+		return "z.boolean()"
+	case kindNull:
+		return "z.null()"
+	case kindArray:
+		return fmt.Sprintf("z.array(%v)", g.schema(t.element))
+	case kindTuple:
+		tuple := fmt.Sprintf("z.tuple([%v])", strings.Join(mapSlice(t.elements, g.schema), ", "))
+		if t.element != nil {
+			tuple += fmt.Sprintf(".rest(%v)", g.schema(t.element))
+		}
+		return tuple
+	case kindMap:
+		return fmt.Sprintf("z.record(z.string(), %v)", g.schema(t.element))
+	case kindObject:
+		if !g.defined[t.object] {
+			g.lazy[t.object] = true
+			return fmt.Sprintf("z.lazy(() => %vSchema)", t.object.name)
+		}
+		return t.object.name + "Schema"
+	case kindUnion:
+		return zodUnion(mapSlice(t.members, g.schema))
+	default:
+		return "z.unknown()"
+	}
+}
+
+func zodUnion(members []string) string {
+	if len(members) == 1 {
+		return members[0]
+	}
+	return fmt.Sprintf("z.union([%v])", strings.Join(members, ", "))
+}
+
+func tsTypeName(t *typeRef) string {
+	name := tsBaseTypeName(t)
+	if t.secret {
+		name = "Secret<" + name + ">"
+	}
+	return name
+}
+
+func tsBaseTypeName(t *typeRef) string {
+	if len(t.literals) != 0 {
+		return strings.Join(mapSlice(t.literals, tsLiteral), " | ")
+	}
+
+	switch t.kind {
+	case kindString:
+		return "string"
+	case kindNumber:
+		return "number"
+	case kindBoolean:
+		return "boolean"
+	case kindNull:
+		return "null"
+	case kindArray:
+		element := tsTypeName(t.element)
+		if strings.Contains(element, " | ") {
+			element = "(" + element + ")"
+		}
+		return element + "[]"
+	case kindTuple:
+		elements := mapSlice(t.elements, tsTypeName)
+		if t.element != nil {
+			rest := tsTypeName(t.element)
+			if strings.Contains(rest, " | ") {
+				rest = "(" + rest + ")"
+			}
+			elements = append(elements, "..."+rest+"[]")
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case kindMap:
+		return fmt.Sprintf("Record<string, %v>", tsTypeName(t.element))
+	case kindObject:
+		return t.object.name
+	case kindUnion:
+		return strings.Join(mapSlice(t.members, tsTypeName), " | ")
+	default:
+		return "unknown"
+	}
+}
+
+// tsLiteral returns the TypeScript representation of a literal value.
+func tsLiteral(v any) string {
+	if v == nil {
+		return "null"
+	}
+	return jsonString(v)
+}
+
+var tsIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsPropertyName returns the TypeScript representation of a property name. Names that are not identifiers are quoted.
+func tsPropertyName(key string) string {
+	if tsIdentifierRegexp.MatchString(key) {
+		return key
+	}
+	return jsonString(key)
+}
+
+// writeJSDoc writes a JSDoc comment for the given description, if any.
+func writeJSDoc(w io.StringWriter, indent, description string) {
+	lines := docLines(description)
+	switch len(lines) {
+	case 0:
+		return
+	case 1:
+		w.WriteString(fmt.Sprintf("%v/** %v */\n", indent, escapeComment(lines[0])))
+	default:
+		w.WriteString(indent + "/**\n")
+		for _, line := range lines {
+			w.WriteString(strings.TrimRight(fmt.Sprintf("%v * %v", indent, escapeComment(line)), " ") + "\n")
+		}
+		w.WriteString(indent + " */\n")
+	}
+}
+
+func escapeComment(line string) string {
+	return strings.ReplaceAll(line, "*/", "*\\/")
+}
@@ -0,0 +1,408 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package esc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/esc/internal/util"
+	"golang.org/x/exp/maps"
+)
+
+// DecodeOptions controls the behavior of Value.DecodeWithOptions.
+type DecodeOptions struct {
+	// RequireSecretTags causes decoding to fail if a secret value is decoded into a field that is not tagged as
+	// secret. Values decoded into an esc.Value are exempt, as they retain their secretness.
+	RequireSecretTags bool
+
+	// DisallowUnknownFields causes decoding to fail if an object contains a property that does not correspond to a
+	// field of the destination struct.
+	DisallowUnknownFields bool
+}
+
+// A DecodeError describes a failure to decode a value.
+type DecodeError struct {
+	// Path is the property path of the value that could not be decoded, e.g. `aws.creds["access-key"]` or
+	// `servers[0].host`. The path is empty for the root value.
+	Path string
+
+	// Message describes the failure.
+	Message string
+}
+
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%v: %v", e.Path, e.Message)
+}
+
+// Decode decodes the value into the Go value pointed to by target. Decode is shorthand for DecodeWithOptions with
+// the zero DecodeOptions.
+func (v Value) Decode(target any) error {
+	return v.DecodeWithOptions(target, DecodeOptions{})
+}
+
+// DecodeWithOptions decodes the value into the Go value pointed to by target.
+//
+// Objects decode into structs and into maps with string keys. Struct fields are matched with properties using the
+// field's `esc` tag, or its `json` tag if it has no `esc` tag, or its name. As with encoding/json, a tag of "-" omits
+// a field and the fields of embedded structs are promoted. Arrays decode into slices and arrays, numbers decode into
+// any numeric type or json.Number, and strings decode into strings and types that implement
+// encoding.TextUnmarshaler. Null values decode into the zero value. Values decode into esc.Value fields as is and into
+// interface fields as plain-old-JSON values (see Value.ToJSON).
+//
+// A field whose tag has the "secret" option (e.g. `esc:"password,secret"`) only accepts secret values. If
+// opts.RequireSecretTags is set, secret values are only accepted by fields with the "secret" option.
+//
+// Errors are reported as *DecodeError values that carry the property path of the value that failed to decode.
+func (v Value) DecodeWithOptions(target any, opts DecodeOptions) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, not %T", target)
+	}
+
+	d := decoder{options: opts}
+	return d.decode("", v, rv.Elem(), false)
+}
+
+type decoder struct {
+	options DecodeOptions
+}
+
+var (
+	valueType           = reflect.TypeOf(Value{})
+	numberType          = reflect.TypeOf(json.Number(""))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// decode decodes v into dest. If secret is true, dest is (or is contained in) a field tagged as secret.
+func (d *decoder) decode(path string, v Value, dest reflect.Value, secret bool) error {
+	if dest.Type() == valueType {
+		dest.Set(reflect.ValueOf(v))
+		return nil
+	}
+
+	if v.Secret && !secret && d.options.RequireSecretTags {
+		return d.errorf(path, "secret value cannot be decoded into a field that is not tagged as secret")
+	}
+	if v.Unknown {
+		return d.errorf(path, "value is unknown")
+	}
+
+	if v.Value == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+
+	if dest.Kind() == reflect.Pointer {
+		if dest.IsNil() {
+			dest.Set(reflect.New(dest.Type().Elem()))
+		}
+		return d.decode(path, v, dest.Elem(), secret)
+	}
+
+	if s, ok := v.Value.(string); ok && reflect.PointerTo(dest.Type()).Implements(textUnmarshalerType) {
+		if err := dest.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return d.errorf(path, "%v", err)
+		}
+		return nil
+	}
+
+	switch dest.Kind() {
+	case reflect.Interface:
+		if dest.NumMethod() != 0 {
+			break
+		}
+		dest.Set(reflect.ValueOf(v.ToJSON(false)))
+		return nil
+	case reflect.Bool:
+		if b, ok := v.Value.(bool); ok {
+			dest.SetBool(b)
+			return nil
+		}
+	case reflect.String:
+		switch repr := v.Value.(type) {
+		case string:
+			dest.SetString(repr)
+			return nil
+		case json.Number:
+			if dest.Type() == numberType {
+				dest.SetString(string(repr))
+				return nil
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := v.Value.(json.Number); ok {
+			i, err := strconv.ParseInt(string(n), 10, dest.Type().Bits())
+			if err != nil {
+				return d.errorf(path, "cannot decode %v into a value of type %v", n, dest.Type())
+			}
+			dest.SetInt(i)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n, ok := v.Value.(json.Number); ok {
+			u, err := strconv.ParseUint(string(n), 10, dest.Type().Bits())
+			if err != nil {
+				return d.errorf(path, "cannot decode %v into a value of type %v", n, dest.Type())
+			}
+			dest.SetUint(u)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, ok := v.Value.(json.Number); ok {
+			f, err := strconv.ParseFloat(string(n), dest.Type().Bits())
+			if err != nil {
+				return d.errorf(path, "cannot decode %v into a value of type %v", n, dest.Type())
+			}
+			dest.SetFloat(f)
+			return nil
+		}
+	case reflect.Slice:
+		if arr, ok := v.Value.([]Value); ok {
+			slice := reflect.MakeSlice(dest.Type(), len(arr), len(arr))
+			for i, e := range arr {
+				if err := d.decode(fmt.Sprintf("%v[%v]", path, i), e, slice.Index(i), secret); err != nil {
+					return err
+				}
+			}
+			dest.Set(slice)
+			return nil
+		}
+	case reflect.Array:
+		if arr, ok := v.Value.([]Value); ok {
+			if len(arr) != dest.Len() {
+				return d.errorf(path, "cannot decode an array of length %v into a value of type %v", len(arr), dest.Type())
+			}
+			for i, e := range arr {
+				if err := d.decode(fmt.Sprintf("%v[%v]", path, i), e, dest.Index(i), secret); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case reflect.Map:
+		if obj, ok := v.Value.(map[string]Value); ok && dest.Type().Key().Kind() == reflect.String {
+			return d.decodeMap(path, obj, dest, secret)
+		}
+	case reflect.Struct:
+		if obj, ok := v.Value.(map[string]Value); ok {
+			return d.decodeStruct(path, obj, dest, secret)
+		}
+	}
+
+	return d.errorf(path, "cannot decode %v into a value of type %v", typeDescription(v), dest.Type())
+}
+
+func (d *decoder) decodeMap(path string, obj map[string]Value, dest reflect.Value, secret bool) error {
+	t := dest.Type()
+	if dest.IsNil() {
+		dest.Set(reflect.MakeMapWithSize(t, len(obj)))
+	}
+
+	keys := maps.Keys(obj)
+	sort.Strings(keys)
+	for _, k := range keys {
+		elem := reflect.New(t.Elem()).Elem()
+		if err := d.decode(util.JoinKey(path, k), obj[k], elem, secret); err != nil {
+			return err
+		}
+		dest.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), elem)
+	}
+	return nil
+}
+
+func (d *decoder) decodeStruct(path string, obj map[string]Value, dest reflect.Value, secret bool) error {
+	fields := structFields(dest.Type())
+
+	keys := maps.Keys(obj)
+	sort.Strings(keys)
+	for _, k := range keys {
+		keyPath := util.JoinKey(path, k)
+
+		f, ok := fields[k]
+		if !ok {
+			if d.options.DisallowUnknownFields {
+				return d.errorf(keyPath, "unknown property")
+			}
+			continue
+		}
+
+		v := obj[k]
+		if f.secret && !v.Secret {
+			return d.errorf(keyPath, "field %v is tagged as secret, but the value is not secret", f.name)
+		}
+
+		fv, err := fieldByIndex(dest, f.index)
+		if err != nil {
+			return d.errorf(keyPath, "%v", err)
+		}
+		if err := d.decode(keyPath, v, fv, secret || f.secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *decoder) errorf(path, format string, args ...any) error {
+	return &DecodeError{Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+// fieldByIndex returns the field of a struct with the given index, allocating embedded struct pointers as necessary.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i != 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %v", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// A structField describes a struct field that may be decoded into.
+type structField struct {
+	name   string // the field's Go name
+	index  []int  // the field's index sequence
+	secret bool   // true if the field is tagged as secret
+	tagged bool   // true if the field's name came from a tag
+}
+
+// structFields returns the fields of a struct type keyed by property name. The fields of embedded structs are
+// promoted following the rules of encoding/json: shallower fields take precedence over deeper fields, and tagged
+// fields take precedence over untagged fields at the same depth. Ambiguous fields are dropped.
+func structFields(t reflect.Type) map[string]structField {
+	type candidate struct {
+		structField
+		depth int
+	}
+	candidates := map[string][]candidate{}
+
+	var walk func(t reflect.Type, index []int, visited map[reflect.Type]bool)
+	walk = func(t reflect.Type, index []int, visited map[reflect.Type]bool) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		defer delete(visited, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+
+			name, options, tagged := fieldTag(sf)
+			if name == "-" && options == "" {
+				continue
+			}
+			fieldIndex := append(index[:len(index):len(index)], i)
+
+			if sf.Anonymous && !tagged {
+				ft := sf.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					walk(ft, fieldIndex, visited)
+					continue
+				}
+			}
+			if !sf.IsExported() {
+				continue
+			}
+
+			if name == "" {
+				name = sf.Name
+			}
+			candidates[name] = append(candidates[name], candidate{
+				structField: structField{
+					name:   sf.Name,
+					index:  fieldIndex,
+					secret: hasTagOption(options, "secret"),
+					tagged: tagged,
+				},
+				depth: len(fieldIndex),
+			})
+		}
+	}
+	walk(t, nil, map[reflect.Type]bool{})
+
+	fields := make(map[string]structField, len(candidates))
+	for name, cs := range candidates {
+		sort.SliceStable(cs, func(i, j int) bool {
+			if cs[i].depth != cs[j].depth {
+				return cs[i].depth < cs[j].depth
+			}
+			return cs[i].tagged && !cs[j].tagged
+		})
+		if len(cs) > 1 && cs[0].depth == cs[1].depth && cs[0].tagged == cs[1].tagged {
+			continue
+		}
+		fields[name] = cs[0].structField
+	}
+	return fields
+}
+
+// fieldTag returns the name and options of a struct field's `esc` tag, falling back to its `json` tag.
+func fieldTag(sf reflect.StructField) (name, options string, tagged bool) {
+	tag, ok := sf.Tag.Lookup("esc")
+	if !ok {
+		tag, ok = sf.Tag.Lookup("json")
+	}
+	if !ok {
+		return "", "", false
+	}
+	name, options, _ = strings.Cut(tag, ",")
+	return name, options, name != ""
+}
+
+func hasTagOption(options, option string) bool {
+	for options != "" {
+		var o string
+		o, options, _ = strings.Cut(options, ",")
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// typeDescription returns a description of the type of a value for use in error messages.
+func typeDescription(v Value) string {
+	switch v.Value.(type) {
+	case bool:
+		return "a boolean"
+	case json.Number:
+		return "a number"
+	case string:
+		return "a string"
+	case []Value:
+		return "an array"
+	case map[string]Value:
+		return "an object"
+	default:
+		panic("invalid value")
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package esc
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeCreds struct {
+	AccessKeyID     string  `json:"accessKeyId"`
+	SecretAccessKey string  `esc:"secretAccessKey,secret"`
+	SessionToken    *string `esc:"sessionToken,secret"`
+}
+
+type decodeBase struct {
+	Region string `json:"region"`
+}
+
+type decodeConfig struct {
+	decodeBase
+
+	Port     int               `json:"port"`
+	Ratio    float32           `json:"ratio"`
+	Count    json.Number       `json:"count"`
+	Debug    bool              `json:"debug"`
+	Tags     []string          `json:"tags"`
+	Point    [2]uint8          `json:"point"`
+	Labels   map[string]string `json:"labels"`
+	Creds    decodeCreds       `json:"creds"`
+	Address  netip.Addr        `json:"address"`
+	Raw      Value             `json:"raw"`
+	Extra    any               `json:"extra"`
+	Missing  *string           `json:"missing"`
+	Ignored  string            `json:"-"`
+	Untagged string
+}
+
+func TestDecode(t *testing.T) {
+	v := NewValue(map[string]Value{
+		"region": NewValue("us-west-2"),
+		"port":   NewValue(json.Number("8080")),
+		"ratio":  NewValue(json.Number("0.5")),
+		"count":  NewValue(json.Number("42")),
+		"debug":  NewValue(true),
+		"tags":   NewValue([]Value{NewValue("web"), NewValue("prod")}),
+		"point":  NewValue([]Value{NewValue(json.Number("1")), NewValue(json.Number("2"))}),
+		"labels": NewValue(map[string]Value{"team": NewValue("infra")}),
+		"creds": NewValue(map[string]Value{
+			"accessKeyId":     NewValue("AKIA"),
+			"secretAccessKey": NewSecret("hunter2"),
+			"sessionToken":    NewSecret("token"),
+		}),
+		"address":  NewValue("10.0.0.1"),
+		"raw":      NewSecret("raw"),
+		"extra":    NewValue(map[string]Value{"n": NewValue(json.Number("1"))}),
+		"missing":  {},
+		"Ignored":  NewValue("ignored"),
+		"Untagged": NewValue("untagged"),
+		"unknown":  NewValue("unknown"),
+	})
+
+	var actual decodeConfig
+	require.NoError(t, v.Decode(&actual))
+
+	token := "token"
+	expected := decodeConfig{
+		decodeBase: decodeBase{Region: "us-west-2"},
+		Port:       8080,
+		Ratio:      0.5,
+		Count:      "42",
+		Debug:      true,
+		Tags:       []string{"web", "prod"},
+		Point:      [2]uint8{1, 2},
+		Labels:     map[string]string{"team": "infra"},
+		Creds:      decodeCreds{AccessKeyID: "AKIA", SecretAccessKey: "hunter2", SessionToken: &token},
+		Address:    netip.MustParseAddr("10.0.0.1"),
+		Raw:        NewSecret("raw"),
+		Extra:      map[string]any{"n": json.Number("1")},
+		Untagged:   "untagged",
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    Value
+		target   any
+		options  DecodeOptions
+		expected string
+	}{
+		{
+			name:     "type mismatch",
+			value:    NewValue(map[string]Value{"port": NewValue("http")}),
+			target:   &decodeConfig{},
+			expected: "port: cannot decode a string into a value of type int",
+		},
+		{
+			name:     "fractional integer",
+			value:    NewValue(map[string]Value{"point": NewValue([]Value{NewValue(json.Number("1")), NewValue(json.Number("2.5"))})}),
+			target:   &decodeConfig{},
+			expected: "point[1]: cannot decode 2.5 into a value of type uint8",
+		},
+		{
+			name:     "overflow",
+			value:    NewValue(map[string]Value{"point": NewValue([]Value{NewValue(json.Number("256")), NewValue(json.Number("2"))})}),
+			target:   &decodeConfig{},
+			expected: "point[0]: cannot decode 256 into a value of type uint8",
+		},
+		{
+			name:     "array length",
+			value:    NewValue(map[string]Value{"point": NewValue([]Value{NewValue(json.Number("1"))})}),
+			target:   &decodeConfig{},
+			expected: "point: cannot decode an array of length 1 into a value of type [2]uint8",
+		},
+		{
+			name:     "text unmarshaler",
+			value:    NewValue(map[string]Value{"address": NewValue("not an address")}),
+			target:   &decodeConfig{},
+			expected: `address: ParseAddr("not an address"): unable to parse IP`,
+		},
+		{
+			name:     "escaped path",
+			value:    NewValue(map[string]Value{"labels": NewValue(map[string]Value{"a.b": NewValue(true)})}),
+			target:   &decodeConfig{},
+			expected: `labels["a.b"]: cannot decode a boolean into a value of type string`,
+		},
+		{
+			name: "plaintext into secret",
+			value: NewValue(map[string]Value{"creds": NewValue(map[string]Value{
+				"secretAccessKey": NewValue("hunter2"),
+			})}),
+			target:   &decodeConfig{},
+			expected: "creds.secretAccessKey: field SecretAccessKey is tagged as secret, but the value is not secret",
+		},
+		{
+			name: "secret into plaintext",
+			value: NewValue(map[string]Value{"creds": NewValue(map[string]Value{
+				"accessKeyId": NewSecret("AKIA"),
+			})}),
+			target:   &decodeConfig{},
+			options:  DecodeOptions{RequireSecretTags: true},
+			expected: "creds.accessKeyId: secret value cannot be decoded into a field that is not tagged as secret",
+		},
+		{
+			name:     "unknown property",
+			value:    NewValue(map[string]Value{"bogus": NewValue(true)}),
+			target:   &decodeConfig{},
+			options:  DecodeOptions{DisallowUnknownFields: true},
+			expected: "bogus: unknown property",
+		},
+		{
+			name:     "unknown value",
+			value:    NewValue(map[string]Value{"region": {Unknown: true}}),
+			target:   &decodeConfig{},
+			expected: "region: value is unknown",
+		},
+		{
+			name:     "root",
+			value:    NewValue("hello"),
+			target:   &decodeConfig{},
+			expected: "cannot decode a string into a value of type esc.decodeConfig",
+		},
+		{
+			name:     "non-pointer",
+			value:    NewValue("hello"),
+			target:   "",
+			expected: "decode target must be a non-nil pointer, not string",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.value.DecodeWithOptions(c.target, c.options)
+			assert.EqualError(t, err, c.expected)
+		})
+	}
+}
+
+func TestDecodeRequireSecretTags(t *testing.T) {
+	v := NewValue(map[string]Value{
+		"creds": NewValue(map[string]Value{
+			"accessKeyId":     NewValue("AKIA"),
+			"secretAccessKey": NewSecret("hunter2"),
+		}),
+		"raw": NewSecret("raw"),
+	})
+
+	var actual decodeConfig
+	require.NoError(t, v.DecodeWithOptions(&actual, DecodeOptions{RequireSecretTags: true}))
+	assert.Equal(t, "hunter2", actual.Creds.SecretAccessKey)
+	assert.Equal(t, NewSecret("raw"), actual.Raw)
+}
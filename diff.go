@@ -0,0 +1,328 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package esc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/esc/internal/util"
+	"golang.org/x/exp/maps"
+)
+
+// A ValueChangeKind describes the kind of a change between two values.
+type ValueChangeKind string
+
+const (
+	// ValueAdded indicates that a property or array element was added.
+	ValueAdded ValueChangeKind = "added"
+	// ValueRemoved indicates that a property or array element was removed.
+	ValueRemoved ValueChangeKind = "removed"
+	// ValueChanged indicates that a value was changed.
+	ValueChanged ValueChangeKind = "changed"
+)
+
+// A ValueChange describes a single change between two values.
+type ValueChange struct {
+	// Path is the path to the changed value, e.g. `aws.creds[0]`. The path of the root value is empty.
+	Path string `json:"path"`
+
+	// Pointer is the RFC 6901 JSON Pointer to the changed value, e.g. `/aws/creds/0`.
+	Pointer string `json:"pointer"`
+
+	// Kind is the kind of the change.
+	Kind ValueChangeKind `json:"kind"`
+
+	// Base is the value in the base. Base is nil if the value was added.
+	Base *Value `json:"base,omitempty"`
+
+	// Compare is the value in the comparison. Compare is nil if the value was removed.
+	Compare *Value `json:"compare,omitempty"`
+
+	// SecretChanged is true if the value's secretness changed.
+	SecretChanged bool `json:"secretChanged,omitempty"`
+
+	// Unknown is true if either side of a changed value is unknown, in which case the values may in fact be equal.
+	Unknown bool `json:"unknown,omitempty"`
+
+	// segments holds the property names and array indices that make up the path.
+	segments []any
+}
+
+// ValueChanges is a list of changes between two values.
+type ValueChanges []ValueChange
+
+// DiffValues returns the changes between the base and compare values, in depth-first order. Objects and arrays are
+// compared element-wise. Values whose secretness differs are reported as changed even if they are otherwise equal.
+// Unknown values are assumed to be equal to other unknown values.
+//
+// Trailing array elements that were removed are reported in reverse order so that the changes may be applied in
+// sequence (e.g. as a JSON Patch).
+func DiffValues(base, compare Value) ValueChanges {
+	var d valueDiffer
+	d.diff(nil, base, compare)
+	return d.changes
+}
+
+// DiffEnvironments returns the changes between the properties of the base and compare environments. A nil
+// environment has no properties.
+func DiffEnvironments(base, compare *Environment) ValueChanges {
+	var baseProps, compareProps map[string]Value
+	if base != nil {
+		baseProps = base.Properties
+	}
+	if compare != nil {
+		compareProps = compare.Properties
+	}
+	return DiffValues(NewValue(baseProps), NewValue(compareProps))
+}
+
+type valueDiffer struct {
+	changes ValueChanges
+}
+
+func (d *valueDiffer) change(segments []any, kind ValueChangeKind, base, compare *Value) {
+	c := ValueChange{
+		Path:     diffPath(segments),
+		Pointer:  diffPointer(segments),
+		Kind:     kind,
+		Base:     base,
+		Compare:  compare,
+		segments: segments,
+	}
+	if base != nil && compare != nil {
+		c.SecretChanged = base.Secret != compare.Secret
+		c.Unknown = base.Unknown || compare.Unknown
+	}
+	d.changes = append(d.changes, c)
+}
+
+func (d *valueDiffer) diff(segments []any, base, compare Value) {
+	if base.Unknown || compare.Unknown {
+		if base.Unknown != compare.Unknown || base.Secret != compare.Secret {
+			d.change(segments, ValueChanged, &base, &compare)
+		}
+		return
+	}
+
+	switch b := base.Value.(type) {
+	case []Value:
+		if c, ok := compare.Value.([]Value); ok {
+			n := len(d.changes)
+			for i := 0; i < len(b) && i < len(c); i++ {
+				d.diff(appendSegment(segments, i), b[i], c[i])
+			}
+			for i := len(b); i < len(c); i++ {
+				d.change(appendSegment(segments, i), ValueAdded, nil, &c[i])
+			}
+			for i := len(b) - 1; i >= len(c); i-- {
+				d.change(appendSegment(segments, i), ValueRemoved, &b[i], nil)
+			}
+			if len(d.changes) == n && base.Secret != compare.Secret {
+				d.change(segments, ValueChanged, &base, &compare)
+			}
+			return
+		}
+	case map[string]Value:
+		if c, ok := compare.Value.(map[string]Value); ok {
+			n := len(d.changes)
+			keys := maps.Keys(b)
+			for k := range c {
+				if _, ok := b[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				bv, inBase := b[k]
+				cv, inCompare := c[k]
+				switch {
+				case !inBase:
+					d.change(appendSegment(segments, k), ValueAdded, nil, &cv)
+				case !inCompare:
+					d.change(appendSegment(segments, k), ValueRemoved, &bv, nil)
+				default:
+					d.diff(appendSegment(segments, k), bv, cv)
+				}
+			}
+			if len(d.changes) == n && base.Secret != compare.Secret {
+				d.change(segments, ValueChanged, &base, &compare)
+			}
+			return
+		}
+	}
+
+	if base.Secret != compare.Secret || !reflect.DeepEqual(base.Value, compare.Value) {
+		d.change(segments, ValueChanged, &base, &compare)
+	}
+}
+
+func appendSegment(segments []any, segment any) []any {
+	return append(segments[:len(segments):len(segments)], segment)
+}
+
+func diffPath(segments []any) string {
+	path := ""
+	for _, s := range segments {
+		switch s := s.(type) {
+		case int:
+			path = fmt.Sprintf("%v[%v]", path, s)
+		case string:
+			path = util.JoinKey(path, s)
+		}
+	}
+	return path
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func diffPointer(segments []any) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteByte('/')
+		switch s := s.(type) {
+		case int:
+			b.WriteString(strconv.Itoa(s))
+		case string:
+			b.WriteString(pointerEscaper.Replace(s))
+		}
+	}
+	return b.String()
+}
+
+// A PatchOperation is a single RFC 6902 JSON Patch operation.
+type PatchOperation struct {
+	// Op is the operation: one of "add", "remove", or "replace".
+	Op string `json:"op"`
+
+	// Path is the JSON Pointer to the target of the operation.
+	Path string `json:"path"`
+
+	// Value is the operation's value. Value is ignored for "remove" operations.
+	Value any `json:"value"`
+}
+
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	if op.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{op.Op, op.Path})
+	}
+	type patchOperation PatchOperation
+	return json.Marshal(patchOperation(op))
+}
+
+// Patch returns an RFC 6902 JSON Patch that transforms the plain-old-JSON representation of the base value into that
+// of the compare value. If redact is true, secrets are replaced with [secret].
+func (changes ValueChanges) Patch(redact bool) []PatchOperation {
+	ops := make([]PatchOperation, len(changes))
+	for i, c := range changes {
+		switch c.Kind {
+		case ValueAdded:
+			ops[i] = PatchOperation{Op: "add", Path: c.Pointer, Value: c.Compare.ToJSON(redact)}
+		case ValueRemoved:
+			ops[i] = PatchOperation{Op: "remove", Path: c.Pointer}
+		default:
+			ops[i] = PatchOperation{Op: "replace", Path: c.Pointer, Value: c.Compare.ToJSON(redact)}
+		}
+	}
+	return ops
+}
+
+// WriteTree writes a readable tree of the changes returned by DiffValues to w. Added values are prefixed with "+", removed values with "-",
+// and changed values and their parents with "~". If redact is true, secrets are replaced with [secret].
+//
+// For example:
+//
+//	~ aws
+//	    ~ region: "us-west-2" => "us-east-1"
+//	    + secretAccessKey: [secret]
+//	- debug: true
+func (changes ValueChanges) WriteTree(w io.Writer, redact bool) error {
+	var parents []any
+	for _, c := range changes {
+		segments := c.segments
+
+		// Write any parents that have not been written yet.
+		depth := 0
+		for depth < len(parents) && depth < len(segments)-1 && parents[depth] == segments[depth] {
+			depth++
+		}
+		parents = parents[:depth]
+		for ; depth < len(segments)-1; depth++ {
+			if _, err := fmt.Fprintf(w, "%v~ %v\n", strings.Repeat("    ", depth), treeLabel(segments[depth])); err != nil {
+				return err
+			}
+			parents = append(parents, segments[depth])
+		}
+
+		label := "<root>"
+		if len(segments) != 0 {
+			label = treeLabel(segments[len(segments)-1])
+		}
+		indent := strings.Repeat("    ", depth)
+
+		var err error
+		switch c.Kind {
+		case ValueAdded:
+			_, err = fmt.Fprintf(w, "%v+ %v: %v\n", indent, label, treeValue(c.Compare, redact))
+		case ValueRemoved:
+			_, err = fmt.Fprintf(w, "%v- %v: %v\n", indent, label, treeValue(c.Base, redact))
+		default:
+			note := ""
+			if c.SecretChanged {
+				if c.Compare.Secret {
+					note = " (now secret)"
+				} else {
+					note = " (now plaintext)"
+				}
+			}
+			_, err = fmt.Fprintf(w, "%v~ %v: %v => %v%v\n", indent, label, treeValue(c.Base, redact), treeValue(c.Compare, redact), note)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func treeLabel(segment any) string {
+	switch s := segment.(type) {
+	case int:
+		return fmt.Sprintf("[%v]", s)
+	default:
+		return util.JoinKey("", s.(string))
+	}
+}
+
+func treeValue(v *Value, redact bool) string {
+	switch {
+	case v.Secret && redact:
+		return "[secret]"
+	case v.Unknown:
+		return "[unknown]"
+	}
+	b, err := json.Marshal(v.ToJSON(redact))
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return string(b)
+}
@@ -0,0 +1,124 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package esc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffValues(t *testing.T) {
+	base := NewValue(map[string]Value{
+		"region":   NewValue("us-west-2"),
+		"debug":    NewValue(true),
+		"tags":     NewValue([]Value{NewValue("a"), NewValue("b"), NewValue("c")}),
+		"password": NewValue("hunter2"),
+		"token":    {Unknown: true},
+		"open":     {Unknown: true},
+		"a/b":      NewValue(json.Number("1")),
+		"nested":   NewValue(map[string]Value{"x": NewValue(json.Number("1"))}),
+	})
+	compare := NewValue(map[string]Value{
+		"region":   NewValue("us-east-1"),
+		"tags":     NewValue([]Value{NewValue("a")}),
+		"password": NewSecret("hunter2"),
+		"token":    NewValue("abc"),
+		"open":     {Unknown: true},
+		"a/b":      NewValue(json.Number("2")),
+		"nested":   NewValue(map[string]Value{"x": NewValue(json.Number("1")), "y": NewValue([]Value{})}),
+	})
+
+	changes := DiffValues(base, compare)
+
+	type summary struct {
+		Path, Pointer string
+		Kind          ValueChangeKind
+		SecretChanged bool
+		Unknown       bool
+	}
+	actual := make([]summary, len(changes))
+	for i, c := range changes {
+		actual[i] = summary{c.Path, c.Pointer, c.Kind, c.SecretChanged, c.Unknown}
+	}
+	expected := []summary{
+		{`["a/b"]`, "/a~1b", ValueChanged, false, false},
+		{"debug", "/debug", ValueRemoved, false, false},
+		{"nested.y", "/nested/y", ValueAdded, false, false},
+		{"password", "/password", ValueChanged, true, false},
+		{"region", "/region", ValueChanged, false, false},
+		{"tags[2]", "/tags/2", ValueRemoved, false, false},
+		{"tags[1]", "/tags/1", ValueRemoved, false, false},
+		{"token", "/token", ValueChanged, false, true},
+	}
+	assert.Equal(t, expected, actual)
+
+	patch, err := json.Marshal(changes.Patch(true))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "replace", "path": "/a~1b", "value": 2},
+		{"op": "remove", "path": "/debug"},
+		{"op": "add", "path": "/nested/y", "value": []},
+		{"op": "replace", "path": "/password", "value": "[secret]"},
+		{"op": "replace", "path": "/region", "value": "us-east-1"},
+		{"op": "remove", "path": "/tags/2"},
+		{"op": "remove", "path": "/tags/1"},
+		{"op": "replace", "path": "/token", "value": "abc"}
+	]`, string(patch))
+
+	var tree strings.Builder
+	require.NoError(t, changes.WriteTree(&tree, true))
+	assert.Equal(t, `~ ["a/b"]: 1 => 2
+- debug: true
+~ nested
+    + y: []
+~ password: "hunter2" => [secret] (now secret)
+~ region: "us-west-2" => "us-east-1"
+~ tags
+    - [2]: "c"
+    - [1]: "b"
+~ token: [unknown] => "abc"
+`, tree.String())
+
+	tree.Reset()
+	require.NoError(t, DiffValues(NewSecret("a"), NewSecret("b")).WriteTree(&tree, false))
+	assert.Equal(t, "~ <root>: \"a\" => \"b\"\n", tree.String())
+}
+
+func TestDiffValuesEqual(t *testing.T) {
+	v := NewValue(map[string]Value{
+		"array":  NewValue([]Value{NewValue(true), {}}),
+		"secret": NewSecret("hunter2"),
+		"open":   {Unknown: true},
+	})
+	assert.Empty(t, DiffValues(v, v))
+}
+
+func TestDiffValuesSecretContainer(t *testing.T) {
+	changes := DiffValues(NewValue([]Value{}), NewSecret([]Value{}))
+	require.Len(t, changes, 1)
+	assert.Equal(t, "", changes[0].Path)
+	assert.True(t, changes[0].SecretChanged)
+}
+
+func TestDiffEnvironments(t *testing.T) {
+	changes := DiffEnvironments(nil, &Environment{Properties: map[string]Value{"foo": NewValue("bar")}})
+	require.Len(t, changes, 1)
+	assert.Equal(t, ValueAdded, changes[0].Kind)
+	assert.Equal(t, "foo", changes[0].Path)
+}
@@ -175,6 +175,34 @@ type Environment struct {
 
 	// ExecutionContext contains the values + schema for the execution context passed to the root environment.
 	ExecutionContext *EvaluatedExecutionContext `json:"executionContext,omitempty"`
+
+	// Imports contains the imports declared by each environment in the environment's import closure.
+	Imports []EnvironmentImport `json:"imports,omitempty"`
+}
+
+// An EnvironmentImport describes an import declared by an environment definition.
+type EnvironmentImport struct {
+	// Importer is the name of the environment that declared the import.
+	Importer string `json:"importer"`
+
+	// Environment is the name of the imported environment. For pinned imports, this includes the version.
+	Environment string `json:"environment"`
+
+	// Version is the revision number or revision tag the import is pinned to, if any.
+	Version string `json:"version,omitempty"`
+
+	// Range is the range of the import declaration.
+	Range Range `json:"range"`
+
+	// Optional is true if the import is skipped when the imported environment cannot be loaded.
+	Optional bool `json:"optional,omitempty"`
+
+	// Conditional is true if the import is only evaluated when its condition holds.
+	Conditional bool `json:"conditional,omitempty"`
+
+	// Skipped is true if the environment was not imported, either because its condition did not hold or because
+	// the import is optional and the environment could not be loaded.
+	Skipped bool `json:"skipped,omitempty"`
 }
 
 // GetEnvironmentVariables returns any environment variables defined by the environment.
@@ -0,0 +1,125 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package esctest provides in-memory implementations of the loaders used by the environment evaluator, mock and
+// recorded providers, and a golden-file test runner. It is intended for use by tests of environment definitions and
+// of providers.
+package esctest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/esc/eval"
+)
+
+// PlaintextCrypter is an eval.Encrypter and eval.Decrypter that leaves its inputs unchanged. It is the default
+// decrypter for in-memory environments.
+type PlaintextCrypter struct{}
+
+// Encrypt returns the plaintext unchanged.
+func (PlaintextCrypter) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	return plaintext, nil
+}
+
+// Decrypt returns the ciphertext unchanged.
+func (PlaintextCrypter) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	return ciphertext, nil
+}
+
+// Environments is an in-memory eval.VersionedEnvironmentLoader. Definitions are keyed by environment name. Specific
+// versions of an environment are keyed by `name@version`.
+type Environments struct {
+	// Decrypter is used to decrypt secrets in loaded definitions. If Decrypter is nil, PlaintextCrypter is used.
+	Decrypter eval.Decrypter
+
+	defs map[string][]byte
+}
+
+// NewEnvironments creates a new set of in-memory environments from the given definitions.
+func NewEnvironments(defs map[string]string) *Environments {
+	envs := &Environments{defs: make(map[string][]byte, len(defs))}
+	for name, def := range defs {
+		envs.Add(name, def)
+	}
+	return envs
+}
+
+// LoadEnvironments creates a new set of in-memory environments from the YAML files in the given directory. Each
+// environment is named after its file, less the `.yaml` extension.
+func LoadEnvironments(dir string) (*Environments, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	envs := NewEnvironments(nil)
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".yaml")
+		if !ok || e.IsDir() {
+			continue
+		}
+		def, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		envs.defs[name] = def
+	}
+	return envs, nil
+}
+
+// Add adds or replaces the definition of the named environment and returns the receiver.
+func (e *Environments) Add(name, def string) *Environments {
+	e.defs[name] = []byte(def)
+	return e
+}
+
+// Names returns the sorted names of the environments.
+func (e *Environments) Names() []string {
+	names := make([]string, 0, len(e.defs))
+	for name := range e.defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Definition returns the definition of the named environment, if any.
+func (e *Environments) Definition(name string) ([]byte, bool) {
+	def, ok := e.defs[name]
+	return def, ok
+}
+
+// LoadEnvironment loads the definition for the environment with the given name.
+func (e *Environments) LoadEnvironment(ctx context.Context, name string) ([]byte, eval.Decrypter, error) {
+	def, ok := e.defs[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("environment %q: %w", name, eval.ErrEnvironmentNotFound)
+	}
+
+	decrypter := e.Decrypter
+	if decrypter == nil {
+		decrypter = PlaintextCrypter{}
+	}
+	return def, decrypter, nil
+}
+
+// LoadEnvironmentVersion loads the definition for the given version of the environment with the given name.
+func (e *Environments) LoadEnvironmentVersion(ctx context.Context, name, version string) ([]byte, eval.Decrypter, error) {
+	return e.LoadEnvironment(ctx, name+"@"+version)
+}
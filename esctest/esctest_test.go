@@ -0,0 +1,149 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package esctest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/esc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockProvider() *MockProvider {
+	return &MockProvider{
+		OpenFunc: func(_ context.Context, inputs map[string]esc.Value, _ esc.EnvExecContext) (esc.Value, error) {
+			return esc.NewValue(map[string]esc.Value{
+				"host": esc.NewValue("db." + inputs["region"].Value.(string) + ".example.com"),
+			}), nil
+		},
+	}
+}
+
+func TestGolden(t *testing.T) {
+	RunGolden(t, filepath.Join("testdata", "golden"), GoldenOptions{
+		Providers: NewProviders().Provider("mock", mockProvider()),
+	})
+}
+
+func TestEnvironments(t *testing.T) {
+	envs := NewEnvironments(map[string]string{"a": "values: {a: 1}"}).Add("a@v1", "values: {a: 0}")
+	assert.Equal(t, []string{"a", "a@v1"}, envs.Names())
+
+	def, _, err := envs.LoadEnvironment(context.Background(), "a")
+	require.NoError(t, err)
+	assert.Equal(t, "values: {a: 1}", string(def))
+
+	def, _, err = envs.LoadEnvironmentVersion(context.Background(), "a", "v1")
+	require.NoError(t, err)
+	assert.Equal(t, "values: {a: 0}", string(def))
+
+	_, _, err = envs.LoadEnvironment(context.Background(), "b")
+	assert.Error(t, err)
+}
+
+func TestMockProvider(t *testing.T) {
+	p := &MockProvider{Value: esc.NewValue("hello")}
+	envs := NewEnvironments(map[string]string{
+		"env": "values:\n  a:\n    fn::open::mock: {x: 1}\n  b:\n    fn::open::mock: {x: 2}\n",
+	})
+
+	result, err := Evaluate(context.Background(), "env", envs, GoldenOptions{Providers: NewProviders().Provider("mock", p)})
+	require.NoError(t, err)
+	require.Empty(t, result.Diags)
+	assert.Equal(t, "hello", result.Environment.Properties["a"].Value)
+	assert.Len(t, p.Calls(), 2)
+}
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	mock := mockProvider()
+	recorder := NewRecordingProvider(mock)
+
+	v, err := recorder.Open(ctx, map[string]esc.Value{"region": esc.NewValue("us-east-1")}, nil)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "recordings.json")
+	err = recorder.WriteFile(path)
+	require.NoError(t, err)
+
+	replay, err := LoadReplayProvider(nil, nil, path)
+	require.NoError(t, err)
+
+	replayed, err := replay.Open(ctx, map[string]esc.Value{"region": esc.NewValue("us-east-1")}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, v.ToJSON(false), replayed.ToJSON(false))
+
+	_, err = replay.Open(ctx, map[string]esc.Value{"region": esc.NewValue("eu-west-1")}, nil)
+	assert.True(t, errors.Is(err, ErrNoRecording))
+	assert.Len(t, mock.Calls(), 1)
+}
+
+func TestRecordSecrets(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockProvider{
+		OpenFunc: func(_ context.Context, inputs map[string]esc.Value, _ esc.EnvExecContext) (esc.Value, error) {
+			return esc.NewValue(map[string]esc.Value{
+				"user":     esc.NewValue("admin"),
+				"password": esc.NewSecret("hunter2"),
+			}), nil
+		},
+	}
+	inputs := map[string]esc.Value{"token": esc.NewSecret("s3cr3t")}
+
+	t.Run("redacted", func(t *testing.T) {
+		recorder := NewRecordingProvider(mock)
+		_, err := recorder.Open(ctx, inputs, nil)
+		require.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "recordings.json")
+		require.NoError(t, recorder.WriteFile(path))
+		bytes, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(bytes), "s3cr3t")
+		assert.NotContains(t, string(bytes), "hunter2")
+
+		replay, err := LoadReplayProvider(nil, nil, path)
+		require.NoError(t, err)
+
+		replayed, err := replay.Open(ctx, inputs, nil)
+		require.NoError(t, err)
+		props := replayed.Value.(map[string]esc.Value)
+		assert.Equal(t, "admin", props["user"].Value)
+		assert.True(t, props["password"].Secret)
+		assert.Equal(t, RedactSecrets(esc.NewSecret("hunter2")).Value, props["password"].Value)
+
+		_, err = replay.Open(ctx, map[string]esc.Value{"token": esc.NewSecret("other")}, nil)
+		assert.True(t, errors.Is(err, ErrNoRecording))
+	})
+
+	t.Run("plaintext", func(t *testing.T) {
+		recorder := NewRecordingProvider(mock)
+		recorder.Plaintext = true
+		_, err := recorder.Open(ctx, inputs, nil)
+		require.NoError(t, err)
+
+		replay, err := NewReplayProvider(nil, nil, recorder.Recordings())
+		require.NoError(t, err)
+
+		replayed, err := replay.Open(ctx, inputs, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", replayed.Value.(map[string]esc.Value)["password"].Value)
+	})
+}
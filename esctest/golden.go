@@ -0,0 +1,158 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package esctest
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/eval"
+	"github.com/pulumi/esc/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// GoldenOptions configures RunGolden.
+type GoldenOptions struct {
+	// Providers loads the providers and rotators used by the environments. If Providers is nil, no providers are
+	// available.
+	Providers eval.ProviderLoader
+
+	// ExecContext is the execution context for evaluation. If ExecContext is nil, an empty context is used.
+	ExecContext *esc.ExecContext
+
+	// Decrypter is used to decrypt secrets in the environments. If Decrypter is nil, PlaintextCrypter is used.
+	Decrypter eval.Decrypter
+
+	// Check, if true, checks the environments rather than evaluating them. Providers are not opened.
+	Check bool
+
+	// Update, if true, rewrites the expected output of each environment rather than comparing against it. Update is
+	// implied if the PULUMI_ACCEPT environment variable is set.
+	Update bool
+}
+
+// GoldenResult is the expected output of a golden test.
+type GoldenResult struct {
+	// Diags holds the diagnostics issued while loading and evaluating the environment, if any.
+	Diags syntax.Diagnostics `json:"diags,omitempty"`
+
+	// Environment is the evaluated environment, if any.
+	Environment *esc.Environment `json:"environment,omitempty"`
+}
+
+// RunGolden evaluates each YAML environment in the given directory and compares the result with the expected output
+// stored alongside it. The expected output for `name.yaml` is stored in `name.expected.json` as a GoldenResult.
+// Environments in the directory may import one another.
+//
+// Each environment is evaluated in its own subtest.
+func RunGolden(t *testing.T, dir string, options GoldenOptions) {
+	envs, err := LoadEnvironments(dir)
+	require.NoError(t, err)
+	envs.Decrypter = options.Decrypter
+
+	update := options.Update || os.Getenv("PULUMI_ACCEPT") != ""
+
+	for _, name := range envs.Names() {
+		t.Run(name, func(t *testing.T) {
+			actual, err := Evaluate(context.Background(), name, envs, options)
+			require.NoError(t, err)
+
+			actualBytes, err := json.MarshalIndent(actual, "", "  ")
+			require.NoError(t, err)
+			actualBytes = append(actualBytes, '\n')
+
+			expectedPath := filepath.Join(dir, name+".expected.json")
+			if update {
+				err = os.WriteFile(expectedPath, actualBytes, 0o600)
+				require.NoError(t, err)
+				return
+			}
+
+			expectedBytes, err := os.ReadFile(expectedPath)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expectedBytes), string(actualBytes))
+		})
+	}
+}
+
+// Evaluate loads and evaluates the named environment. Diagnostics are sorted by position.
+func Evaluate(ctx context.Context, name string, envs *Environments, options GoldenOptions) (*GoldenResult, error) {
+	def, decrypter, err := envs.LoadEnvironment(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := options.Providers
+	if providers == nil {
+		providers = NewProviders()
+	}
+
+	execContext := options.ExecContext
+	if execContext == nil {
+		if execContext, err = esc.NewExecContext(nil); err != nil {
+			return nil, err
+		}
+	}
+
+	decl, diags, err := eval.LoadYAMLBytes(name, def)
+	if err != nil {
+		return nil, err
+	}
+	if diags.HasErrors() {
+		sortDiagnostics(diags)
+		return &GoldenResult{Diags: diags}, nil
+	}
+
+	var env *esc.Environment
+	var evalDiags syntax.Diagnostics
+	if options.Check {
+		env, evalDiags = eval.CheckEnvironment(ctx, name, decl, decrypter, providers, envs, execContext, true)
+	} else {
+		env, evalDiags = eval.EvalEnvironment(ctx, name, decl, decrypter, providers, envs, execContext)
+	}
+	diags.Extend(evalDiags...)
+	sortDiagnostics(diags)
+
+	return &GoldenResult{Diags: diags, Environment: env}, nil
+}
+
+// sortDiagnostics sorts diagnostics by position so that golden output is stable.
+func sortDiagnostics(diags syntax.Diagnostics) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		di, dj := diags[i], diags[j]
+		if di.Subject == nil {
+			if dj.Subject == nil {
+				return di.Summary < dj.Summary
+			}
+			return true
+		}
+		if dj.Subject == nil {
+			return false
+		}
+		if di.Subject.Filename != dj.Subject.Filename {
+			return di.Subject.Filename < dj.Subject.Filename
+		}
+		if di.Subject.Start.Line != dj.Subject.Start.Line {
+			return di.Subject.Start.Line < dj.Subject.Start.Line
+		}
+		return di.Subject.Start.Column < dj.Subject.Start.Column
+	})
+}
@@ -0,0 +1,337 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package esctest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/schema"
+)
+
+// Providers is an in-memory eval.ProviderLoader.
+type Providers struct {
+	providers map[string]esc.Provider
+	rotators  map[string]esc.Rotator
+}
+
+// NewProviders creates a new, empty set of providers.
+func NewProviders() *Providers {
+	return &Providers{
+		providers: map[string]esc.Provider{},
+		rotators:  map[string]esc.Rotator{},
+	}
+}
+
+// Provider adds or replaces the named provider and returns the receiver.
+func (p *Providers) Provider(name string, provider esc.Provider) *Providers {
+	p.providers[name] = provider
+	return p
+}
+
+// Rotator adds or replaces the named rotator and returns the receiver.
+func (p *Providers) Rotator(name string, rotator esc.Rotator) *Providers {
+	p.rotators[name] = rotator
+	return p
+}
+
+// LoadProvider loads the provider with the given name.
+func (p *Providers) LoadProvider(ctx context.Context, name string) (esc.Provider, error) {
+	if provider, ok := p.providers[name]; ok {
+		return provider, nil
+	}
+	return nil, fmt.Errorf("unknown provider %q", name)
+}
+
+// LoadRotator loads the rotator with the given name.
+func (p *Providers) LoadRotator(ctx context.Context, name string) (esc.Rotator, error) {
+	if rotator, ok := p.rotators[name]; ok {
+		return rotator, nil
+	}
+	return nil, fmt.Errorf("unknown rotator %q", name)
+}
+
+// MockProvider is a provider with a canned response. Each call to Open is recorded.
+type MockProvider struct {
+	// Inputs and Outputs are the provider's schemata. Nil schemata accept any value.
+	Inputs, Outputs *schema.Schema
+
+	// OpenFunc, if set, computes the provider's response. Otherwise, Open returns Value and Err.
+	OpenFunc func(ctx context.Context, inputs map[string]esc.Value, executionContext esc.EnvExecContext) (esc.Value, error)
+
+	// Value and Err are the provider's response if OpenFunc is nil.
+	Value esc.Value
+	Err   error
+
+	m     sync.Mutex
+	calls []map[string]esc.Value
+}
+
+// Schema returns the provider's input and output schemata.
+func (p *MockProvider) Schema() (inputs, outputs *schema.Schema) {
+	inputs, outputs = p.Inputs, p.Outputs
+	if inputs == nil {
+		inputs = schema.Always().Schema()
+	}
+	if outputs == nil {
+		outputs = schema.Always().Schema()
+	}
+	return inputs, outputs
+}
+
+// Open records the call and returns the provider's response.
+func (p *MockProvider) Open(
+	ctx context.Context,
+	inputs map[string]esc.Value,
+	executionContext esc.EnvExecContext,
+) (esc.Value, error) {
+	p.m.Lock()
+	p.calls = append(p.calls, inputs)
+	p.m.Unlock()
+
+	if p.OpenFunc != nil {
+		return p.OpenFunc(ctx, inputs, executionContext)
+	}
+	return p.Value, p.Err
+}
+
+// Calls returns the inputs of each call to Open.
+func (p *MockProvider) Calls() []map[string]esc.Value {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return append([]map[string]esc.Value(nil), p.calls...)
+}
+
+// A Recording records a single call to a provider's Open method.
+type Recording struct {
+	// Inputs holds the plain JSON representation of the inputs to the call. Unless the recording was made with
+	// RecordingProvider.Plaintext set, secret inputs are redacted (see RedactSecrets).
+	Inputs any `json:"inputs"`
+
+	// Value is the value returned by the call, if any.
+	Value *esc.Value `json:"value,omitempty"`
+
+	// Error is the error message returned by the call, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// ErrNoRecording is returned by a replaying provider that has no recording for its inputs.
+var ErrNoRecording = errors.New("no recording for inputs")
+
+// RecordingProvider is a provider that records the responses of another provider. Once recorded, the responses can be
+// replayed by a provider created with NewReplayProvider, which allows tests to run without access to the services
+// used by the recorded provider.
+//
+// Recordings are typically committed alongside tests, so secret inputs and outputs are redacted by default (see
+// RedactSecrets). Replayed responses contain the redacted placeholders in place of secret values.
+type RecordingProvider struct {
+	// Plaintext, if true, records secret values in plaintext. Recordings made with Plaintext set may contain
+	// credentials and should not be committed.
+	Plaintext bool
+
+	provider esc.Provider
+
+	m          sync.Mutex
+	recordings []Recording
+}
+
+// NewRecordingProvider creates a new provider that records the responses of the given provider.
+func NewRecordingProvider(provider esc.Provider) *RecordingProvider {
+	return &RecordingProvider{provider: provider}
+}
+
+// Schema returns the recorded provider's input and output schemata.
+func (p *RecordingProvider) Schema() (inputs, outputs *schema.Schema) {
+	return p.provider.Schema()
+}
+
+// Open calls the recorded provider and records its response.
+func (p *RecordingProvider) Open(
+	ctx context.Context,
+	inputs map[string]esc.Value,
+	executionContext esc.EnvExecContext,
+) (esc.Value, error) {
+	v, err := p.provider.Open(ctx, inputs, executionContext)
+
+	recordedInputs, recordedValue := esc.NewValue(inputs), v
+	if !p.Plaintext {
+		recordedInputs, recordedValue = RedactSecrets(recordedInputs), RedactSecrets(recordedValue)
+	}
+
+	recording := Recording{Inputs: recordedInputs.ToJSON(false)}
+	if err != nil {
+		recording.Error = err.Error()
+	} else {
+		recording.Value = &recordedValue
+	}
+
+	p.m.Lock()
+	p.recordings = append(p.recordings, recording)
+	p.m.Unlock()
+
+	return v, err
+}
+
+// Recordings returns the provider's recordings.
+func (p *RecordingProvider) Recordings() []Recording {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return append([]Recording(nil), p.recordings...)
+}
+
+// WriteFile writes the provider's recordings to the named file as JSON.
+func (p *RecordingProvider) WriteFile(path string) error {
+	bytes, err := json.MarshalIndent(p.Recordings(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bytes, '\n'), 0o600)
+}
+
+// RedactSecrets returns a copy of v in which each secret value is replaced by a placeholder of the form
+// `[secret sha256:<hash>]`, where hash is the SHA-256 hash of the JSON representation of the secret value's plaintext.
+// Placeholders are deterministic, so inputs that contain secrets can still be matched on replay.
+func RedactSecrets(v esc.Value) esc.Value {
+	if v.Secret {
+		// The JSON representation of a value always marshals.
+		bytes, _ := json.Marshal(v.ToJSON(false))
+		sum := sha256.Sum256(bytes)
+		v.Value = "[secret sha256:" + hex.EncodeToString(sum[:]) + "]"
+		return v
+	}
+
+	switch repr := v.Value.(type) {
+	case []esc.Value:
+		redacted := make([]esc.Value, len(repr))
+		for i, e := range repr {
+			redacted[i] = RedactSecrets(e)
+		}
+		v.Value = redacted
+	case map[string]esc.Value:
+		redacted := make(map[string]esc.Value, len(repr))
+		for k, e := range repr {
+			redacted[k] = RedactSecrets(e)
+		}
+		v.Value = redacted
+	}
+	return v
+}
+
+// ReplayProvider is a provider that replays recorded responses. Open returns the response of the first recording
+// whose inputs match its inputs, or ErrNoRecording if there is no such recording. Inputs match a recording if they are
+// equal to the recorded inputs after redacting secrets, or, for recordings made in plaintext, if they are equal to the
+// recorded inputs.
+type ReplayProvider struct {
+	inputs, outputs *schema.Schema
+	recordings      map[string]Recording
+}
+
+// NewReplayProvider creates a new provider that replays the given recordings. If the schemata are nil, the provider
+// accepts and returns any value.
+func NewReplayProvider(inputs, outputs *schema.Schema, recordings []Recording) (*ReplayProvider, error) {
+	byInputs := make(map[string]Recording, len(recordings))
+	for _, r := range recordings {
+		key, err := recordingKey(r.Inputs)
+		if err != nil {
+			return nil, err
+		}
+		if _, has := byInputs[key]; !has {
+			byInputs[key] = r
+		}
+	}
+	return &ReplayProvider{inputs: inputs, outputs: outputs, recordings: byInputs}, nil
+}
+
+// LoadReplayProvider creates a new provider that replays the recordings in the named file.
+func LoadReplayProvider(inputs, outputs *schema.Schema, path string) (*ReplayProvider, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var recordings []Recording
+	if err := json.Unmarshal(bytes, &recordings); err != nil {
+		return nil, fmt.Errorf("decoding recordings: %w", err)
+	}
+	return NewReplayProvider(inputs, outputs, recordings)
+}
+
+// Schema returns the provider's input and output schemata.
+func (p *ReplayProvider) Schema() (inputs, outputs *schema.Schema) {
+	inputs, outputs = p.inputs, p.outputs
+	if inputs == nil {
+		inputs = schema.Always().Schema()
+	}
+	if outputs == nil {
+		outputs = schema.Always().Schema()
+	}
+	return inputs, outputs
+}
+
+// Open replays the recorded response for the given inputs.
+func (p *ReplayProvider) Open(
+	ctx context.Context,
+	inputs map[string]esc.Value,
+	executionContext esc.EnvExecContext,
+) (esc.Value, error) {
+	key, err := recordingKey(RedactSecrets(esc.NewValue(inputs)).ToJSON(false))
+	if err != nil {
+		return esc.Value{}, err
+	}
+	r, ok := p.recordings[key]
+	if !ok {
+		plaintextKey, err := recordingKey(esc.NewValue(inputs).ToJSON(false))
+		if err != nil {
+			return esc.Value{}, err
+		}
+		r, ok = p.recordings[plaintextKey]
+	}
+	switch {
+	case !ok:
+		return esc.Value{}, fmt.Errorf("%w %v", ErrNoRecording, key)
+	case r.Error != "":
+		return esc.Value{}, errors.New(r.Error)
+	case r.Value == nil:
+		return esc.Value{}, nil
+	default:
+		return *r.Value, nil
+	}
+}
+
+// recordingKey returns the canonical JSON encoding of a recording's inputs. Recordings are matched on their keys.
+func recordingKey(inputs any) (string, error) {
+	// Round-trip the inputs so that recordings decoded from JSON and recordings created in memory produce the same key.
+	bytes, err := json.Marshal(inputs)
+	if err != nil {
+		return "", err
+	}
+	var canonical any
+	if err := json.Unmarshal(bytes, &canonical); err != nil {
+		return "", err
+	}
+	bytes, err = json.Marshal(canonical)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
@@ -44,14 +44,15 @@ type Decrypter interface {
 	Decrypt(ctx context.Context, value []byte) ([]byte, error)
 }
 
-// rewriteYAML is a helper for rewriting a single YAML document.
-func rewriteYAML(
+// rewriteDocument is a helper for rewriting a single environment definition. JSON definitions are written back as
+// JSON; all others are written as YAML.
+func rewriteDocument(
 	ctx context.Context,
 	filename string,
 	source []byte,
 	visitor func(n syntax.Node) (syntax.Node, syntax.Diagnostics, error),
 ) ([]byte, error) {
-	syn, diags := encoding.DecodeYAMLBytes(filename, source, TagDecoder)
+	syn, diags := decodeDefinition(filename, source)
 	if len(diags) != 0 {
 		return nil, diags
 	}
@@ -65,6 +66,14 @@ func rewriteYAML(
 	}
 
 	var b bytes.Buffer
+	if encoding.IsJSON(source) {
+		diags = encoding.EncodeJSON(&b, doc)
+		if len(diags) != 0 {
+			return nil, diags
+		}
+		return b.Bytes(), nil
+	}
+
 	enc := yaml.NewEncoder(&b)
 	enc.SetIndent(2)
 	diags = encoding.EncodeYAML(enc, doc)
@@ -114,7 +123,7 @@ func parseSecret(node syntax.Node) (obj *syntax.ObjectNode, plaintext, ciphertex
 // EncryptSecrets encrypts any secrets in the given YAML document and returns the rewritten source. Encryption replaces
 // all plaintext arguments to `fn::secret` with encrypted ciphertext.
 func EncryptSecrets(ctx context.Context, filename string, source []byte, encrypter Encrypter) ([]byte, error) {
-	return rewriteYAML(ctx, filename, source, func(n syntax.Node) (syntax.Node, syntax.Diagnostics, error) {
+	return rewriteDocument(ctx, filename, source, func(n syntax.Node) (syntax.Node, syntax.Diagnostics, error) {
 		obj, plaintext, _, ok := parseSecret(n)
 		if !ok || plaintext == nil {
 			return n, nil, nil
@@ -148,7 +157,7 @@ func EncryptSecrets(ctx context.Context, filename string, source []byte, encrypt
 // DecryptSecrets decrypts any secrets in the given YAML document and returns the rewritten source. Decryption replaces
 // all ciphertext arguments to `fn::secret` with decrypted plaintext.
 func DecryptSecrets(ctx context.Context, filename string, source []byte, decrypter Decrypter) ([]byte, error) {
-	return rewriteYAML(ctx, filename, source, func(n syntax.Node) (syntax.Node, syntax.Diagnostics, error) {
+	return rewriteDocument(ctx, filename, source, func(n syntax.Node) (syntax.Node, syntax.Diagnostics, error) {
 		obj, _, ciphertextNode, ok := parseSecret(n)
 		if !ok || ciphertextNode == nil {
 			return n, nil, nil
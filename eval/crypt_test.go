@@ -51,14 +51,15 @@ func TestCrypt(t *testing.T) {
 	entries, err := os.ReadDir(path)
 	require.NoError(t, err)
 	for _, e := range entries {
-		baseName, ok := strings.CutSuffix(e.Name(), ".plaintext.yaml")
+		ext := filepath.Ext(e.Name())
+		baseName, ok := strings.CutSuffix(e.Name(), ".plaintext"+ext)
 		if !ok {
 			continue
 		}
 
 		t.Run(e.Name(), func(t *testing.T) {
 			plaintextPath := filepath.Join(path, e.Name())
-			ciphertextPath := filepath.Join(path, baseName+".ciphertext.yaml")
+			ciphertextPath := filepath.Join(path, baseName+".ciphertext"+ext)
 
 			plaintextBytes, err := os.ReadFile(plaintextPath)
 			require.NoError(t, err)
@@ -19,10 +19,12 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
 	"reflect"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -45,12 +47,37 @@ type ProviderLoader interface {
 	LoadRotator(ctx context.Context, name string) (esc.Rotator, error)
 }
 
+// ErrEnvironmentNotFound is returned (possibly wrapped) by an EnvironmentLoader if the requested environment does not
+// exist. Optional imports of environments that do not exist are skipped.
+var ErrEnvironmentNotFound = errors.New("environment not found")
+
 // An EnvironmentLoader provides the environment evaluator the capability to load imported environment definitions.
 type EnvironmentLoader interface {
-	// LoadEnvironment loads the definition for the environment with the given name.
+	// LoadEnvironment loads the definition for the environment with the given name. If the environment does not
+	// exist, LoadEnvironment returns an error that wraps ErrEnvironmentNotFound.
 	LoadEnvironment(ctx context.Context, name string) ([]byte, Decrypter, error)
 }
 
+// A VersionedEnvironmentLoader is an EnvironmentLoader that is also capable of loading specific versions of imported
+// environment definitions. Versioned imports are written as `name@version`.
+type VersionedEnvironmentLoader interface {
+	EnvironmentLoader
+
+	// LoadEnvironmentVersion loads the definition for the given version of the environment with the given name. The
+	// version is either a revision number or a revision tag. If the environment or version does not exist,
+	// LoadEnvironmentVersion returns an error that wraps ErrEnvironmentNotFound.
+	LoadEnvironmentVersion(ctx context.Context, name, version string) ([]byte, Decrypter, error)
+}
+
+// splitEnvironmentVersion splits an import of the form `name@version` into its name and version. The version is
+// empty if the import is not pinned.
+func splitEnvironmentVersion(ref string) (name, version string) {
+	if i := strings.LastIndexByte(ref, '@'); i != -1 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
 // LoadYAML decodes a YAML template from an io.Reader.
 func LoadYAML(filename string, r io.Reader) (*ast.EnvironmentDecl, syntax.Diagnostics, error) {
 	bytes, err := io.ReadAll(r)
@@ -60,11 +87,12 @@ func LoadYAML(filename string, r io.Reader) (*ast.EnvironmentDecl, syntax.Diagno
 	return LoadYAMLBytes(filename, bytes)
 }
 
-// LoadYAMLBytes decodes a YAML template from a byte array.
+// LoadYAMLBytes decodes a YAML template from a byte array. Templates that are JSON objects are decoded as JSON so that
+// diagnostics carry accurate JSON source ranges.
 func LoadYAMLBytes(filename string, source []byte) (*ast.EnvironmentDecl, syntax.Diagnostics, error) {
 	var diags syntax.Diagnostics
 
-	syn, sdiags := encoding.DecodeYAMLBytes(filename, source, TagDecoder)
+	syn, sdiags := decodeDefinition(filename, source)
 	diags.Extend(sdiags...)
 	if sdiags.HasErrors() {
 		return nil, diags, nil
@@ -75,6 +103,46 @@ func LoadYAMLBytes(filename string, source []byte) (*ast.EnvironmentDecl, syntax
 	return t, diags, nil
 }
 
+// decodeDefinition decodes an environment definition. Definitions that are JSON objects are decoded using the JSON
+// codec; all others are decoded as YAML. Definitions that begin with '{' are decoded as JSON first and fall back to
+// YAML, as they may be YAML flow mappings. If such a definition is neither valid JSON nor valid YAML, the JSON syntax
+// errors are reported.
+func decodeDefinition(filename string, source []byte) (syntax.Node, syntax.Diagnostics) {
+	if trimmed := bytes.TrimLeft(source, " \t\r\n"); len(trimmed) == 0 || trimmed[0] != '{' {
+		return encoding.DecodeYAMLBytes(filename, source, TagDecoder)
+	}
+
+	syn, jsonDiags := encoding.DecodeJSONBytes(filename, source)
+	if !jsonDiags.HasErrors() {
+		return syn, jsonDiags
+	}
+	syn, yamlDiags := encoding.DecodeYAMLBytes(filename, source, TagDecoder)
+	if yamlDiags.HasErrors() {
+		return nil, jsonDiags
+	}
+	return syn, yamlDiags
+}
+
+// Strict implements strict evaluation. It returns a copy of diags in which each warning has been promoted to an
+// error. Callers that should fail on any warning pass the diagnostics returned by LoadYAMLBytes and the various
+// evaluation functions through Strict.
+func Strict(diags syntax.Diagnostics) syntax.Diagnostics {
+	if len(diags) == 0 {
+		return diags
+	}
+
+	strict := make(syntax.Diagnostics, len(diags))
+	for i, diag := range diags {
+		if diag.Severity == hcl.DiagWarning {
+			promoted := *diag
+			promoted.Severity = hcl.DiagError
+			diag = &promoted
+		}
+		strict[i] = diag
+	}
+	return strict
+}
+
 // EvalEnvironment evaluates the given environment.
 func EvalEnvironment(
 	ctx context.Context,
@@ -107,6 +175,11 @@ func CheckEnvironment(
 
 // RotateEnvironment evaluates the given environment and invokes provider rotate methods.
 // The updated rotation state is returned with a set of patches to be written back to the environment.
+//
+// Rotations performed by transactional rotators are prepared rather than completed. If any rotation fails or
+// evaluation reports errors, the prepared rotations are aborted before RotateEnvironment returns. Otherwise, the caller
+// must commit the result once its patches have been written back to the environment, or abort the result if the
+// patches cannot be written.
 func RotateEnvironment(
 	ctx context.Context,
 	name string,
@@ -117,11 +190,44 @@ func RotateEnvironment(
 	execContext *esc.ExecContext,
 	paths []resource.PropertyPath,
 ) (*esc.Environment, RotationResult, syntax.Diagnostics) {
-	rotateDocPaths := make(map[string]bool, len(paths))
+	rotated, result, diags := evalEnvironment(ctx, false, true, name, env, decrypter, providers, environments, execContext, true, rotateDocPaths(paths))
+	if result.Failed() || diags.HasErrors() {
+		diags.Extend(result.Abort(ctx)...)
+	}
+	return rotated, result, diags
+}
+
+// PlanRotateEnvironment checks the given environment as CheckEnvironment would, but also returns a plan that describes
+// the rotations that RotateEnvironment would perform. Neither providers nor rotators are invoked, so rotator inputs
+// that depend on provider outputs are unknown and cannot be validated until the rotation is performed. Secrets are
+// never decrypted: encrypted secrets evaluate to unknown values, which do not block rotation.
+func PlanRotateEnvironment(
+	ctx context.Context,
+	name string,
+	env *ast.EnvironmentDecl,
+	decrypter Decrypter,
+	providers ProviderLoader,
+	environments EnvironmentLoader,
+	execContext *esc.ExecContext,
+	paths []resource.PropertyPath,
+) (RotationPlan, syntax.Diagnostics) {
+	if env == nil || (len(env.Values.GetEntries()) == 0 && len(env.Imports.GetElements()) == 0) {
+		return nil, nil
+	}
+
+	ec := newEvalContext(ctx, true, true, name, env, true, decrypter, providers, environments, map[string]*imported{}, &[]esc.EnvironmentImport{}, execContext, false, rotateDocPaths(paths))
+	ec.planning = true
+	_, diags := ec.evaluate()
+	return ec.rotationPlan, diags
+}
+
+// rotateDocPaths converts property paths relative to an environment's values into document paths.
+func rotateDocPaths(paths []resource.PropertyPath) map[string]bool {
+	docPaths := make(map[string]bool, len(paths))
 	for _, path := range paths {
-		rotateDocPaths["values."+path.String()] = true
+		docPaths["values."+path.String()] = true
 	}
-	return evalEnvironment(ctx, false, true, name, env, decrypter, providers, environments, execContext, true, rotateDocPaths)
+	return docPaths
 }
 
 // evalEnvironment evaluates an environment and exports the result of evaluation.
@@ -142,7 +248,7 @@ func evalEnvironment(
 		return nil, nil, nil
 	}
 
-	ec := newEvalContext(ctx, validating, rotating, name, env, true, decrypter, providers, envs, map[string]*imported{}, execContext, showSecrets, rotatePaths)
+	ec := newEvalContext(ctx, validating, rotating, name, env, true, decrypter, providers, envs, map[string]*imported{}, &[]esc.EnvironmentImport{}, execContext, showSecrets, rotatePaths)
 	v, diags := ec.evaluate()
 
 	s := schema.Never().Schema()
@@ -170,36 +276,48 @@ func evalEnvironment(
 		Properties:       envProperties.Value.(map[string]esc.Value),
 		Schema:           s,
 		ExecutionContext: executionContext,
+		Imports:          *ec.dependencies,
 	}, ec.rotationResult, diags
 }
 
 type imported struct {
 	evaluating bool
 	value      *value
+	schemas    []*declaredSchema // the declared schemas that apply to the environment's values
+}
+
+// A declaredSchema is a schema declared by an environment's top-level `schema` section.
+type declaredSchema struct {
+	schema *schema.Schema // the schema
+	value  *value         // the value from which the schema was built
 }
 
 // An evalContext carries the state necessary to evaluate an environment.
 type evalContext struct {
-	ctx          context.Context      // the cancellation context for evaluation
-	validating   bool                 // true if we are only checking the environment
-	rotating     bool                 // true if we are invoking rotators
-	showSecrets  bool                 // true if secrets should be decrypted during validation
-	name         string               // the name of the environment
-	env          *ast.EnvironmentDecl // the root of the environment AST
-	isRootEnv    bool                 // true if this environment is the root of evaluation (not an import)
-	decrypter    Decrypter            // the decrypter to use for the environment
-	providers    ProviderLoader       // the provider loader to use
-	environments EnvironmentLoader    // the environment loader to use
-	imports      map[string]*imported // the shared set of imported environments
-	execContext  *esc.ExecContext     // evaluation context used for interpolation
-
-	myContext *value // evaluated context to be used to interpolate properties
-	myImports *value // directly-imported environments
-	root      *expr  // the root expression
-	base      *value // the base value
+	ctx          context.Context          // the cancellation context for evaluation
+	validating   bool                     // true if we are only checking the environment
+	rotating     bool                     // true if we are invoking rotators
+	planning     bool                     // true if we are planning rotations rather than invoking rotators
+	showSecrets  bool                     // true if secrets should be decrypted during validation
+	name         string                   // the name of the environment
+	env          *ast.EnvironmentDecl     // the root of the environment AST
+	isRootEnv    bool                     // true if this environment is the root of evaluation (not an import)
+	decrypter    Decrypter                // the decrypter to use for the environment
+	providers    ProviderLoader           // the provider loader to use
+	environments EnvironmentLoader        // the environment loader to use
+	imports      map[string]*imported     // the shared set of imported environments
+	dependencies *[]esc.EnvironmentImport // the shared list of imports declared by environments in the import closure
+	execContext  *esc.ExecContext         // evaluation context used for interpolation
+
+	myContext *value            // evaluated context to be used to interpolate properties
+	myImports *value            // directly-imported environments
+	root      *expr             // the root expression
+	base      *value            // the base value
+	schemas   []*declaredSchema // the declared schemas of the environment and its merged imports
 
 	rotateDocPaths map[string]bool // the subset of document paths to invoke rotation for when rotating. if empty, all rotators will be invoked.
 	rotationResult RotationResult  // result of secret rotations
+	rotationPlan   RotationPlan    // planned secret rotations
 
 	diags syntax.Diagnostics // diagnostics generated during evaluation
 }
@@ -215,10 +333,15 @@ func newEvalContext(
 	providers ProviderLoader,
 	environments EnvironmentLoader,
 	imports map[string]*imported,
+	dependencies *[]esc.EnvironmentImport,
 	execContext *esc.ExecContext,
 	showSecrets bool,
 	rotateDocPaths map[string]bool,
 ) *evalContext {
+	// Pinned imports are evaluated under their pinned name, but the execution context refers to the environment by
+	// its unversioned name.
+	envName, _ := splitEnvironmentVersion(name)
+
 	return &evalContext{
 		ctx:            ctx,
 		validating:     validating,
@@ -231,7 +354,8 @@ func newEvalContext(
 		providers:      providers,
 		environments:   environments,
 		imports:        imports,
-		execContext:    execContext.CopyForEnv(name),
+		dependencies:   dependencies,
+		execContext:    execContext.CopyForEnv(envName),
 		rotateDocPaths: rotateDocPaths,
 	}
 }
@@ -241,24 +365,24 @@ func (e *evalContext) decryptSecrets() bool {
 	return !e.validating || e.showSecrets
 }
 
-// error records an evaluation error associated with an expression.
-func (e *evalContext) error(expr ast.Expr, summary string) {
-	diag := ast.ExprError(expr, summary)
+// error records an evaluation error with the given code associated with an expression.
+func (e *evalContext) error(expr ast.Expr, code, summary string) {
+	diag := ast.ExprError(expr, summary).WithCode(code)
 	e.diags.Extend(diag)
 }
 
 // errorf is like error, but accepts a format string and arguments (ala fmt.Sprintf)
-func (e *evalContext) errorf(expr ast.Expr, format string, a ...any) {
-	e.error(expr, fmt.Sprintf(format, a...))
+func (e *evalContext) errorf(expr ast.Expr, code, format string, a ...any) {
+	e.error(expr, code, fmt.Sprintf(format, a...))
 }
 
-func (e *evalContext) accessorError(expr ast.Expr, accessor ast.PropertyAccessor, summary string) {
-	diag := ast.AccessorError(expr, accessor, summary)
+func (e *evalContext) accessorError(expr ast.Expr, accessor ast.PropertyAccessor, code, summary string) {
+	diag := ast.AccessorError(expr, accessor, summary).WithCode(code)
 	e.diags.Extend(diag)
 }
 
-func (e *evalContext) accessorErrorf(expr ast.Expr, accessor ast.PropertyAccessor, format string, a ...any) {
-	e.accessorError(expr, accessor, fmt.Sprintf(format, a...))
+func (e *evalContext) accessorErrorf(expr ast.Expr, accessor ast.PropertyAccessor, code, format string, a ...any) {
+	e.accessorError(expr, accessor, code, fmt.Sprintf(format, a...))
 }
 
 type exprNode interface {
@@ -411,7 +535,7 @@ func declare[Expr exprNode](e *evalContext, path string, x Expr, base *value) *e
 			if entry.Key != nil {
 				k := entry.Key.Value
 				if _, ok := properties[k]; ok {
-					e.errorf(entry.Key, "duplicate key %q", k)
+					e.errorf(entry.Key, syntax.CodeDuplicateKey, "duplicate key %q", k)
 				} else {
 					properties[k] = declare(e, util.JoinKey(path, k), entry.Value, base.property(entry.Key, k))
 				}
@@ -456,24 +580,131 @@ func (e *evalContext) evaluate() (*value, syntax.Diagnostics) {
 		base: e.base,
 	}
 
+	// Evaluate the environment's declared schema. The schema is evaluated before the root value's properties are
+	// declared so that its defaults can be applied as base values, which makes defaulted properties visible to
+	// references. As a consequence, the schema may only refer to imported values. The declared schemas of the
+	// environment and its imports are only checked against the values of the root environment, as imported values may
+	// be completed by their importers.
+	declared := e.evaluateDeclaredSchema()
+	mine.schemas = e.schemas
+	e.applyDeclaredDefaults(declared)
+	e.root.base = e.base
+
 	// Declare the root value's properties.
 	for _, entry := range e.env.Values.GetEntries() {
 		key := entry.Key.GetValue()
 
 		if e.isReserveTopLevelKey(key) {
-			e.errorf(entry.Key, "%q is a reserved key", key)
+			e.errorf(entry.Key, syntax.CodeReservedKey, "%q is a reserved key", key)
 		} else if _, ok := properties[key]; ok {
-			e.errorf(entry.Key, "duplicate key %q", key)
+			e.errorf(entry.Key, syntax.CodeDuplicateKey, "duplicate key %q", key)
 		} else {
 			properties[key] = declare(e, key, entry.Value, e.base.property(entry.Key, key))
 		}
 	}
 
-	// Evaluate the root value and return.
+	// Evaluate the root value.
 	v := e.evaluateExpr(e.root, schema.Always())
+
+	if e.isRootEnv {
+		e.validateDeclaredSchemas(v)
+	}
+
+	e.filterAllowedDiagnostics()
 	return v, e.diags
 }
 
+// evaluateDeclaredSchema evaluates the environment's top-level schema, if any, adds it to the environment's declared
+// schemas, and returns it.
+func (e *evalContext) evaluateDeclaredSchema() *declaredSchema {
+	if e.env.Schema == nil {
+		return nil
+	}
+
+	x := declare(e, "<schema>", e.env.Schema, nil)
+	v, ok := e.evaluateTypedExpr(x, schema.JSONSchemaSchema())
+	if !ok || v.containsUnknowns() {
+		return nil
+	}
+
+	s, err := e.valueToSchema(v)
+	if err == nil {
+		err = s.Compile()
+	}
+	if err != nil {
+		e.errorf(e.env.Schema, syntax.CodeInvalidSchema, "invalid schema: %v", err)
+		return nil
+	}
+	declared := &declaredSchema{schema: s, value: v}
+	e.schemas = append(e.schemas, declared)
+	return declared
+}
+
+// applyDeclaredDefaults applies the defaults of the environment's own declared schema, if any, as the lowest layer of
+// the environment's base value. Values declared by the environment and its imports take precedence over the defaults.
+// The defaults of an imported environment's schema are applied by the imported environment itself.
+func (e *evalContext) applyDeclaredDefaults(s *declaredSchema) {
+	if s == nil {
+		return
+	}
+
+	defaults := schemaDefaults(s.schema, s.value, s.value.def)
+	switch {
+	case defaults == nil:
+		return
+	case e.base == nil:
+		e.base = defaults
+	default:
+		e.base.merge(defaults)
+	}
+}
+
+// validateDeclaredSchemas validates the environment's values against its declared schemas. Violations are reported
+// at the offending values. Violations that are not associated with a particular value (e.g. missing top-level
+// properties) are reported at the environment's values.
+func (e *evalContext) validateDeclaredSchemas(v *value) {
+	if len(e.schemas) == 0 {
+		return
+	}
+
+	var subject *hcl.Range
+	if e.env.Values != nil && e.env.Values.Syntax() != nil {
+		subject = e.env.Values.Syntax().Syntax().Range()
+	} else if node := e.env.Syntax(); node != nil {
+		subject = node.Syntax().Range()
+	}
+
+	for _, s := range e.schemas {
+		var vv validator
+		vv.validateValue(v, s.schema, validationLoc{x: v.def})
+		for _, diag := range vv.diags {
+			if diag.Subject == nil {
+				diag.Subject = subject
+			}
+		}
+		e.diags.Extend(vv.diags...)
+	}
+}
+
+// filterAllowedDiagnostics removes warnings that are allowed by the environment's definition from the diagnostics
+// for this environment. Diagnostics issued for imported environments have already been filtered by their own
+// definitions.
+func (e *evalContext) filterAllowedDiagnostics() {
+	allowed := e.env.Diagnostics
+	if allowed == nil {
+		return
+	}
+
+	var diags syntax.Diagnostics
+	for _, diag := range e.diags {
+		if (diag.Subject == nil || diag.Subject.Filename == e.name) && allowed.Allows(diag) {
+			continue
+		}
+		diags = append(diags, diag)
+	}
+	e.diags = diags
+}
+
 func (e *evalContext) evaluateContext() {
 	def := declare(e, "", ast.Symbol(&ast.PropertyName{Name: "context"}), nil)
 	e.myContext = unexport(esc.NewValue(e.execContext.Values()), def)
@@ -488,20 +719,83 @@ func (e *evalContext) evaluateImports() {
 		if entry.Environment == nil {
 			continue
 		}
-		name := entry.Environment.Value
+		name, version := splitEnvironmentVersion(entry.Environment.Value)
+		switch version {
+		case "":
+			if name != entry.Environment.Value {
+				e.error(entry.Environment, syntax.CodeInvalidImport, "the version of a pinned import must not be empty")
+				continue
+			}
+		case "latest":
+			// Imports pinned to latest are equivalent to unpinned imports.
+		default:
+			name = name + "@" + version
+		}
 
-		merge := true
+		merge, optional := true, false
 		if entry.Meta != nil && entry.Meta.Merge != nil {
 			merge = entry.Meta.Merge.Value
 		}
+		if entry.Meta != nil && entry.Meta.Optional != nil {
+			optional = entry.Meta.Optional.Value
+		}
 
-		val, ok := e.evaluateImport(entry.Environment, name)
+		*e.dependencies = append(*e.dependencies, esc.EnvironmentImport{
+			Importer:    e.name,
+			Environment: name,
+			Version:     version,
+			Range:       convertRange(entry.Syntax().Syntax().Range(), e.name),
+			Optional:    optional,
+			Conditional: entry.Meta != nil && entry.Meta.When != nil,
+		})
+		dependency := len(*e.dependencies) - 1
+
+		if entry.Meta != nil && entry.Meta.When != nil {
+			if !e.evaluateImportCondition(entry.Meta.When) {
+				(*e.dependencies)[dependency].Skipped = true
+				continue
+			}
+		}
+
+		val, ok, skipped := e.evaluateImport(entry.Environment, name, optional)
 		if !ok {
+			(*e.dependencies)[dependency].Skipped = skipped
 			continue
 		}
 
+		if entry.Meta != nil && entry.Meta.Path != nil {
+			// If the path failed to parse, the error has already been reported.
+			path := entry.Meta.PropertyPath()
+			if path == nil {
+				continue
+			}
+			if val, ok = e.selectImportPath(entry, val, path); !ok {
+				continue
+			}
+		}
+
 		myImports[name] = val
 		if merge {
+			if entry.Meta != nil && entry.Meta.As != nil {
+				if val, ok = e.aliasImport(entry.Meta.As, val); !ok {
+					continue
+				}
+			} else if entry.Meta.PropertyPath() != nil && !val.isObject() {
+				e.errorf(entry.Meta.Path, syntax.CodeInvalidImportPath, "path %q selects a non-object value from %v; use 'as' to import it under a key",
+					entry.Meta.Path.Value, name)
+				continue
+			}
+
+			// The declared schemas of an import only apply to the importer's values if the import is merged as a
+			// whole.
+			if entry.Meta.PropertyPath() == nil && (entry.Meta == nil || entry.Meta.As == nil) {
+				for _, s := range e.imports[name].schemas {
+					if !slices.Contains(e.schemas, s) {
+						e.schemas = append(e.schemas, s)
+					}
+				}
+			}
+
 			val = newCopier().copy(val)
 			val.merge(e.base)
 			e.base = val
@@ -527,43 +821,245 @@ func (e *evalContext) evaluateImports() {
 	e.myImports = val
 }
 
-// evaluateImport evaluates an imported environment.
+// selectImportPath selects the subtree of an imported environment's values that is named by the import's path.
+// Returns false if the path does not exist.
+func (e *evalContext) selectImportPath(entry *ast.ImportDecl, val *value, path *ast.PropertyAccess) (*value, bool) {
+	x := entry.Meta.Path
+	for i, accessor := range path.Accessors {
+		prefix := (&ast.PropertyAccess{Accessors: path.Accessors[:i+1]}).String()
+
+		if val.unknown {
+			s := val.schema
+			if s.Always {
+				val = &value{def: val.def, schema: s, unknown: true, secret: val.secret}
+				continue
+			}
+			switch a := accessor.(type) {
+			case *ast.PropertyName:
+				s = s.Property(a.Name)
+			case *ast.PropertySubscript:
+				switch index := a.Index.(type) {
+				case string:
+					s = s.Property(index)
+				case int:
+					s = s.Item(index)
+				}
+			}
+			if s.Never {
+				e.accessorErrorf(x, accessor, syntax.CodeInvalidImportPath, "path %q does not exist in %v", prefix, entry.Environment.Value)
+				return nil, false
+			}
+			val = &value{def: val.def, schema: s, unknown: true, secret: val.secret}
+			continue
+		}
+
+		switch repr := val.repr.(type) {
+		case []*value:
+			sub, ok := accessor.(*ast.PropertySubscript)
+			index, isInt := 0, false
+			if ok {
+				index, isInt = sub.Index.(int)
+			}
+			if !isInt || index < 0 || index >= len(repr) {
+				e.accessorErrorf(x, accessor, syntax.CodeInvalidImportPath, "path %q does not exist in %v", prefix, entry.Environment.Value)
+				return nil, false
+			}
+			val = repr[index]
+		case map[string]*value:
+			var key string
+			switch a := accessor.(type) {
+			case *ast.PropertyName:
+				key = a.Name
+			case *ast.PropertySubscript:
+				k, ok := a.Index.(string)
+				if !ok {
+					e.accessorErrorf(x, accessor, syntax.CodeInvalidImportPath, "path %q does not exist in %v: cannot access an object property using an integer index",
+						prefix, entry.Environment.Value)
+					return nil, false
+				}
+				key = k
+			}
+
+			prop := val.property(x, key)
+			if prop == nil {
+				nearest := spell.Nearest(key, slices.Values(val.keys()))
+				e.accessorErrorf(x, accessor, syntax.CodeInvalidImportPath, "path %q does not exist in %v%v", prefix, entry.Environment.Value, didYouMean(nearest))
+				return nil, false
+			}
+			val = prop
+		default:
+			e.accessorErrorf(x, accessor, syntax.CodeInvalidImportPath, "path %q does not exist in %v: receiver must be an array or an object",
+				prefix, entry.Environment.Value)
+			return nil, false
+		}
+	}
+	return val, true
+}
+
+// aliasImport mounts an imported value under the given key. Returns false if the key is reserved.
+func (e *evalContext) aliasImport(alias *ast.StringExpr, val *value) (*value, bool) {
+	key := alias.Value
+	if key == "" || strings.HasPrefix(key, "fn::") {
+		// The error has already been reported during parsing.
+		return nil, false
+	}
+	if e.isReserveTopLevelKey(key) {
+		e.errorf(alias, syntax.CodeReservedKey, "%q is a reserved key", key)
+		return nil, false
+	}
+
+	s := schema.Record(schema.SchemaMap{key: val.schema}).Schema()
+	def := newExpr("", &objectExpr{node: ast.Object(), properties: map[string]*expr{key: val.def}}, s, nil)
+	def.state = exprDone
+
+	v := &value{def: def, schema: s, repr: map[string]*value{key: val}}
+	def.value = v
+	return v, true
+}
+
+// loadEnvironment loads the definition for an imported environment. If the import is pinned to a version, the
+// environment loader must be a VersionedEnvironmentLoader.
+func (e *evalContext) loadEnvironment(ref string) ([]byte, Decrypter, error) {
+	name, version := splitEnvironmentVersion(ref)
+	if version == "" {
+		return e.environments.LoadEnvironment(e.ctx, name)
+	}
+
+	loader, ok := e.environments.(VersionedEnvironmentLoader)
+	if !ok {
+		return nil, nil, fmt.Errorf("cannot import %v: versioned imports are not supported", ref)
+	}
+	return loader.LoadEnvironmentVersion(e.ctx, name, version)
+}
+
+// evaluateImportCondition evaluates the condition for an import. Returns true if the condition holds.
+func (e *evalContext) evaluateImportCondition(cond *ast.ImportConditionDecl) bool {
+	// Can happen if there are parse errors.
+	if cond.Value == nil {
+		return false
+	}
+	if !e.checkImportConditionRefs(cond.Value) {
+		return false
+	}
+
+	v := e.evaluateExpr(declare(e, "", cond.Value, nil), schema.Always())
+	if v.containsUnknowns() {
+		e.error(cond.Value, syntax.CodeInvalidImportCond, "import condition must be known")
+		return false
+	}
+	actual, diags := v.export("")
+	e.diags.Extend(diags...)
+
+	switch {
+	case cond.Matches != nil:
+		str, ok := actual.Value.(string)
+		if !ok {
+			e.error(cond.Value, syntax.CodeInvalidImportCond, "the value of an import condition with 'matches' must be a string")
+			return false
+		}
+		re, err := regexp.Compile(cond.Matches.Value)
+		if err != nil {
+			e.errorf(cond.Matches, syntax.CodeInvalidImportCond, "invalid regular expression: %v", err)
+			return false
+		}
+		return re.MatchString(str)
+	case cond.Equals != nil:
+		if !e.checkImportConditionRefs(cond.Equals) {
+			return false
+		}
+		ev := e.evaluateExpr(declare(e, "", cond.Equals, nil), schema.Always())
+		if ev.containsUnknowns() {
+			e.error(cond.Equals, syntax.CodeInvalidImportCond, "import condition must be known")
+			return false
+		}
+		expected, diags := ev.export("")
+		e.diags.Extend(diags...)
+		return reflect.DeepEqual(actual.ToJSON(false), expected.ToJSON(false))
+	default:
+		b, ok := actual.Value.(bool)
+		if !ok {
+			e.error(cond.Value, syntax.CodeInvalidImportCond, "the value of an import condition without 'equals' or 'matches' must be a boolean")
+			return false
+		}
+		return b
+	}
+}
+
+// checkImportConditionRefs checks that an expression used in an import condition is either a literal or only refers
+// to the execution context. Import conditions are evaluated before the environment's values are declared.
+func (e *evalContext) checkImportConditionRefs(x ast.Expr) bool {
+	var accesses []*ast.PropertyAccess
+	switch x := x.(type) {
+	case *ast.NullExpr, *ast.BooleanExpr, *ast.NumberExpr, *ast.StringExpr:
+		return true
+	case *ast.SymbolExpr:
+		accesses = append(accesses, x.Property)
+	case *ast.InterpolateExpr:
+		for _, p := range x.Parts {
+			if p.Value != nil {
+				accesses = append(accesses, p.Value)
+			}
+		}
+	default:
+		e.error(x, syntax.CodeInvalidImportCond, "import conditions must be literals or references to 'context'")
+		return false
+	}
+
+	for _, access := range accesses {
+		if name, ok := access.Accessors[0].(*ast.PropertyName); !ok || name.Name != "context" {
+			e.accessorError(x, access.Accessors[0], syntax.CodeInvalidImportCond, "import conditions may only refer to 'context'")
+			return false
+		}
+	}
+	return true
+}
+
+// evaluateImport evaluates an imported environment. If the import is optional and the environment does not exist, a
+// warning is issued and evaluateImport returns skipped = true. Other errors loading an optional import (e.g.
+// authorization or decryption failures) are reported as errors so that configuration is not silently dropped.
 //
 // Each environment in the import closure is only evaluated once.
-func (e *evalContext) evaluateImport(expr ast.Expr, name string) (*value, bool) {
-	var val *value
+func (e *evalContext) evaluateImport(expr ast.Expr, name string, optional bool) (val *value, ok bool, skipped bool) {
 	if imported, ok := e.imports[name]; ok {
 		if imported.evaluating {
-			e.diags.Extend(syntax.Error(expr.Syntax().Syntax().Range(), fmt.Sprintf("cyclic import of %v", name), expr.Syntax().Syntax().Path()))
-			return nil, false
+			s := expr.Syntax().Syntax()
+			e.diags.Extend(syntax.Error(s.Range(), fmt.Sprintf("cyclic import of %v", name), s.Path()).WithCode(syntax.CodeCyclicImport).WithAliasRange(s))
+			return nil, false, false
 		}
 		val = imported.value
 	} else {
-		bytes, dec, err := e.environments.LoadEnvironment(e.ctx, name)
+		bytes, dec, err := e.loadEnvironment(name)
 		if err != nil {
-			e.errorf(expr, "%s", err.Error())
-			return nil, false
+			if optional && errors.Is(err, ErrEnvironmentNotFound) {
+				diag := ast.ExprError(expr, fmt.Sprintf("skipping optional import of %v: environment not found", name))
+				diag.Severity = hcl.DiagWarning
+				diag.Code = syntax.CodeOptionalImportSkipped
+				e.diags.Extend(diag)
+				return nil, false, true
+			}
+			e.errorf(expr, syntax.CodeImportFailed, "%s", err.Error())
+			return nil, false, false
 		}
 
 		env, diags, err := LoadYAMLBytes(name, bytes)
 		e.diags.Extend(diags...)
 		if err != nil {
-			e.errorf(expr, "%s", err.Error())
-			return nil, false
+			e.errorf(expr, syntax.CodeImportFailed, "%s", err.Error())
+			return nil, false, false
 		}
 		if diags.HasErrors() {
-			return nil, false
+			return nil, false, false
 		}
 
 		// we only want to rotate the root environment, so set rotating flag to false when evaluating imports
-		imp := newEvalContext(e.ctx, e.validating, false, name, env, false, dec, e.providers, e.environments, e.imports, e.execContext, e.showSecrets, nil)
+		imp := newEvalContext(e.ctx, e.validating, false, name, env, false, dec, e.providers, e.environments, e.imports, e.dependencies, e.execContext, e.showSecrets, nil)
 		v, diags := imp.evaluate()
 		e.diags.Extend(diags...)
 
 		val = v
 		e.imports[name].value = val
 	}
-	return val, true
+	return val, true, false
 }
 
 // evaluateExpr evaluates an expression. If the expression has already been evaluated, it returns the
@@ -574,7 +1070,7 @@ func (e *evalContext) evaluateExpr(x *expr, accept *schema.Schema) *value {
 	case exprDone:
 		return x.value
 	case exprEvaluating:
-		e.errorf(x.repr.syntax(), "cyclic reference to %v", x.path)
+		e.errorf(x.repr.syntax(), syntax.CodeCyclicReference, "cyclic reference to %v", x.path)
 		return &value{
 			def:     x,
 			schema:  schema.Always().Schema(),
@@ -596,6 +1092,7 @@ func (e *evalContext) evaluateExpr(x *expr, accept *schema.Schema) *value {
 	if x.base != nil && x.base.final {
 		diag := ast.ExprError(x.repr.syntax(), "cannot override final value")
 		diag.Severity = hcl.DiagWarning
+		diag.Code = syntax.CodeFinalOverride
 		e.diags.Extend(diag)
 		val := x.base
 		x.schema = val.schema
@@ -797,7 +1294,7 @@ func (e *evalContext) evaluateExprAccess(x *expr, accessors []*propertyAccessor,
 	for len(accessors) > 0 {
 		accessor := accessors[0]
 		if receiver == nil {
-			e.errorf(x.repr.syntax(), "internal error: no receiver")
+			e.errorf(x.repr.syntax(), syntax.CodeInternalError, "internal error: no receiver")
 			return e.invalidPropertyAccess(x.repr.syntax(), accessors)
 		}
 
@@ -823,7 +1320,7 @@ func (e *evalContext) evaluateExprAccess(x *expr, accessors []*propertyAccessor,
 					return e.evaluateValueAccess(x.repr.syntax(), receiver.base, accessors)
 				}
 				nearest := spell.Nearest(key, maps.Keys(repr.properties))
-				e.accessorErrorf(x.repr.syntax(), accessor.accessor, "unknown property %q%v", key, didYouMean(nearest))
+				e.accessorErrorf(x.repr.syntax(), accessor.accessor, syntax.CodeUnknownProperty, "unknown property %q%v", key, didYouMean(nearest))
 				return e.invalidPropertyAccess(x.repr.syntax(), accessors)
 			}
 			receiver = prop
@@ -862,7 +1359,7 @@ func (e *evalContext) evaluateEnvironmentReferenceAccess(x *expr, accessors []*p
 	}
 	qualifiedName := fmt.Sprintf("%s/%s", projName, envName)
 
-	importedValue, ok := e.evaluateImport(x.repr.syntax(), qualifiedName)
+	importedValue, ok, _ := e.evaluateImport(x.repr.syntax(), qualifiedName, false)
 	if !ok {
 		// failed to import, treat as missing
 		importedValue = &value{def: newMissingExpr("", nil), schema: schema.Always(), unknown: true}
@@ -896,25 +1393,25 @@ func (e *evalContext) evaluateEnvironmentReferenceAccess(x *expr, accessors []*p
 }
 
 // evaluateValueAccess evaluates a list of accessors relative to a value receiver.
-func (e *evalContext) evaluateValueAccess(syntax ast.Expr, receiver *value, accessors []*propertyAccessor) *value {
+func (e *evalContext) evaluateValueAccess(node ast.Expr, receiver *value, accessors []*propertyAccessor) *value {
 	for len(accessors) > 0 {
 		accessor := accessors[0]
 
 		if receiver.unknown {
-			return e.evaluateUnknownAccess(syntax, receiver.schema, accessors)
+			return e.evaluateUnknownAccess(node, receiver.schema, accessors)
 		}
 
 		switch repr := receiver.repr.(type) {
 		case []*value:
-			index, ok := e.arrayIndex(syntax, accessor.accessor, len(repr))
+			index, ok := e.arrayIndex(node, accessor.accessor, len(repr))
 			if !ok {
-				return e.invalidPropertyAccess(syntax, accessors)
+				return e.invalidPropertyAccess(node, accessors)
 			}
 			receiver = repr[index]
 		case map[string]*value:
-			key, ok := e.objectKey(syntax, accessor.accessor, true)
+			key, ok := e.objectKey(node, accessor.accessor, true)
 			if !ok {
-				return e.invalidPropertyAccess(syntax, accessors)
+				return e.invalidPropertyAccess(node, accessors)
 			}
 
 			// Check for the property in the object itself. If the property does not exist and the value's base is also
@@ -923,16 +1420,16 @@ func (e *evalContext) evaluateValueAccess(syntax ast.Expr, receiver *value, acce
 			prop, ok := repr[key]
 			if !ok {
 				if receiver.base.isObject() {
-					return e.evaluateValueAccess(syntax, receiver.base, accessors)
+					return e.evaluateValueAccess(node, receiver.base, accessors)
 				}
 				nearest := spell.Nearest(key, maps.Keys(repr))
-				e.accessorErrorf(syntax, accessor.accessor, "unknown property %q%v", key, didYouMean(nearest))
-				return e.invalidPropertyAccess(syntax, accessors)
+				e.accessorErrorf(node, accessor.accessor, syntax.CodeUnknownProperty, "unknown property %q%v", key, didYouMean(nearest))
+				return e.invalidPropertyAccess(node, accessors)
 			}
 			receiver = prop
 		default:
-			e.accessorError(syntax, accessor.accessor, "receiver must be an array or an object")
-			return e.invalidPropertyAccess(syntax, accessors)
+			e.accessorError(node, accessor.accessor, syntax.CodeInvalidAccess, "receiver must be an array or an object")
+			return e.invalidPropertyAccess(node, accessors)
 		}
 
 		accessor.value, accessors = receiver, accessors[1:]
@@ -943,7 +1440,7 @@ func (e *evalContext) evaluateValueAccess(syntax ast.Expr, receiver *value, acce
 
 // evaluateValueAccess evaluates a list of accessors relative to an unknown value receiver. Unknown values are
 // synthesized for each receiver.
-func (e *evalContext) evaluateUnknownAccess(syntax ast.Expr, receiver *schema.Schema, accessors []*propertyAccessor) *value {
+func (e *evalContext) evaluateUnknownAccess(node ast.Expr, receiver *schema.Schema, accessors []*propertyAccessor) *value {
 	var val *value
 	for len(accessors) > 0 {
 		accessor := accessors[0]
@@ -955,26 +1452,26 @@ func (e *evalContext) evaluateUnknownAccess(syntax ast.Expr, receiver *schema.Sc
 				if receiver.Items.Never {
 					n = len(receiver.PrefixItems)
 				}
-				index, ok := e.arrayIndex(syntax, accessor.accessor, n)
+				index, ok := e.arrayIndex(node, accessor.accessor, n)
 				if !ok {
-					return e.invalidPropertyAccess(syntax, accessors)
+					return e.invalidPropertyAccess(node, accessors)
 				}
 				receiver = receiver.Item(index)
 			case "object":
-				key, ok := e.objectKey(syntax, accessor.accessor, true)
+				key, ok := e.objectKey(node, accessor.accessor, true)
 				if !ok {
-					return e.invalidPropertyAccess(syntax, accessors)
+					return e.invalidPropertyAccess(node, accessors)
 				}
 				receiver = receiver.Property(key)
 			default:
-				e.accessorError(syntax, accessor.accessor, "receiver must be an array or an object")
-				return e.invalidPropertyAccess(syntax, accessors)
+				e.accessorError(node, accessor.accessor, syntax.CodeInvalidAccess, "receiver must be an array or an object")
+				return e.invalidPropertyAccess(node, accessors)
 			}
 		}
 
 		val = &value{
 			def: &expr{
-				repr:  &literalExpr{node: syntax},
+				repr:  &literalExpr{node: node},
 				state: exprDone,
 			},
 			schema:  receiver,
@@ -987,11 +1484,11 @@ func (e *evalContext) evaluateUnknownAccess(syntax ast.Expr, receiver *schema.Sc
 }
 
 // invalidPropertyAccess resolves each accessor to an unknown value.
-func (e *evalContext) invalidPropertyAccess(syntax ast.Expr, accessors []*propertyAccessor) *value {
+func (e *evalContext) invalidPropertyAccess(node ast.Expr, accessors []*propertyAccessor) *value {
 	for _, accessor := range accessors {
 		accessor.value = &value{
 			def: &expr{
-				repr:  &literalExpr{node: syntax},
+				repr:  &literalExpr{node: node},
 				state: exprDone,
 			},
 			schema:  schema.Always().Schema(),
@@ -1006,20 +1503,20 @@ func (e *evalContext) invalidPropertyAccess(syntax ast.Expr, accessors []*proper
 func (e *evalContext) arrayIndex(expr ast.Expr, accessor ast.PropertyAccessor, len int) (int, bool) {
 	sub, ok := accessor.(*ast.PropertySubscript)
 	if !ok {
-		e.accessorError(expr, accessor, "cannot access an array element using a property name")
+		e.accessorError(expr, accessor, syntax.CodeInvalidAccess, "cannot access an array element using a property name")
 		return 0, false
 	}
 	index, ok := sub.Index.(int)
 	if !ok {
-		e.accessorError(expr, accessor, "cannot access an array element using a property name")
+		e.accessorError(expr, accessor, syntax.CodeInvalidAccess, "cannot access an array element using a property name")
 		return 0, false
 	}
 	if index < 0 {
-		e.accessorError(expr, accessor, "array indices must not be negative")
+		e.accessorError(expr, accessor, syntax.CodeInvalidAccess, "array indices must not be negative")
 		return 0, false
 	}
 	if len >= 0 && index >= len {
-		e.accessorErrorf(expr, accessor, "array index %v out-of-bounds for array of length %v", index, len)
+		e.accessorErrorf(expr, accessor, syntax.CodeInvalidAccess, "array index %v out-of-bounds for array of length %v", index, len)
 		return 0, false
 	}
 	return index, true
@@ -1035,7 +1532,7 @@ func (e *evalContext) objectKey(expr ast.Expr, accessor ast.PropertyAccessor, mu
 		s, ok := a.Index.(string)
 		if !ok {
 			if must {
-				e.accessorError(expr, accessor, "cannot access an object property using an integer index")
+				e.accessorError(expr, accessor, syntax.CodeInvalidAccess, "cannot access an object property using an integer index")
 			}
 			return "", false
 		}
@@ -1057,18 +1554,18 @@ func (e *evalContext) evaluateBuiltinSecret(x *expr, repr *secretExpr) *value {
 
 	ciphertext, err := decodeCiphertext(repr.node.Ciphertext.Value)
 	if err != nil {
-		e.errorf(repr.syntax(), "invalid ciphertext: %v", err)
+		e.errorf(repr.syntax(), syntax.CodeDecryptFailed, "invalid ciphertext: %v", err)
 		v.unknown = true
 		return v
 	}
 	if !e.decryptSecrets() {
-		v.unknown = true
+		v.unknown, v.encrypted = true, true
 		return v
 	}
 
 	plaintext, err := e.decrypter.Decrypt(e.ctx, ciphertext)
 	if err != nil {
-		e.errorf(repr.syntax(), "decrypting: %v", err)
+		e.errorf(repr.syntax(), syntax.CodeDecryptFailed, "decrypting: %v", err)
 		v.unknown = true
 		return v
 	}
@@ -1092,16 +1589,16 @@ func (e *evalContext) evaluateBuiltinOpen(x *expr, repr *openExpr) *value {
 
 	provider, err := e.providers.LoadProvider(e.ctx, repr.node.Provider.GetValue())
 	if err != nil {
-		e.errorf(repr.syntax(), "%v", err)
+		e.errorf(repr.syntax(), syntax.CodeProviderFailed, "%v", err)
 	} else {
 		inputSchema, outputSchema := provider.Schema()
 		if err := inputSchema.Compile(); err != nil {
-			e.errorf(repr.syntax(), "internal error: invalid input schema (%v)", err)
+			e.errorf(repr.syntax(), syntax.CodeInternalError, "internal error: invalid input schema (%v)", err)
 		} else {
 			repr.inputSchema = inputSchema
 		}
 		if err := outputSchema.Compile(); err != nil {
-			e.errorf(repr.syntax(), "internal error: invalid schema (%v)", err)
+			e.errorf(repr.syntax(), syntax.CodeInternalError, "internal error: invalid schema (%v)", err)
 		} else {
 			x.schema = outputSchema
 		}
@@ -1120,7 +1617,7 @@ func (e *evalContext) evaluateBuiltinOpen(x *expr, repr *openExpr) *value {
 
 	output, err := provider.Open(e.ctx, inputsV.Value.(map[string]esc.Value), e.execContext)
 	if err != nil {
-		e.errorf(repr.syntax(), "%s", err.Error())
+		e.errorf(repr.syntax(), syntax.CodeOpenFailed, "%s", err.Error())
 		v.unknown = true
 		return v
 	}
@@ -1143,22 +1640,22 @@ func (e *evalContext) evaluateBuiltinRotate(x *expr, repr *rotateExpr) *value {
 
 	rotator, err := e.providers.LoadRotator(e.ctx, repr.node.Provider.GetValue())
 	if err != nil {
-		e.errorf(repr.syntax(), "%v", err)
+		e.errorf(repr.syntax(), syntax.CodeProviderFailed, "%v", err)
 	} else {
 		inputSchema, stateSchema, outputSchema := rotator.Schema()
 		stateSchema = schema.OneOf(stateSchema, schema.Null())
 		if err := inputSchema.Compile(); err != nil {
-			e.errorf(repr.syntax(), "internal error: invalid input schema (%v)", err)
+			e.errorf(repr.syntax(), syntax.CodeInternalError, "internal error: invalid input schema (%v)", err)
 		} else {
 			repr.inputSchema = inputSchema
 		}
 		if err := stateSchema.Compile(); err != nil {
-			e.errorf(repr.syntax(), "internal error: invalid state schema (%v)", err)
+			e.errorf(repr.syntax(), syntax.CodeInternalError, "internal error: invalid state schema (%v)", err)
 		} else {
 			repr.stateSchema = stateSchema
 		}
 		if err := outputSchema.Compile(); err != nil {
-			e.errorf(repr.syntax(), "internal error: invalid schema (%v)", err)
+			e.errorf(repr.syntax(), syntax.CodeInternalError, "internal error: invalid schema (%v)", err)
 		} else {
 			x.schema = outputSchema
 		}
@@ -1169,6 +1666,46 @@ func (e *evalContext) evaluateBuiltinRotate(x *expr, repr *rotateExpr) *value {
 
 	inputs, inputsOK := e.evaluateTypedExpr(repr.inputs, repr.inputSchema)
 	state, stateOK := e.evaluateTypedExpr(repr.state, repr.stateSchema)
+	if e.planning && e.shouldRotate(docPath) {
+		var blockers []string
+		if err != nil {
+			blockers = append(blockers, fmt.Sprintf("rotator could not be loaded: %v", err))
+		}
+		if !inputsOK {
+			blockers = append(blockers, "inputs are invalid")
+		}
+
+		var current esc.Value
+		switch {
+		case !stateOK:
+			blockers = append(blockers, "state is invalid")
+		case state.containsUnknownsExceptCiphertext():
+			blockers = append(blockers, "state contains unknown values")
+		default:
+			stateV, exportDiags := state.export("")
+			e.diags.Extend(exportDiags...)
+			current = stateV
+		}
+
+		var stateSchema *schema.Schema
+		if rotator != nil {
+			_, stateSchema, _ = rotator.Schema()
+		}
+
+		e.rotationPlan = append(e.rotationPlan, &PlannedRotation{
+			Path:    docPath,
+			Rotator: repr.node.Provider.GetValue(),
+			Patch: &Patch{
+				DocPath:     util.JoinKey(docPath, repr.node.Name().GetValue()) + ".state",
+				Replacement: redactedShape(stateSchema, current),
+			},
+			Blockers: blockers,
+		})
+
+		// The rotator is not opened, as its state would be stale.
+		v.unknown = true
+		return v
+	}
 	if !inputsOK || inputs.containsObservableUnknowns(e.rotating) || !stateOK || state.containsUnknowns() || e.validating || err != nil {
 		if e.shouldRotate(docPath) {
 			e.rotationResult = append(e.rotationResult, &Rotation{
@@ -1189,33 +1726,55 @@ func (e *evalContext) evaluateBuiltinRotate(x *expr, repr *rotateExpr) *value {
 		stateV, exportDiags := state.export("")
 		e.diags.Extend(exportDiags...)
 
-		newState, err := rotator.Rotate(
-			e.ctx,
-			inputsV.Value.(map[string]esc.Value),
-			asObjectOrNil(stateV.Value),
-			e.execContext,
-		)
+		// transactional rotators are prepared rather than rotated. prepared rotations are committed or aborted once
+		// the result of rotation is known.
+		var prepared *preparedRotation
+		var newState esc.Value
+		var err error
+		if tx, ok := rotator.(esc.TransactionalRotator); ok {
+			prepared = &preparedRotation{
+				rotator:     tx,
+				node:        repr.syntax(),
+				inputs:      inputsV.Value.(map[string]esc.Value),
+				state:       asObjectOrNil(stateV.Value),
+				execContext: e.execContext,
+			}
+			newState, err = tx.Prepare(e.ctx, prepared.inputs, prepared.state, e.execContext)
+			prepared.newState = asObjectOrNil(newState.Value)
+		} else {
+			newState, err = rotator.Rotate(
+				e.ctx,
+				inputsV.Value.(map[string]esc.Value),
+				asObjectOrNil(stateV.Value),
+				e.execContext,
+			)
+		}
 		if err != nil {
-			diag := ast.ExprError(repr.syntax(), err.Error())
+			diag := ast.ExprError(repr.syntax(), err.Error()).WithCode(syntax.CodeRotateFailed)
 			e.rotationResult = append(e.rotationResult, &Rotation{
 				Path:   docPath,
 				Status: RotationFailed,
 				Diags:  []*syntax.Diagnostic{diag},
 			})
 
-			e.errorf(repr.syntax(), "rotate: %s", err.Error())
+			e.errorf(repr.syntax(), syntax.CodeRotateFailed, "rotate: %s", err.Error())
 			v.unknown = true
 			return v
 		}
 
+		status := RotationSucceeded
+		if prepared != nil {
+			status = RotationPrepared
+		}
 		e.rotationResult = append(e.rotationResult, &Rotation{
 			Path:   docPath,
-			Status: RotationSucceeded,
+			Status: status,
 			Patch: &Patch{
 				// rotation output is written back to the fn's `state` input
 				DocPath:     util.JoinKey(docPath, repr.node.Name().GetValue()) + ".state",
 				Replacement: newState,
 			},
+			prepared: prepared,
 		})
 
 		// todo: validate newState conforms to state schema
@@ -1237,7 +1796,7 @@ func (e *evalContext) evaluateBuiltinRotate(x *expr, repr *rotateExpr) *value {
 		e.execContext,
 	)
 	if err != nil {
-		e.errorf(repr.syntax(), "%s", err.Error())
+		e.errorf(repr.syntax(), syntax.CodeOpenFailed, "%s", err.Error())
 		v.unknown = true
 		return v
 	}
@@ -1345,7 +1904,7 @@ func (e *evalContext) evaluateBuiltinFromBase64(x *expr, repr *fromBase64Expr) *
 	if !v.unknown {
 		b, err := base64.StdEncoding.DecodeString(str.repr.(string))
 		if err != nil {
-			e.errorf(repr.syntax(), "decoding base64 string: %v", err)
+			e.errorf(repr.syntax(), syntax.CodeInvalidBase64, "decoding base64 string: %v", err)
 			v.unknown = true
 			return v
 		}
@@ -1384,7 +1943,7 @@ func (e *evalContext) evaluateBuiltinValidate(x *expr, repr *validateExpr) *valu
 	// Convert the evaluated schema value to a *schema.Schema
 	validationSchema, err := e.valueToSchema(schemaVal)
 	if err != nil {
-		e.errorf(repr.schemaExpr.repr.syntax(), "invalid schema: %v", err)
+		e.errorf(repr.schemaExpr.repr.syntax(), syntax.CodeInvalidSchema, "invalid schema: %v", err)
 		val := e.evaluateExpr(repr.value, schema.Always())
 		v.schema = val.schema
 		v.repr = val.repr
@@ -1395,7 +1954,7 @@ func (e *evalContext) evaluateBuiltinValidate(x *expr, repr *validateExpr) *valu
 
 	// Compile the schema (like fn::open does with provider schemas)
 	if err := validationSchema.Compile(); err != nil {
-		e.errorf(repr.schemaExpr.repr.syntax(), "invalid schema: %v", err)
+		e.errorf(repr.schemaExpr.repr.syntax(), syntax.CodeInvalidSchema, "invalid schema: %v", err)
 		val := e.evaluateExpr(repr.value, schema.Always())
 		v.schema = val.schema
 		v.repr = val.repr
@@ -1403,9 +1962,13 @@ func (e *evalContext) evaluateBuiltinValidate(x *expr, repr *validateExpr) *valu
 		return v
 	}
 
-	// Validate value against the conform schema using evaluateTypedExpr
-	// This follows the same pattern as fn::open: inputs, ok := e.evaluateTypedExpr(repr.inputs, repr.inputSchema)
-	val, _ := e.evaluateTypedExpr(repr.value, validationSchema)
+	// Fill in missing properties from the schema's defaults, then validate the value against the conform schema.
+	val := e.evaluateExpr(repr.value, validationSchema)
+	applySchemaDefaults(val, validationSchema, schemaVal)
+
+	vv := validator{}
+	vv.validateValue(val, validationSchema, validationLoc{x: repr.value})
+	e.diags.Extend(vv.diags...)
 
 	// Return the value with its schema (pass-through semantics)
 	v.schema = val.schema
@@ -1440,6 +2003,112 @@ func (e *evalContext) valueToSchema(v *value) (*schema.Schema, error) {
 	return &s, nil
 }
 
+// applySchemaDefaults fills in the missing properties of an object value that have defaults in the given schema.
+// Defaults are applied recursively to properties that are themselves objects. If sv is non-nil, it is the value from
+// which the schema was built, and filled values are taken from its `default` properties so that they are traced to
+// the schema's definition. The value is modified in place. Returns true if any defaults were applied.
+func applySchemaDefaults(v *value, s *schema.Schema, sv *value) bool {
+	if v == nil || v.unknown || s == nil {
+		return false
+	}
+	object, ok := v.repr.(map[string]*value)
+	if !ok {
+		return false
+	}
+
+	properties := sv.property(nil, "properties")
+
+	applied := false
+	for _, k := range slices.Sorted(maps.Keys(s.Properties)) {
+		ps, psv := s.Properties[k], properties.property(nil, k)
+
+		pv := v.property(nil, k)
+		switch {
+		case pv == nil:
+			dv, ok := schemaDefault(ps, psv, v.def)
+			if !ok {
+				continue
+			}
+			pv = dv
+		case pv.unknown || !pv.isObject():
+			continue
+		default:
+			if _, own := object[k]; !own {
+				// The property is inherited from the value's base. Layer an empty object over the inherited value to
+				// hold its defaults.
+				pv = &value{def: pv.def, base: pv, schema: pv.schema, repr: map[string]*value{}}
+			}
+			if !applySchemaDefaults(pv, ps, psv) {
+				continue
+			}
+		}
+
+		object[k], applied = pv, true
+		v.schema = mergedSchema(v.schema, schema.Record(schema.SchemaMap{k: pv.schema}).Schema())
+	}
+
+	if applied {
+		v.mergedKeys = nil
+	}
+	return applied
+}
+
+// schemaDefaults returns an object value that holds the defaults of the given schema's properties, or nil if none of
+// its properties have defaults. Defaults are collected recursively from properties that are themselves objects. If sv
+// is non-nil, it is the value from which the schema was built, and defaults are traced to the schema's definition.
+// Otherwise, the defaults are attributed to the given expression.
+func schemaDefaults(s *schema.Schema, sv *value, def *expr) *value {
+	if s == nil {
+		return nil
+	}
+
+	properties := sv.property(nil, "properties")
+
+	object, schemas := map[string]*value{}, schema.SchemaMap{}
+	for _, k := range slices.Sorted(maps.Keys(s.Properties)) {
+		ps, psv := s.Properties[k], properties.property(nil, k)
+
+		pdef := def
+		if psv != nil {
+			pdef = psv.def
+		}
+
+		pv, ok := schemaDefault(ps, psv, pdef)
+		if !ok {
+			if pv = schemaDefaults(ps, psv, pdef); pv == nil {
+				continue
+			}
+		}
+		object[k], schemas[k] = pv, pv.schema
+	}
+	if len(object) == 0 {
+		return nil
+	}
+	return &value{def: def, schema: schema.Record(schemas).Schema(), repr: object}
+}
+
+// schemaDefault returns the default value of the given schema, if any. If sv is non-nil, it is the value from which
+// the schema was built, and the default is taken from its `default` property. Otherwise, the default is attributed to
+// the given expression.
+func schemaDefault(s *schema.Schema, sv *value, def *expr) (*value, bool) {
+	if d := sv.property(nil, "default"); d != nil && !d.unknown {
+		dv := newCopier().copy(d)
+		dv.fromDefault = true
+		return dv, true
+	}
+	if s.Default == nil {
+		return nil, false
+	}
+
+	ev, err := esc.FromJSON(s.Default)
+	if err != nil {
+		return nil, false
+	}
+	dv := unexport(ev, def)
+	dv.fromDefault = true
+	return dv, true
+}
+
 // evaluateBuiltinFinal evaluates a call to the fn::final builtin. The inner value is evaluated
 // normally, and the result is wrapped with the final flag to prevent overrides in child environments.
 func (e *evalContext) evaluateBuiltinFinal(x *expr, repr *finalExpr) *value {
@@ -1468,14 +2137,14 @@ func (e *evalContext) evaluateBuiltinFromJSON(x *expr, repr *fromJSONExpr) *valu
 
 		var jv any
 		if err := dec.Decode(&jv); err != nil {
-			e.errorf(repr.syntax(), "decoding JSON string: %v", err)
+			e.errorf(repr.syntax(), syntax.CodeInvalidJSON, "decoding JSON string: %v", err)
 			v.unknown = true
 			return v
 		}
 
 		ev, err := esc.FromJSON(jv)
 		if err != nil {
-			e.errorf(repr.syntax(), "internal error: decoding JSON value: %v", err)
+			e.errorf(repr.syntax(), syntax.CodeInternalError, "internal error: decoding JSON value: %v", err)
 			v.unknown = true
 			return v
 		}
@@ -1518,7 +2187,7 @@ func (e *evalContext) evaluateBuiltinToJSON(x *expr, repr *toJSONExpr) *value {
 
 		b, err := json.Marshal(valueV.ToJSON(false))
 		if err != nil {
-			e.errorf(repr.syntax(), "failed to encode JSON: %v", err)
+			e.errorf(repr.syntax(), syntax.CodeInvalidJSON, "failed to encode JSON: %v", err)
 			v.unknown = true
 			return v
 		}
@@ -17,6 +17,7 @@ package eval
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -28,11 +29,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/pgavlin/fx/v2"
 	fxm "github.com/pgavlin/fx/v2/maps"
 	fxs "github.com/pgavlin/fx/v2/slices"
 	"github.com/pgavlin/fx/v2/try"
 	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/ast"
 	"github.com/pulumi/esc/schema"
 	"github.com/pulumi/esc/syntax"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -118,6 +121,23 @@ func (testSchemaProvider) Open(ctx context.Context, inputs map[string]esc.Value,
 	return esc.NewValue(inputs), nil
 }
 
+type formatProvider struct{}
+
+func (formatProvider) Schema() (*schema.Schema, *schema.Schema) {
+	outputs := schema.Record(schema.BuilderMap{
+		"endpoint": schema.String().Format("uri"),
+		"id":       schema.String().Format("uuid"),
+	}).Schema()
+	return schema.Always(), outputs
+}
+
+func (formatProvider) Open(ctx context.Context, inputs map[string]esc.Value, context esc.EnvExecContext) (esc.Value, error) {
+	return esc.NewValue(map[string]esc.Value{
+		"endpoint": esc.NewValue("https://api.example.com"),
+		"id":       esc.NewValue("0f8fad5b-d9cb-469f-a165-70867728950e"),
+	}), nil
+}
+
 type benchProvider struct {
 	delay time.Duration
 }
@@ -227,6 +247,8 @@ func (tp testProviders) LoadProvider(ctx context.Context, name string) (esc.Prov
 		return errorProvider{}, nil
 	case "schema":
 		return testSchemaProvider{}, nil
+	case "format":
+		return formatProvider{}, nil
 	case "test":
 		return testProvider{}, nil
 	case "secret-wrapper":
@@ -253,16 +275,23 @@ type testEnvironments struct {
 
 func (e *testEnvironments) LoadEnvironment(ctx context.Context, name string) ([]byte, Decrypter, error) {
 	if e.root == "" {
-		return nil, nil, os.ErrNotExist
+		return nil, nil, fmt.Errorf("%v: %w", name, ErrEnvironmentNotFound)
 	}
 
 	bytes, err := os.ReadFile(filepath.Join(e.root, name+".yaml"))
 	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, fmt.Errorf("%v: %w", name, ErrEnvironmentNotFound)
+		}
 		return nil, nil, err
 	}
 	return bytes, rot128{}, nil
 }
 
+func (e *testEnvironments) LoadEnvironmentVersion(ctx context.Context, name, version string) ([]byte, Decrypter, error) {
+	return e.LoadEnvironment(ctx, name+"@"+version)
+}
+
 type benchEnvironments struct {
 	defs  map[string][]byte
 	delay time.Duration
@@ -328,6 +357,12 @@ func sortEnvironmentDiagnostics(diags syntax.Diagnostics) {
 	})
 }
 
+func sortRotationPlan(plan RotationPlan) {
+	slices.SortFunc(plan, func(a, b *PlannedRotation) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+}
+
 func normalize[T any](t *testing.T, v T) T {
 	var decoded T
 	marshaled, err := json.Marshal(v)
@@ -360,6 +395,8 @@ func TestEval(t *testing.T) {
 		Rotate           *esc.Environment   `json:"rotate,omitempty"`
 		RotateJSON       any                `json:"rotateJson,omitempty"`
 		RotatePatches    []*Patch           `json:"rotatePatches,omitempty"`
+		RotatePlanDiags  syntax.Diagnostics `json:"rotatePlanDiags,omitempty"`
+		RotatePlan       RotationPlan       `json:"rotatePlan,omitempty"`
 	}
 
 	path := filepath.Join("testdata", "eval")
@@ -425,10 +462,18 @@ func TestEval(t *testing.T) {
 				var patches []*Patch
 				var rotateDiags syntax.Diagnostics
 				var rotationResult RotationResult
+				var plan RotationPlan
+				var planDiags syntax.Diagnostics
 				if doRotate {
 					rotated, rotationResult, rotateDiags = RotateEnvironment(context.Background(), environmentName, env, rot128{}, testProviders{},
 						&testEnvironments{basePath}, execContext, rotatePaths)
 					patches = rotationResult.Patches()
+					rotateDiags.Extend(rotationResult.Commit(context.Background())...)
+
+					plan, planDiags = PlanRotateEnvironment(context.Background(), environmentName, env, rot128{}, testProviders{},
+						&testEnvironments{basePath}, execContext, rotatePaths)
+					sortRotationPlan(plan)
+					sortEnvironmentDiagnostics(planDiags)
 				}
 
 				var checkJSON any
@@ -462,6 +507,8 @@ func TestEval(t *testing.T) {
 					Rotate:           rotated,
 					RotateJSON:       rotateJSON,
 					RotatePatches:    patches,
+					RotatePlanDiags:  planDiags,
+					RotatePlan:       plan,
 				}, "", "    ")
 				bytes = append(bytes, '\n')
 				require.NoError(t, err)
@@ -484,16 +531,19 @@ func TestEval(t *testing.T) {
 			require.NoError(t, err)
 			sortEnvironmentDiagnostics(diags)
 			require.Equal(t, expected.LoadDiags, diags)
+			requireCatalogedCodes(t, diags)
 
 			check, diags := CheckEnvironment(context.Background(), environmentName, env, rot128{}, testProviders{},
 				&testEnvironments{basePath}, execContext, showSecrets)
 			sortEnvironmentDiagnostics(diags)
 			require.Equal(t, expected.CheckDiags, diags)
+			requireCatalogedCodes(t, diags)
 
 			actual, diags := EvalEnvironment(context.Background(), environmentName, env, rot128{}, testProviders{},
 				&testEnvironments{basePath}, execContext)
 			sortEnvironmentDiagnostics(diags)
 			require.Equal(t, expected.EvalDiags, diags)
+			requireCatalogedCodes(t, diags)
 
 			var rotated *esc.Environment
 			if doRotate {
@@ -502,10 +552,12 @@ func TestEval(t *testing.T) {
 				var patches []*Patch
 				if rotationResult != nil {
 					patches = rotationResult.Patches()
+					diags.Extend(rotationResult.Commit(context.Background())...)
 				}
 
 				sortEnvironmentDiagnostics(diags)
 				require.Equal(t, expected.RotateDiags, diags)
+				requireCatalogedCodes(t, diags)
 
 				slices.SortFunc(patches, func(a, b *Patch) int {
 					return strings.Compare(a.DocPath, b.DocPath)
@@ -513,6 +565,14 @@ func TestEval(t *testing.T) {
 				require.Equal(t, expected.RotatePatches, patches)
 
 				rotated = rotated_
+
+				plan, diags := PlanRotateEnvironment(context.Background(), environmentName, env, rot128{}, testProviders{},
+					&testEnvironments{basePath}, execContext, rotatePaths)
+				sortRotationPlan(plan)
+				sortEnvironmentDiagnostics(diags)
+				require.Equal(t, expected.RotatePlanDiags, diags)
+				requireCatalogedCodes(t, diags)
+				require.Equal(t, expected.RotatePlan, normalize(t, plan))
 			}
 
 			// work around a schema comparison issue due to the 'compiled' field by roundtripping through JSON
@@ -605,6 +665,14 @@ func BenchmarkEvalAll(b *testing.B) {
 	benchmarkEval(b, 10*time.Millisecond, 10*time.Millisecond)
 }
 
+// requireCatalogedCodes requires that each diagnostic carries a code from the catalog of diagnostic codes.
+func requireCatalogedCodes(t *testing.T, diags syntax.Diagnostics) {
+	for _, d := range diags {
+		_, ok := syntax.LookupCode(d.Code)
+		require.Truef(t, ok, "diagnostic %q has unknown code %q", d.Summary, d.Code)
+	}
+}
+
 // TestSyntaxErrorCheck provides additional insurance that we are able to parse and analyze environments that contain
 // syntax errors. It is important that we are able to provide as much information about an environment as we can so
 // that tools that depend on an environment's typed AST or implied schema needs this fix can operate properly, even on
@@ -638,3 +706,441 @@ func TestSyntaxErrorCheck(t *testing.T) {
 	assert.True(t, checkDiags.HasErrors())
 	assert.NotNil(t, check)
 }
+
+// errorEnvironments is an environment loader that fails to load each environment with the corresponding error.
+type errorEnvironments map[string]error
+
+func (e errorEnvironments) LoadEnvironment(ctx context.Context, name string) ([]byte, Decrypter, error) {
+	return nil, nil, e[name]
+}
+
+func TestOptionalImportErrors(t *testing.T) {
+	env, diags, err := LoadYAMLBytes("optional", []byte(`imports:
+  - missing:
+      optional: true
+  - forbidden:
+      optional: true
+values:
+  foo: bar
+`))
+	require.NoError(t, err)
+	require.Empty(t, diags)
+
+	environments := errorEnvironments{
+		"missing":   fmt.Errorf("missing: %w", ErrEnvironmentNotFound),
+		"forbidden": errors.New("403 Forbidden"),
+	}
+	execContext, err := esc.NewExecContext(nil)
+	require.NoError(t, err)
+
+	_, diags = CheckEnvironment(context.Background(), "optional", env, rot128{}, testProviders{}, environments,
+		execContext, false)
+	require.Len(t, diags, 2)
+
+	// Missing optional imports are skipped with a warning; other errors are reported.
+	assert.Equal(t, hcl.DiagWarning, diags[0].Severity)
+	assert.Equal(t, "skipping optional import of missing: environment not found", diags[0].Summary)
+	assert.Equal(t, hcl.DiagError, diags[1].Severity)
+	assert.Equal(t, "403 Forbidden", diags[1].Summary)
+}
+
+func TestPlanRotateEncryptedState(t *testing.T) {
+	ciphertext, err := rot128{}.Encrypt(context.Background(), []byte("bar1"))
+	require.NoError(t, err)
+
+	env, diags, err := LoadYAMLBytes("plan", []byte(fmt.Sprintf(`values:
+  creds:
+    fn::rotate::swap:
+      inputs: {}
+      state:
+        a:
+          fn::secret:
+            ciphertext: %v
+        b: bar2
+`, base64.StdEncoding.EncodeToString(encodeCiphertext(ciphertext)))))
+	require.NoError(t, err)
+	require.Empty(t, diags)
+
+	execContext, err := esc.NewExecContext(nil)
+	require.NoError(t, err)
+
+	// Planning never decrypts secrets, so a broken decrypter does not block the rotation.
+	plan, diags := PlanRotateEnvironment(context.Background(), "plan", env, broken{}, testProviders{},
+		&testEnvironments{}, execContext, nil)
+	require.Empty(t, diags)
+	require.Len(t, plan, 1)
+	assert.Empty(t, plan[0].Blockers)
+	assert.Equal(t, "values.creds", plan[0].Path)
+}
+
+// TestInvalidJSON ensures that syntax errors in definitions that begin with '{' and are neither valid JSON nor valid
+// YAML are reported as JSON syntax errors with their positions.
+func TestInvalidJSON(t *testing.T) {
+	envBytes := []byte(`{
+  "values": {
+    "foo": "bar"
+    "baz": [1, 2]
+  }
+}`)
+
+	env, diags, err := LoadYAMLBytes("invalid-json", envBytes)
+	require.NoError(t, err)
+	assert.Nil(t, env)
+	require.Len(t, diags, 1)
+	assert.Equal(t, syntax.CodeJSONSyntax, diags[0].Code)
+	assert.Equal(t, "unexpected character '\"' after object value; expected ',' or '}'", diags[0].Summary)
+	assert.Equal(t, 4, diags[0].Subject.Start.Line)
+	assert.Equal(t, 5, diags[0].Subject.Start.Column)
+}
+
+// TestFlowStyleYAML ensures that definitions that begin with '{' but are not JSON are decoded as YAML.
+func TestFlowStyleYAML(t *testing.T) {
+	cases := []string{
+		`{values: {foo: bar}}`,
+		`{"values": {"foo": "bar"}} # note`,
+		`{"values": {"foo": "bar",}}`,
+	}
+	for _, source := range cases {
+		t.Run(source, func(t *testing.T) {
+			env, diags, err := LoadYAMLBytes("flow", []byte(source))
+			require.NoError(t, err)
+			require.Empty(t, diags)
+
+			execContext, err := esc.NewExecContext(nil)
+			require.NoError(t, err)
+
+			checked, diags := CheckEnvironment(context.Background(), "flow", env, rot128{}, testProviders{},
+				&testEnvironments{}, execContext, false)
+			require.Empty(t, diags)
+			assert.Equal(t, "bar", checked.Properties["foo"].Value)
+
+			// Flow-style definitions are rewritten as YAML.
+			encrypted, err := EncryptSecrets(context.Background(), "flow", []byte(source), rot128{})
+			require.NoError(t, err)
+			_, diags, err = LoadYAMLBytes("flow", encrypted)
+			require.NoError(t, err)
+			assert.Empty(t, diags)
+		})
+	}
+}
+
+func TestStrict(t *testing.T) {
+	environmentName := "strict"
+	envBytes := []byte(`values:
+  foo: bar
+values:
+  baz: qux
+`)
+
+	_, loadDiags, err := LoadYAMLBytes(environmentName, envBytes)
+	require.NoError(t, err)
+	require.Len(t, loadDiags, 1)
+	assert.False(t, loadDiags.HasErrors())
+
+	strict := Strict(loadDiags)
+	assert.True(t, strict.HasErrors())
+	assert.Equal(t, syntax.CodeDuplicateKey, strict[0].Code)
+
+	// The original diagnostics are left untouched.
+	assert.False(t, loadDiags.HasErrors())
+}
+
+// txRotator is a transactional rotator that records the outcome of each prepared rotation.
+type txRotator struct {
+	swapRotator
+
+	fail      bool
+	failAbort bool
+	log       *[]string
+}
+
+func (r txRotator) Prepare(ctx context.Context, inputs, state map[string]esc.Value, context esc.EnvExecContext) (esc.Value, error) {
+	if r.fail {
+		return esc.Value{}, errors.New("prepare failed")
+	}
+	*r.log = append(*r.log, "prepare "+state["a"].Value.(string))
+	return r.Rotate(ctx, inputs, state, context)
+}
+
+func (r txRotator) Commit(ctx context.Context, inputs, state, newState map[string]esc.Value, context esc.EnvExecContext) error {
+	*r.log = append(*r.log, "commit "+state["a"].Value.(string))
+	return nil
+}
+
+func (r txRotator) Abort(ctx context.Context, inputs, state, newState map[string]esc.Value, context esc.EnvExecContext) error {
+	*r.log = append(*r.log, "abort "+state["a"].Value.(string))
+	if r.failAbort {
+		return errors.New("abort failed")
+	}
+	return nil
+}
+
+type txProviders map[string]esc.Rotator
+
+func (txProviders) LoadProvider(ctx context.Context, name string) (esc.Provider, error) {
+	return nil, fmt.Errorf("unknown provider %q", name)
+}
+
+func (p txProviders) LoadRotator(ctx context.Context, name string) (esc.Rotator, error) {
+	if r, ok := p[name]; ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("unknown rotator %q", name)
+}
+
+func TestTransactionalRotation(t *testing.T) {
+	rotate := func(t *testing.T, def string, providers txProviders) (RotationResult, syntax.Diagnostics) {
+		env, diags, err := LoadYAMLBytes("tx", []byte(def))
+		require.NoError(t, err)
+		require.Empty(t, diags)
+
+		execContext, err := esc.NewExecContext(nil)
+		require.NoError(t, err)
+
+		_, result, diags := RotateEnvironment(context.Background(), "tx", env, rot128{}, providers, &testEnvironments{}, execContext, nil)
+		slices.SortFunc(result, func(a, b *Rotation) int { return strings.Compare(a.Path, b.Path) })
+		return result, diags
+	}
+
+	const twoRotations = `values:
+  a:
+    fn::rotate::tx:
+      inputs: {}
+      state: {a: a1, b: a2}
+  b:
+    fn::rotate::tx:
+      inputs: {}
+      state: {a: b1, b: b2}
+`
+
+	t.Run("commit", func(t *testing.T) {
+		var log []string
+		result, diags := rotate(t, twoRotations, txProviders{"tx": txRotator{log: &log}})
+		require.Empty(t, diags)
+		require.Len(t, result, 2)
+		for _, r := range result {
+			assert.Equal(t, RotationPrepared, r.Status)
+		}
+		assert.Len(t, result.Patches(), 2)
+
+		diags = result.Commit(context.Background())
+		require.Empty(t, diags)
+		for _, r := range result {
+			assert.Equal(t, RotationSucceeded, r.Status)
+		}
+		assert.ElementsMatch(t, []string{"prepare a1", "prepare b1", "commit a1", "commit b1"}, log)
+
+		// Committing again is a no-op.
+		require.Empty(t, result.Commit(context.Background()))
+		assert.Len(t, log, 4)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		var log []string
+		result, diags := rotate(t, twoRotations, txProviders{"tx": txRotator{log: &log}})
+		require.Empty(t, diags)
+
+		diags = result.Abort(context.Background())
+		require.Empty(t, diags)
+		for _, r := range result {
+			assert.Equal(t, RotationAborted, r.Status)
+		}
+		assert.Empty(t, result.Patches())
+		assert.ElementsMatch(t, []string{"prepare a1", "prepare b1", "abort a1", "abort b1"}, log)
+	})
+
+	t.Run("later failure", func(t *testing.T) {
+		var log []string
+		result, diags := rotate(t, `values:
+  a:
+    fn::rotate::tx:
+      inputs: {}
+      state: {a: a1, b: a2}
+  b:
+    fn::rotate::fail:
+      inputs: {}
+      state: {a: b1, b: b2}
+`, txProviders{"tx": txRotator{log: &log}, "fail": txRotator{fail: true, log: &log}})
+		require.True(t, diags.HasErrors())
+		require.Len(t, result, 2)
+		assert.Equal(t, RotationAborted, result[0].Status)
+		assert.Equal(t, RotationFailed, result[1].Status)
+		assert.Empty(t, result.Patches())
+		assert.Equal(t, []string{"prepare a1", "abort a1"}, log)
+	})
+
+	t.Run("evaluation error", func(t *testing.T) {
+		var log []string
+		result, diags := rotate(t, `values:
+  a:
+    fn::rotate::tx:
+      inputs: {}
+      state: {a: a1, b: a2}
+  b: ${missing}
+`, txProviders{"tx": txRotator{log: &log}})
+		require.True(t, diags.HasErrors())
+		require.Len(t, result, 1)
+		assert.Equal(t, RotationAborted, result[0].Status)
+		assert.Empty(t, result.Patches())
+		assert.Equal(t, []string{"prepare a1", "abort a1"}, log)
+	})
+
+	t.Run("abort failure", func(t *testing.T) {
+		var log []string
+		result, diags := rotate(t, twoRotations, txProviders{"tx": txRotator{failAbort: true, log: &log}})
+		require.Empty(t, diags)
+
+		diags = result.Abort(context.Background())
+		require.Len(t, diags, 2)
+		assert.Equal(t, syntax.CodeRotateFailed, diags[0].Code)
+		for _, r := range result {
+			assert.Equal(t, RotationAbortFailed, r.Status)
+			assert.NotNil(t, r.Patch)
+		}
+	})
+}
+
+func TestValidateInputSchemaAllOf(t *testing.T) {
+	loc := validationLoc{x: &expr{repr: &literalExpr{node: ast.String("x")}}}
+
+	// Neither subschema of x satisfies accept alone, but together they do.
+	x := schema.AllOf(
+		schema.Object().Properties(schema.BuilderMap{"a": schema.String()}).Required("a"),
+		schema.Object().Properties(schema.BuilderMap{"b": schema.Number()}).Required("b"),
+	).Schema()
+	require.NoError(t, x.Compile())
+
+	t.Run("conjunction", func(t *testing.T) {
+		accept := schema.Record(schema.BuilderMap{"a": schema.String(), "b": schema.Number()}).Schema()
+		require.NoError(t, accept.Compile())
+
+		var v validator
+		assert.True(t, v.validateSchemaType(x, accept, loc))
+		assert.Empty(t, v.diags)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		accept := schema.Record(schema.BuilderMap{"a": schema.String(), "c": schema.Boolean()}).Schema()
+		require.NoError(t, accept.Compile())
+
+		var v validator
+		assert.False(t, v.validateSchemaType(x, accept, loc))
+		assert.NotEmpty(t, v.diags)
+	})
+
+	t.Run("conflicting types", func(t *testing.T) {
+		accept := schema.Record(schema.BuilderMap{"a": schema.String(), "b": schema.Number()}).Schema()
+		require.NoError(t, accept.Compile())
+
+		x := schema.AllOf(
+			schema.Object().Properties(schema.BuilderMap{"a": schema.String()}).Required("a"),
+			schema.Object().Properties(schema.BuilderMap{"a": schema.Number(), "b": schema.Number()}).Required("b"),
+		).Schema()
+		require.NoError(t, x.Compile())
+
+		var v validator
+		assert.False(t, v.validateSchemaType(x, accept, loc))
+	})
+}
+
+// connectionProvider is a provider whose outputs are either a connection string or a record of connection details.
+type connectionProvider struct {
+	details schema.BuilderMap
+}
+
+func (p connectionProvider) Schema() (*schema.Schema, *schema.Schema) {
+	return schema.Always().Schema(), schema.OneOf(schema.String(), schema.Record(p.details)).Schema()
+}
+
+func (connectionProvider) Open(ctx context.Context, inputs map[string]esc.Value, context esc.EnvExecContext) (esc.Value, error) {
+	return esc.NewValue("postgres://localhost"), nil
+}
+
+type connectionProviders map[string]esc.Provider
+
+func (p connectionProviders) LoadProvider(ctx context.Context, name string) (esc.Provider, error) {
+	if provider, ok := p[name]; ok {
+		return provider, nil
+	}
+	return nil, fmt.Errorf("unknown provider %q", name)
+}
+
+func (connectionProviders) LoadRotator(ctx context.Context, name string) (esc.Rotator, error) {
+	return nil, fmt.Errorf("unknown rotator %q", name)
+}
+
+// TestDiffCheckedSchemas diffs the schemas that CheckEnvironment infers for successive versions of an environment.
+func TestDiffCheckedSchemas(t *testing.T) {
+	providers := connectionProviders{
+		"v1": connectionProvider{details: schema.BuilderMap{"host": schema.String()}},
+		"v2": connectionProvider{details: schema.BuilderMap{"host": schema.String(), "port": schema.Number()}},
+	}
+
+	check := func(t *testing.T, def string) *schema.Schema {
+		env, diags, err := LoadYAMLBytes("diff", []byte(def))
+		require.NoError(t, err)
+		require.Empty(t, diags)
+
+		execContext, err := esc.NewExecContext(nil)
+		require.NoError(t, err)
+
+		checked, diags := CheckEnvironment(context.Background(), "diff", env, rot128{}, providers,
+			&testEnvironments{}, execContext, false)
+		require.Empty(t, diags)
+		return checked.Schema
+	}
+
+	cases := []struct {
+		name     string
+		base     string
+		compare  string
+		expected schema.Changes
+	}{
+		{
+			name:    "unchanged",
+			base:    "values: {region: us-west-2, tags: [a, b]}",
+			compare: "values: {region: us-east-1, tags: [c, d]}",
+		},
+		{
+			name:    "added and removed",
+			base:    "values: {region: us-west-2, zone: a}",
+			compare: "values: {region: us-west-2, port: 5432}",
+			expected: schema.Changes{
+				{Path: "port", Kind: schema.ChangeRequired, Breaking: true, Description: "required property added"},
+				{Path: "zone", Kind: schema.ChangeRemoved, Breaking: true, Description: "property removed"},
+			},
+		},
+		{
+			name:    "tuples",
+			base:    "values: {tags: [a, b, c]}",
+			compare: "values: {tags: [a, 42]}",
+			expected: schema.Changes{
+				{Path: "tags", Kind: schema.ChangeShrunk, Breaking: true, Description: "array shrunk from 3 to 2 elements"},
+				{Path: "tags[1]", Kind: schema.ChangeRetyped, Breaking: true, Description: "type changed from string to number"},
+			},
+		},
+		{
+			name:    "shrunk",
+			base:    "values: {tags: [a, b, c]}",
+			compare: "values: {tags: [a, b]}",
+			expected: schema.Changes{
+				{Path: "tags", Kind: schema.ChangeShrunk, Breaking: true, Description: "array shrunk from 3 to 2 elements"},
+			},
+		},
+		{
+			name:    "unions",
+			base:    "values: {db: {fn::open::v1: {}}}",
+			compare: "values: {db: {fn::open::v2: {}}}",
+			expected: schema.Changes{
+				{Path: "db.port", Kind: schema.ChangeRequired, Breaking: true, Description: "required property added"},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			changes, err := schema.Diff(check(t, c.base), check(t, c.compare))
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, changes)
+		})
+	}
+}
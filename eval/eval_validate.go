@@ -17,7 +17,9 @@ package eval
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"math/big"
+	"slices"
 	"strings"
 
 	"github.com/pulumi/esc/ast"
@@ -94,19 +96,19 @@ type validator struct {
 	diags syntax.Diagnostics
 }
 
-// errorf issues a validation error at the given location.
-func (e *validator) errorf(loc validationLoc, format string, args ...any) bool {
+// errorf issues a validation error with the given code at the given location.
+func (e *validator) errorf(loc validationLoc, code, format string, args ...any) bool {
 	if loc.prefix {
 		format = fmt.Sprintf("%s: %s", loc.path, format)
 	}
-	diag := ast.ExprError(loc.x.repr.syntax(), fmt.Sprintf(format, args...))
+	diag := ast.ExprError(loc.x.repr.syntax(), fmt.Sprintf(format, args...)).WithCode(code)
 	e.diags.Extend(diag)
 	return false
 }
 
 // constError issues an error associated with an invalid value where a constant is expected.
 func (e *validator) constError(loc validationLoc, expected any) bool {
-	return e.errorf(loc, "expected %v", jsonRepr(expected))
+	return e.errorf(loc, syntax.CodeSchemaConst, "expected %v", jsonRepr(expected))
 }
 
 // enumError issues an error associated with an invalid value where an enum is expected.
@@ -114,12 +116,12 @@ func (e *validator) enumError(loc validationLoc, expected []any) bool {
 	if len(expected) == 1 {
 		return e.constError(loc, expected[0])
 	}
-	return e.errorf(loc, "expected one of %v", jsonRepr(expected))
+	return e.errorf(loc, syntax.CodeSchemaConst, "expected one of %v", jsonRepr(expected))
 }
 
 // typeError issues an error associated with an invalid type.
 func (e *validator) typeError(loc validationLoc, expected, got string) bool {
-	return e.errorf(loc, "expected %s, got %s", expected, got)
+	return e.errorf(loc, syntax.CodeSchemaType, "expected %s, got %s", expected, got)
 }
 
 // isAny returns true if a schema is the Always schema.
@@ -190,23 +192,146 @@ func (e *validator) validateSchemaType(x, accept *schema.Schema, loc validationL
 		return false
 	}
 
+	// A value that satisfies x satisfies x and all of its allOf subschemas at once, so accept must validate their
+	// conjunction rather than any single subschema.
+	if len(x.AllOf) != 0 {
+		return e.validateSchemaType(allOfConjunction(x, nil), accept, loc)
+	}
+
 	refOK := accept.GetRef() == nil || e.validateSchemaType(x, accept.GetRef(), loc)
 	xRefOK := x.GetRef() == nil || e.validateSchemaType(x.GetRef(), accept, loc)
 	xAnyOfOK := e.validateInputSchemaAnyOf(x, accept, loc)
 	xOneOfOK := e.validateInputSchemaOneOf(x, accept, loc)
 	typeOK := x.Type == "" || e.checkType(x.Type, accept, loc)
+	allOfOK := e.validateSchemaAllOf(x, accept, loc)
 	anyOfOK := e.validateSchemaAnyOf(x, accept, loc)
 	oneOfOK := e.validateSchemaOneOf(x, accept, loc)
+	ifOK := e.validateSchemaIf(x, accept, loc)
 
 	complexOK := true
 	switch x.Type {
+	case "string":
+		complexOK = e.validateSchemaString(x, accept, loc)
 	case "array":
 		complexOK = e.validateSchemaArray(x, accept, loc)
 	case "object":
 		complexOK = e.validateSchemaObject(x, accept, loc)
 	}
 
-	return refOK && xRefOK && xAnyOfOK && xOneOfOK && typeOK && anyOfOK && oneOfOK && complexOK
+	return refOK && xRefOK && xAnyOfOK && xOneOfOK && typeOK && allOfOK && anyOfOK && oneOfOK && ifOK &&
+		complexOK
+}
+
+// allOfConjunction returns a schema that is equivalent to x and its allOf subschemas taken together. The returned
+// schema has no allOf directive. Only the keywords that are considered when validating an input schema are combined;
+// see conjunction for details. The seen set guards against cycles through $refs.
+func allOfConjunction(x *schema.Schema, seen map[*schema.Schema]bool) *schema.Schema {
+	if seen == nil {
+		seen = map[*schema.Schema]bool{}
+	}
+
+	c := *x
+	c.AllOf = nil
+	result := &c
+	for _, s := range x.AllOf {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+
+		if len(s.AllOf) != 0 {
+			s = allOfConjunction(s, seen)
+		}
+		result = conjunction(result, s)
+		if ref := s.GetRef(); ref != nil && !seen[ref] {
+			seen[ref] = true
+			if len(ref.AllOf) != 0 {
+				ref = allOfConjunction(ref, seen)
+			}
+			result = conjunction(result, ref)
+		}
+	}
+	return result
+}
+
+// conjunction returns a schema that describes the values described by both a and b. The types, properties, required
+// properties, additional properties, and items of the two schemas are combined. Any other keywords are taken from a,
+// or from b if a does not have them. Properties, additional properties, and items that are present in both schemas
+// are combined lazily using allOf.
+func conjunction(a, b *schema.Schema) *schema.Schema {
+	switch {
+	case a.Never || b.Never:
+		return schema.Never()
+	case a.Always:
+		return b
+	case b.Always:
+		return a
+	}
+
+	c := *a
+	switch {
+	case c.Type == "":
+		c.Type = b.Type
+	case b.Type != "" && b.Type != c.Type:
+		return schema.Never()
+	}
+	if c.Const == nil {
+		c.Const = b.Const
+	}
+	if len(c.Enum) == 0 {
+		c.Enum = b.Enum
+	}
+	if len(c.AnyOf) == 0 {
+		c.AnyOf = b.AnyOf
+	}
+	if len(c.OneOf) == 0 {
+		c.OneOf = b.OneOf
+	}
+
+	if len(b.Properties) != 0 {
+		c.Properties = maps.Clone(a.Properties)
+		if c.Properties == nil {
+			c.Properties = map[string]*schema.Schema{}
+		}
+		for k, bp := range b.Properties {
+			c.Properties[k] = conjunctionOf(c.Properties[k], bp)
+		}
+	}
+	for _, k := range b.Required {
+		if !slices.Contains(c.Required, k) {
+			c.Required = append(slices.Clip(c.Required), k)
+		}
+	}
+	c.AdditionalProperties = conjunctionOf(a.AdditionalProperties, b.AdditionalProperties)
+
+	c.Items = conjunctionOf(a.Items, b.Items)
+	if len(b.PrefixItems) != 0 {
+		c.PrefixItems = make([]*schema.Schema, max(len(a.PrefixItems), len(b.PrefixItems)))
+		for i := range c.PrefixItems {
+			var ai, bi *schema.Schema
+			if i < len(a.PrefixItems) {
+				ai = a.PrefixItems[i]
+			}
+			if i < len(b.PrefixItems) {
+				bi = b.PrefixItems[i]
+			}
+			c.PrefixItems[i] = conjunctionOf(ai, bi)
+		}
+	}
+
+	return &c
+}
+
+// conjunctionOf returns the allOf of a and b. If either schema is nil, the other is returned.
+func conjunctionOf(a, b *schema.Schema) *schema.Schema {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	default:
+		return &schema.Schema{AllOf: []*schema.Schema{a, b}}
+	}
 }
 
 // validateInputSchemaAnyOf checks that accept validates the input schema x if x has an anyOf directive.
@@ -226,7 +351,7 @@ func (e *validator) validateInputSchemaAnyOf(x, accept *schema.Schema, loc valid
 	}
 	if !matched {
 		e.diags.Extend(allDiags...)
-		e.errorf(loc, "at least one subschema must match")
+		e.errorf(loc, syntax.CodeSchemaSubschema, "at least one subschema must match")
 		return false
 	}
 	return true
@@ -249,12 +374,23 @@ func (e *validator) validateInputSchemaOneOf(x, accept *schema.Schema, loc valid
 	}
 	if !matched {
 		e.diags.Extend(allDiags...)
-		e.errorf(loc, "at least one subschema must match")
+		e.errorf(loc, syntax.CodeSchemaSubschema, "at least one subschema must match")
 		return false
 	}
 	return true
 }
 
+// validateSchemaAllOf checks that the allOf schema accept validates the input schema x.
+func (e *validator) validateSchemaAllOf(x, accept *schema.Schema, loc validationLoc) bool {
+	ok := true
+	for _, accept := range accept.AllOf {
+		if !e.validateSchemaType(x, accept, loc) {
+			ok = false
+		}
+	}
+	return ok
+}
+
 // validateSchemaAnyOf checks that the anyOf schema accept validates the input schema x.
 func (e *validator) validateSchemaAnyOf(x, accept *schema.Schema, loc validationLoc) bool {
 	if len(accept.AnyOf) == 0 {
@@ -272,7 +408,7 @@ func (e *validator) validateSchemaAnyOf(x, accept *schema.Schema, loc validation
 	}
 	if !matched {
 		e.diags.Extend(allDiags...)
-		e.errorf(loc, "at least one subschema must match")
+		e.errorf(loc, syntax.CodeSchemaSubschema, "at least one subschema must match")
 		return false
 	}
 	return true
@@ -298,19 +434,69 @@ func (e *validator) validateSchemaOneOf(x, accept *schema.Schema, loc validation
 	}
 	if !matched {
 		e.diags.Extend(allDiags...)
-		e.errorf(loc, "at least one subschema must match")
+		e.errorf(loc, syntax.CodeSchemaSubschema, "at least one subschema must match")
 		return false
 	}
 	return true
 }
 
+// validateSchemaIf checks that the if/then/else schema accept validates the input schema x. Whether or not a value
+// that satisfies x also satisfies accept's If schema is not generally known, so accept validates x if either of its
+// Then or Else schemas validates x. A missing Then or Else schema validates any input.
+//
+// Note that accept's Not schema is not checked against input schemas. It is only enforced for known values.
+func (e *validator) validateSchemaIf(x, accept *schema.Schema, loc validationLoc) bool {
+	if accept.If == nil || accept.Then == nil || accept.Else == nil {
+		return true
+	}
+
+	var allDiags syntax.Diagnostics
+	for _, accept := range []*schema.Schema{accept.Then, accept.Else} {
+		var ee validator
+		if ee.validateSchemaType(x, accept, loc) {
+			return true
+		}
+		allDiags.Extend(ee.diags...)
+	}
+	e.diags.Extend(allDiags...)
+	e.errorf(loc, syntax.CodeSchemaSubschema, "at least one of the then and else subschemas must match")
+	return false
+}
+
+// validateSchemaString checks that the string-typed schema accept validates the string-typed schema x. In order for
+// accept to validate x, if accept has a known Format:
+//
+//   - If x has a string Const, the Const must conform to the Format
+//   - If x has a Format, it must be the same as accept's Format
+//
+// Strings described by schemas with neither a Const nor a Format are only checked once they are known.
+func (e *validator) validateSchemaString(x, accept *schema.Schema, loc validationLoc) bool {
+	f := accept.GetFormat()
+	if f == nil {
+		return true
+	}
+	if c, ok := x.Const.(string); ok {
+		if err := f(c); err != nil {
+			return e.errorf(loc, syntax.CodeSchemaFormat, "expected a string in %q format: %v", accept.Format, err)
+		}
+		return true
+	}
+	if x.Format != "" && x.Format != accept.Format {
+		return e.errorf(loc, syntax.CodeSchemaFormat, "expected a string in %q format, got a string in %q format",
+			accept.Format, x.Format)
+	}
+	return true
+}
+
 // validateSchemaArray checks that the array-typed schema accept validates the array-typed schema x. In order for accept
 // to validate x:
 //
-// - All common PrefixItems in accept must validate the corresponding PrefixItem in x
-// - If accept has more PrefixItems than x, then accept's extra PrefixItems must validate x's Items
-// - If x has more PrefixItems than accept, then accept's Items must validate x's extra PrefixItems
-// - If x's Items is not Never, then accept's Items must validate x's Items
+//   - All common PrefixItems in accept must validate the corresponding PrefixItem in x
+//   - If accept has more PrefixItems than x, then accept's extra PrefixItems must validate x's Items
+//   - If x has more PrefixItems than accept, then accept's Items must validate x's extra PrefixItems
+//   - If x's Items is not Never, then accept's Items must validate x's Items
+//   - If accept has a Contains schema that requires at least one match, then it must validate at least one of x's
+//     PrefixItems or x's Items
 func (e *validator) validateSchemaArray(x, accept *schema.Schema, loc validationLoc) bool {
 	allOk := true
 
@@ -348,6 +534,23 @@ func (e *validator) validateSchemaArray(x, accept *schema.Schema, loc validation
 		ok := e.validateSchemaType(x.Items, accept.Items, loc)
 		allOk = allOk && ok
 	}
+
+	if accept.Contains != nil {
+		if m := accept.GetMinContains(); m == nil || *m != 0 {
+			matched := false
+			for _, xp := range append(x.PrefixItems, x.Items) {
+				var ee validator
+				if xp != nil && !xp.Never && ee.validateSchemaType(xp, accept.Contains, loc) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				e.errorf(loc, syntax.CodeSchemaSubschema, "expected an array with at least one item matching the contains schema")
+				allOk = false
+			}
+		}
+	}
 	return allOk
 }
 
@@ -356,7 +559,10 @@ func (e *validator) validateSchemaArray(x, accept *schema.Schema, loc validation
 //
 // - For each property P with schema S in x:
 //   - If P is also in accept with schema T, then T must validate S
-//   - If P is not in accept, then x's AdditionalProperties must validate S
+//   - If P is not in accept but matches one or more of accept's PatternProperties, then each matching pattern's
+//     schema must validate S
+//   - Otherwise, accept's AdditionalProperties must validate S
+//   - If accept has PropertyNames, then PropertyNames must validate P
 //
 // - If x does not have AdditionalProperties:
 //   - All Required properties in accept must also be Required in x
@@ -371,10 +577,20 @@ func (e *validator) validateSchemaObject(x, accept *schema.Schema, loc validatio
 		if pa, ok := accept.Properties[name]; ok {
 			ok := e.validateSchemaType(px, pa, loc.property(name))
 			allOk = allOk && ok
+		} else if patterns := accept.MatchPatternProperties(name); len(patterns) != 0 {
+			for _, pa := range patterns {
+				ok := e.validateSchemaType(px, pa, loc.property(name))
+				allOk = allOk && ok
+			}
 		} else {
 			ok := e.validateSchemaType(px, accept.AdditionalProperties, loc.property(name))
 			allOk = allOk && ok
 		}
+
+		if accept.PropertyNames != nil {
+			ok := e.validateSchemaType(schema.String().Const(name).Schema(), accept.PropertyNames, loc.property(name))
+			allOk = allOk && ok
+		}
 	}
 
 	if x.AdditionalProperties == nil {
@@ -387,7 +603,7 @@ func (e *validator) validateSchemaObject(x, accept *schema.Schema, loc validatio
 			ok := true
 			for _, name := range ra {
 				if !xreq[name] {
-					e.errorf(loc.property(name), "missing required property")
+					e.errorf(loc.property(name), syntax.CodeSchemaRequired, "missing required property")
 					ok = false
 				}
 			}
@@ -418,7 +634,7 @@ func (e *validator) validateValue(v *value, accept *schema.Schema, loc validatio
 // validateElement checks that accept validates value.
 func (e *validator) validateElement(v *value, accept *schema.Schema, loc validationLoc) bool {
 	if err := accept.Compile(); err != nil {
-		e.errorf(loc, "internal error: invalid schema: %w", err)
+		e.errorf(loc, syntax.CodeInternalError, "internal error: invalid schema: %w", err)
 		return false
 	}
 
@@ -433,12 +649,26 @@ func (e *validator) validateElement(v *value, accept *schema.Schema, loc validat
 	}
 
 	rok := accept.GetRef() == nil || e.validateElement(v, accept.GetRef(), loc)
+	lok := e.validateAllOf(v, accept, loc)
 	aok := e.validateAnyOf(v, accept, loc)
 	ook := e.validateOneOf(v, accept, loc)
+	nok := e.validateNot(v, accept, loc)
+	iok := e.validateIf(v, accept, loc)
 	cok := e.validateConst(v, accept, loc)
 	eok := e.validateEnum(v, accept, loc)
 	tok := e.validateType(v, accept, loc)
-	return rok && aok && ook && cok && eok && tok
+	return rok && lok && aok && ook && nok && iok && cok && eok && tok
+}
+
+// validateAllOf checks that each of the allOf schema accept's subschemas validates value.
+func (e *validator) validateAllOf(v *value, accept *schema.Schema, loc validationLoc) bool {
+	ok := true
+	for _, accept := range accept.AllOf {
+		if !e.validateElement(v, accept, loc) {
+			ok = false
+		}
+	}
+	return ok
 }
 
 // validateAnyOf checks that the anyOf schema accept validates the input schema x.
@@ -458,7 +688,7 @@ func (e *validator) validateAnyOf(v *value, accept *schema.Schema, loc validatio
 	}
 	if !matched {
 		e.diags.Extend(allDiags...)
-		e.errorf(loc, "at least one subschema must match")
+		e.errorf(loc, syntax.CodeSchemaSubschema, "at least one subschema must match")
 		return false
 	}
 	return true
@@ -476,7 +706,7 @@ func (e *validator) validateOneOf(v *value, accept *schema.Schema, loc validatio
 		var ee validator
 		if ee.validateElement(v, accept, loc) {
 			if matched != nil {
-				e.errorf(loc, "exactly one subschema may match")
+				e.errorf(loc, syntax.CodeSchemaSubschema, "exactly one subschema may match")
 				return false
 			}
 			matched = &ee
@@ -485,12 +715,39 @@ func (e *validator) validateOneOf(v *value, accept *schema.Schema, loc validatio
 	}
 	if matched == nil {
 		e.diags.Extend(allDiags...)
-		e.errorf(loc, "exactly one subschema must match")
+		e.errorf(loc, syntax.CodeSchemaSubschema, "exactly one subschema must match")
 		return false
 	}
 	return true
 }
 
+// validateNot checks that accept's Not schema does not validate value.
+func (e *validator) validateNot(v *value, accept *schema.Schema, loc validationLoc) bool {
+	if accept.Not == nil {
+		return true
+	}
+
+	var ee validator
+	if ee.validateElement(v, accept.Not, loc) {
+		return e.errorf(loc, syntax.CodeSchemaSubschema, "subschema must not match")
+	}
+	return true
+}
+
+// validateIf checks that accept's Then schema validates value if accept's If schema validates value, and that
+// accept's Else schema validates value otherwise.
+func (e *validator) validateIf(v *value, accept *schema.Schema, loc validationLoc) bool {
+	if accept.If == nil {
+		return true
+	}
+
+	var ee validator
+	if ee.validateElement(v, accept.If, loc) {
+		return e.validateElement(v, accept.Then, loc)
+	}
+	return e.validateElement(v, accept.Else, loc)
+}
+
 // validateConst checks that accept's Const validates value.
 func (e *validator) validateConst(v *value, accept *schema.Schema, loc validationLoc) bool {
 	if accept.Const == nil || e.equalsConst(v, accept.Const) {
@@ -554,7 +811,7 @@ func (e *validator) validateType(v *value, accept *schema.Schema, loc validation
 func (e *validator) validateNumber(v json.Number, accept *schema.Schema, loc validationLoc) bool {
 	n, _, err := big.ParseFloat(string(v), 10, 0, big.ToNearestEven)
 	if err != nil {
-		e.errorf(loc, "internal error: invalid number %q (%v)", v, err)
+		e.errorf(loc, syntax.CodeInternalError, "internal error: invalid number %q (%v)", v, err)
 		return false
 	}
 
@@ -563,25 +820,25 @@ func (e *validator) validateNumber(v json.Number, accept *schema.Schema, loc val
 		var q big.Float
 		q.Quo(n, m)
 		if !q.IsInt() {
-			e.errorf(loc, "expected a multiple of %v", accept.MultipleOf)
+			e.errorf(loc, syntax.CodeSchemaRange, "expected a multiple of %v", accept.MultipleOf)
 			ok = false
 		}
 	}
 
 	if m := accept.GetMinimum(); m != nil && n.Cmp(m) < 0 {
-		e.errorf(loc, "expected a number greater than or equal to %v", accept.Minimum)
+		e.errorf(loc, syntax.CodeSchemaRange, "expected a number greater than or equal to %v", accept.Minimum)
 		ok = false
 	}
 	if m := accept.GetExclusiveMinimum(); m != nil && n.Cmp(m) <= 0 {
-		e.errorf(loc, "expected a number greater than %v", accept.ExclusiveMinimum)
+		e.errorf(loc, syntax.CodeSchemaRange, "expected a number greater than %v", accept.ExclusiveMinimum)
 		ok = false
 	}
 	if m := accept.GetMaximum(); m != nil && n.Cmp(m) > 0 {
-		e.errorf(loc, "expected a number less than or equal to%v", accept.Maximum)
+		e.errorf(loc, syntax.CodeSchemaRange, "expected a number less than or equal to%v", accept.Maximum)
 		ok = false
 	}
 	if m := accept.GetExclusiveMaximum(); m != nil && n.Cmp(m) >= 0 {
-		e.errorf(loc, "expected a number less than %v", accept.ExclusiveMaximum)
+		e.errorf(loc, syntax.CodeSchemaRange, "expected a number less than %v", accept.ExclusiveMaximum)
 		ok = false
 	}
 	return ok
@@ -591,17 +848,23 @@ func (e *validator) validateNumber(v json.Number, accept *schema.Schema, loc val
 func (e *validator) validateString(v string, accept *schema.Schema, loc validationLoc) bool {
 	ok := true
 	if m := accept.GetMinLength(); m != nil && uint(len(v)) < *m {
-		e.errorf(loc, "expected a string of at least length %v", accept.MinLength)
+		e.errorf(loc, syntax.CodeSchemaLength, "expected a string of at least length %v", accept.MinLength)
 		ok = false
 	}
 	if m := accept.GetMaxLength(); m != nil && uint(len(v)) > *m {
-		e.errorf(loc, "expected a string of at most length %v", accept.MaxLength)
+		e.errorf(loc, syntax.CodeSchemaLength, "expected a string of at most length %v", accept.MaxLength)
 		ok = false
 	}
 	if p := accept.GetPattern(); p != nil && !p.MatchString(v) {
-		e.errorf(loc, "string must match the pattern %q", p.String())
+		e.errorf(loc, syntax.CodeSchemaPattern, "string must match the pattern %q", p.String())
 		ok = false
 	}
+	if f := accept.GetFormat(); f != nil {
+		if err := f(v); err != nil {
+			e.errorf(loc, syntax.CodeSchemaFormat, "expected a string in %q format: %v", accept.Format, err)
+			ok = false
+		}
+	}
 	return ok
 }
 
@@ -609,14 +872,15 @@ func (e *validator) validateString(v string, accept *schema.Schema, loc validati
 func (e *validator) validateArray(v []*value, accept *schema.Schema, loc validationLoc) bool {
 	ok := true
 	if m := accept.GetMinItems(); m != nil && uint(len(v)) < *m {
-		e.errorf(loc, "expected an array with at least %v items", accept.MinItems)
+		e.errorf(loc, syntax.CodeSchemaLength, "expected an array with at least %v items", accept.MinItems)
 		ok = false
 	}
 	if m := accept.GetMaxItems(); m != nil && uint(len(v)) > *m {
-		e.errorf(loc, "expected an array with at most %v items", accept.MaxItems)
+		e.errorf(loc, syntax.CodeSchemaLength, "expected an array with at most %v items", accept.MaxItems)
 		ok = false
 	}
 
+	contains := uint(0)
 	for i, v := range v {
 		vloc := loc.index(i)
 		if i < len(accept.PrefixItems) {
@@ -626,6 +890,28 @@ func (e *validator) validateArray(v []*value, accept *schema.Schema, loc validat
 		} else if !e.validateValue(v, accept.Items, vloc) {
 			ok = false
 		}
+
+		if accept.Contains != nil {
+			var ee validator
+			if ee.validateValue(v, accept.Contains, vloc) {
+				contains++
+			}
+		}
+	}
+
+	if accept.Contains != nil {
+		minContains := uint(1)
+		if m := accept.GetMinContains(); m != nil {
+			minContains = *m
+		}
+		if contains < minContains {
+			e.errorf(loc, syntax.CodeSchemaSubschema, "expected an array with at least %v items matching the contains schema", minContains)
+			ok = false
+		}
+		if m := accept.GetMaxContains(); m != nil && contains > *m {
+			e.errorf(loc, syntax.CodeSchemaSubschema, "expected an array with at most %v items matching the contains schema", accept.MaxContains)
+			ok = false
+		}
 	}
 	return ok
 }
@@ -636,11 +922,11 @@ func (e *validator) validateObject(v *value, accept *schema.Schema, loc validati
 
 	ok := true
 	if m := accept.GetMinProperties(); m != nil && uint(len(keys)) < *m {
-		e.errorf(loc, "expected an object with at least %v properties", accept.MinProperties)
+		e.errorf(loc, syntax.CodeSchemaLength, "expected an object with at least %v properties", accept.MinProperties)
 		ok = false
 	}
 	if m := accept.GetMaxProperties(); m != nil && uint(len(keys)) > *m {
-		e.errorf(loc, "expected an object with at most %v properties", accept.MaxProperties)
+		e.errorf(loc, syntax.CodeSchemaLength, "expected an object with at most %v properties", accept.MaxProperties)
 		ok = false
 	}
 
@@ -651,13 +937,26 @@ func (e *validator) validateObject(v *value, accept *schema.Schema, loc validati
 		kv := v.property(nil, k)
 		vloc := loc.property(k)
 
-		if p, has := accept.Properties[k]; has {
+		p, has := accept.Properties[k]
+		if has && !e.validateValue(kv, p, vloc) {
+			ok = false
+		}
+		patterns := accept.MatchPatternProperties(k)
+		for _, p := range patterns {
 			if !e.validateValue(kv, p, vloc) {
 				ok = false
 			}
-		} else if !e.validateValue(kv, accept.AdditionalProperties, vloc) {
+		}
+		if !has && len(patterns) == 0 && !e.validateValue(kv, accept.AdditionalProperties, vloc) {
 			ok = false
 		}
+
+		if accept.PropertyNames != nil {
+			name := &value{def: kv.def, schema: schema.String().Const(k).Schema(), repr: k}
+			if !e.validateElement(name, accept.PropertyNames, vloc) {
+				ok = false
+			}
+		}
 	}
 
 	var missing []string
@@ -676,7 +975,7 @@ func (e *validator) validateObject(v *value, accept *schema.Schema, loc validati
 		}
 	}
 	if len(missing) != 0 {
-		e.errorf(loc, "missing required properties: %s", strings.Join(missing, ", "))
+		e.errorf(loc, syntax.CodeSchemaRequired, "missing required properties: %s", strings.Join(missing, ", "))
 		ok = false
 	}
 
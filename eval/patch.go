@@ -16,6 +16,7 @@ package eval
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/pulumi/esc"
 	"github.com/pulumi/esc/syntax/encoding"
@@ -31,7 +32,12 @@ type Patch struct {
 
 // ApplyValuePatches applies a set of patches values to an environment definition.
 // If patch values contain secret values, they will be wrapped with fn::secret.
+// JSON definitions are patched and written back as JSON.
 func ApplyValuePatches(source []byte, patches []*Patch) ([]byte, error) {
+	if encoding.IsJSON(source) {
+		return applyJSONValuePatches(source, patches)
+	}
+
 	var doc yaml.Node
 	if err := yaml.Unmarshal(source, &doc); err != nil {
 		return nil, err
@@ -67,6 +73,45 @@ func ApplyValuePatches(source []byte, patches []*Patch) ([]byte, error) {
 	return yaml.Marshal(doc.Content[0])
 }
 
+func applyJSONValuePatches(source []byte, patches []*Patch) ([]byte, error) {
+	doc, diags := encoding.ParseJSON("", source)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	for _, patch := range patches {
+		path, err := resource.ParsePropertyPath(patch.DocPath)
+		if err != nil {
+			return nil, err
+		}
+
+		// convert the esc.Value into a JSON node that can be set on the environment
+		replacement, err := valueToSecretJSON(patch.Replacement)
+		if err != nil {
+			return nil, err
+		}
+		bytes, err := json.Marshal(replacement)
+		if err != nil {
+			return nil, err
+		}
+		jsonValue, diags := encoding.ParseJSON("", bytes)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		_, err = encoding.JSONSyntax{JSONNode: doc}.Set(nil, path, *jsonValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var b strings.Builder
+	if err := encoding.WriteJSON(&b, doc); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
 // valueToSecretJSON converts a Value into a plain-old-JSON value, but secret values are wrapped with fn::secret
 func valueToSecretJSON(v esc.Value) (any, error) {
 	// If this value is secret at the top level, we need to handle it specially
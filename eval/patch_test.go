@@ -60,6 +60,45 @@ values:
 		assert.Empty(t, diags, "patched YAML should parse without errors")
 		assert.NotNil(t, env, "should successfully parse environment")
 	})
+
+	t.Run("json definition", func(t *testing.T) {
+		source := []byte(`{"values": {"mySecret": {"fn::rotate": {"provider": "test", "inputs": {}, "state": null}}}}`)
+
+		patches := []*Patch{
+			{
+				DocPath: "values.mySecret[\"fn::rotate\"].state",
+				Replacement: esc.NewValue(map[string]esc.Value{
+					"password": esc.NewSecret("secret123"),
+					"user":     esc.NewValue("admin"),
+				}),
+			},
+		}
+
+		result, err := ApplyValuePatches(source, patches)
+		require.NoError(t, err)
+		assert.Equal(t, `{
+  "values": {
+    "mySecret": {
+      "fn::rotate": {
+        "provider": "test",
+        "inputs": {},
+        "state": {
+          "password": {
+            "fn::secret": "secret123"
+          },
+          "user": "admin"
+        }
+      }
+    }
+  }
+}
+`, string(result))
+
+		env, diags, err := LoadYAMLBytes("test", result)
+		assert.NoError(t, err)
+		assert.Empty(t, diags, "patched JSON should parse without errors")
+		assert.NotNil(t, env, "should successfully parse environment")
+	})
 }
 
 func TestValueToSecretJSON(t *testing.T) {
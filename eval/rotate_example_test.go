@@ -49,12 +49,19 @@ values:
 	execContext, _ := esc.NewExecContext(nil)
 	_, rotationResult, _ := RotateEnvironment(context.Background(), "<stdin>", env, rot128{}, testProviders{}, &testEnvironments{}, execContext, nil)
 
-	// writeback state patches
-	updated, _ := ApplyValuePatches([]byte(def), rotationResult.Patches())
+	// writeback state patches, aborting any prepared rotations if the patches cannot be applied
+	updated, err := ApplyValuePatches([]byte(def), rotationResult.Patches())
+	if err != nil {
+		rotationResult.Abort(context.Background())
+		return
+	}
 
 	// encrypt secret values
 	encryptedYaml, _ := EncryptSecrets(context.Background(), "<stdin>", updated, rot128{})
 
+	// the new state has been persisted, so commit any prepared rotations
+	rotationResult.Commit(context.Background())
+
 	fmt.Println(string(encryptedYaml))
 	// Output:
 	// values:
@@ -14,7 +14,15 @@
 
 package eval
 
-import "github.com/pulumi/esc/syntax"
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/ast"
+	"github.com/pulumi/esc/schema"
+	"github.com/pulumi/esc/syntax"
+)
 
 type RotationStatus string
 
@@ -22,6 +30,15 @@ const (
 	RotationSucceeded    RotationStatus = "succeeded"
 	RotationFailed       RotationStatus = "failed"
 	RotationNotEvaluated RotationStatus = "not-evaluated"
+
+	// RotationPrepared is the status of a rotation that has been prepared by a transactional rotator but not yet
+	// committed or aborted.
+	RotationPrepared RotationStatus = "prepared"
+	// RotationAborted is the status of a prepared rotation that has been aborted.
+	RotationAborted RotationStatus = "aborted"
+	// RotationAbortFailed is the status of a prepared rotation that could not be aborted. The new secret may still
+	// be live.
+	RotationAbortFailed RotationStatus = "abort-failed"
 )
 
 // A RotationResult stores the result of secret rotations
@@ -33,6 +50,76 @@ type Rotation struct {
 	Status RotationStatus     // status of the rotation
 	Diags  syntax.Diagnostics // diagnostics from the rotation
 	Patch  *Patch             // updated rotation state generated during evaluation, to be written back to the environment definition
+
+	prepared *preparedRotation // the prepared rotation, if the rotation is pending commit or abort
+}
+
+// A preparedRotation records the information needed to commit or abort a rotation prepared by a transactional
+// rotator.
+type preparedRotation struct {
+	rotator     esc.TransactionalRotator
+	node        ast.Expr
+	inputs      map[string]esc.Value
+	state       map[string]esc.Value
+	newState    map[string]esc.Value
+	execContext esc.EnvExecContext
+}
+
+// Failed returns true if any rotation failed.
+func (r RotationResult) Failed() bool {
+	for _, rotation := range r {
+		if rotation.Status == RotationFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// Commit commits each prepared rotation. Callers must commit a rotation result once its patches have been written
+// back to the environment definition. A rotation that fails to commit is marked as failed. Its patch must still be
+// persisted, as the new state has already taken effect.
+func (r RotationResult) Commit(ctx context.Context) syntax.Diagnostics {
+	var diags syntax.Diagnostics
+	for _, rotation := range r {
+		p := rotation.prepared
+		if p == nil {
+			continue
+		}
+		rotation.prepared = nil
+
+		if err := p.rotator.Commit(ctx, p.inputs, p.state, p.newState, p.execContext); err != nil {
+			diag := ast.ExprError(p.node, fmt.Sprintf("commit: %v", err)).WithCode(syntax.CodeRotateFailed)
+			rotation.Status, rotation.Diags = RotationFailed, append(rotation.Diags, diag)
+			diags.Extend(diag)
+			continue
+		}
+		rotation.Status = RotationSucceeded
+	}
+	return diags
+}
+
+// Abort aborts each prepared rotation in the reverse of the order in which the rotations were prepared. Callers
+// must abort a rotation result if its patches cannot be written back to the environment definition. The patches of
+// aborted rotations are discarded.
+func (r RotationResult) Abort(ctx context.Context) syntax.Diagnostics {
+	var diags syntax.Diagnostics
+	for i := len(r) - 1; i >= 0; i-- {
+		rotation := r[i]
+		p := rotation.prepared
+		if p == nil {
+			continue
+		}
+		rotation.prepared = nil
+
+		if err := p.rotator.Abort(ctx, p.inputs, p.state, p.newState, p.execContext); err != nil {
+			diag := ast.ExprError(p.node, fmt.Sprintf("abort: %v", err)).WithCode(syntax.CodeRotateFailed)
+			rotation.Status, rotation.Diags = RotationAbortFailed, append(rotation.Diags, diag)
+			diags.Extend(diag)
+			continue
+		}
+		rotation.Status, rotation.Patch = RotationAborted, nil
+	}
+	return diags
 }
 
 func (r RotationResult) Patches() []*Patch {
@@ -45,3 +132,64 @@ func (r RotationResult) Patches() []*Patch {
 
 	return patches
 }
+
+// A RotationPlan describes the rotations that would be performed by RotateEnvironment.
+type RotationPlan []*PlannedRotation
+
+// A PlannedRotation describes a single rotation that would be performed by RotateEnvironment.
+type PlannedRotation struct {
+	Path     string   // document path where the rotation is defined
+	Rotator  string   // name of the rotator that would be invoked
+	Patch    *Patch   // the patch that would be written back to the environment definition. Values are redacted.
+	Blockers []string // problems that would prevent the rotation, if any
+}
+
+// Blocked returns true if any planned rotation would be blocked.
+func (p RotationPlan) Blocked() bool {
+	for _, rotation := range p {
+		if len(rotation.Blockers) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Patches returns the patches that would be written back to the environment definition.
+func (p RotationPlan) Patches() []*Patch {
+	patches := make([]*Patch, 0, len(p))
+	for _, rotation := range p {
+		patches = append(patches, rotation.Patch)
+	}
+	return patches
+}
+
+// redactedShape returns a value with the shape of a rotator's state in which each leaf is an unknown secret. If the
+// state schema describes the state's properties, the shape is derived from the schema. Otherwise, the shape is
+// derived from the current state.
+func redactedShape(stateSchema *schema.Schema, current esc.Value) esc.Value {
+	if stateSchema != nil && len(stateSchema.Properties) != 0 {
+		props := make(map[string]esc.Value, len(stateSchema.Properties))
+		for k, s := range stateSchema.Properties {
+			currentProps, _ := current.Value.(map[string]esc.Value)
+			props[k] = redactedShape(s, currentProps[k])
+		}
+		return esc.NewValue(props)
+	}
+
+	switch repr := current.Value.(type) {
+	case []esc.Value:
+		elems := make([]esc.Value, len(repr))
+		for i, v := range repr {
+			elems[i] = redactedShape(nil, v)
+		}
+		return esc.NewValue(elems)
+	case map[string]esc.Value:
+		props := make(map[string]esc.Value, len(repr))
+		for k, v := range repr {
+			props[k] = redactedShape(nil, v)
+		}
+		return esc.NewValue(props)
+	default:
+		return esc.Value{Secret: true, Unknown: true}
+	}
+}
@@ -46,10 +46,12 @@ type value struct {
 
 	// true if the value is unknown (e.g. because it did not evaluate successfully or is the result of an unevaluated
 	// fn::open)
-	unknown    bool
-	rotateOnly bool
-	secret     bool // true if the value is secret
-	final      bool // true if the value is final and cannot be overridden by child environments
+	unknown     bool
+	rotateOnly  bool
+	secret      bool // true if the value is secret
+	encrypted   bool // true if the value is an encrypted secret that was not decrypted
+	final       bool // true if the value is final and cannot be overridden by child environments
+	fromDefault bool // true if the value was filled in from a schema default
 
 	repr any // nil | bool | json.Number | string | []*value | map[string]*value
 }
@@ -87,6 +89,32 @@ func (v *value) containsUnknowns() bool {
 	return false
 }
 
+// containsUnknownsExceptCiphertext returns true if the value contains any unknown values other than encrypted secrets
+// that were not decrypted.
+func (v *value) containsUnknownsExceptCiphertext() bool {
+	if v == nil {
+		return false
+	}
+	if v.unknown {
+		return !v.encrypted
+	}
+	switch repr := v.repr.(type) {
+	case []*value:
+		for _, v := range repr {
+			if v.containsUnknownsExceptCiphertext() {
+				return true
+			}
+		}
+	case map[string]*value:
+		for _, k := range v.keys() {
+			if v.property(v.def.repr.syntax(), k).containsUnknownsExceptCiphertext() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // containsObservableUnknowns returns true if the value contains any unknown values.
 // the rotating flag indicate our tolerance of unknown rotateOnly values.
 func (v *value) containsObservableUnknowns(rotating bool) bool {
@@ -328,7 +356,7 @@ func (v *value) export(environment string) (esc.Value, syntax.Diagnostics) {
 		// NOTE: it is always a bug to encounter a value in the process of being exported. The only case in which we
 		// should hit this is if the value chain contains cycles, which should not be possible.
 		return esc.Value{Unknown: true}, syntax.Diagnostics{
-			syntax.NodeError(v.def.repr.syntax().Syntax(), "internal error: cyclic export"),
+			syntax.NodeError(v.def.repr.syntax().Syntax(), "internal error: cyclic export").WithCode(syntax.CodeInternalError),
 		}
 	}
 	v.exporting = true
@@ -371,8 +399,9 @@ func (v *value) export(environment string) (esc.Value, syntax.Diagnostics) {
 		Secret:  v.secret,
 		Unknown: v.unknown,
 		Trace: esc.Trace{
-			Def:  v.def.defRange(environment),
-			Base: base,
+			Def:     v.def.defRange(environment),
+			Base:    base,
+			Default: v.fromDefault,
 		},
 	}
 	return *v.exported, diags
@@ -489,13 +518,14 @@ func (c copier) copy(v *value) *value {
 	}
 
 	*copy = value{
-		def:     v.def,
-		base:    c.copy(v.base),
-		schema:  v.schema,
-		unknown: v.unknown,
-		secret:  v.secret,
-		final:   v.final,
-		repr:    repr,
+		def:         v.def,
+		base:        c.copy(v.base),
+		schema:      v.schema,
+		unknown:     v.unknown,
+		secret:      v.secret,
+		final:       v.final,
+		fromDefault: v.fromDefault,
+		repr:        repr,
 	}
 	return copy
 }
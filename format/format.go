@@ -0,0 +1,260 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package format implements canonical formatting of environment definitions.
+package format
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/esc/ast"
+	"github.com/pulumi/esc/syntax"
+	"github.com/pulumi/esc/syntax/encoding"
+	"gopkg.in/yaml.v3"
+)
+
+// Options controls the behavior of the formatter.
+type Options struct {
+	// SortKeys sorts the keys of each object within the environment's values by name.
+	SortKeys bool
+}
+
+// Source formats an environment definition and returns the formatted source.
+//
+// YAML definitions are written in block style with two-space indentation. Scalars are written without quotes unless
+// quotes are required to preserve their type, and multi-line strings are written as literal blocks. Head, line, and
+// foot comments are preserved. JSON definitions are written as indented JSON.
+//
+// Within the environment's values, long-form calls to fn::open and fn::rotate are rewritten to their short forms,
+// e.g. `fn::open: {provider: aws-login, inputs: {...}}` becomes `fn::open::aws-login: {...}`.
+//
+// Definitions that are empty or that contain only comments are returned unchanged. Definitions that cannot be
+// decoded are not formatted, and the returned diagnostics describe the problem.
+func Source(filename string, source []byte, opts Options) ([]byte, syntax.Diagnostics) {
+	isJSON := encoding.IsJSON(source)
+
+	var root syntax.Node
+	var diags syntax.Diagnostics
+	if isJSON {
+		root, diags = encoding.DecodeJSONBytes(filename, source)
+	} else {
+		root, diags = encoding.DecodeYAMLBytes(filename, source, nil)
+	}
+	if diags.HasErrors() {
+		// Definitions that begin with '{' and are not valid YAML were most likely meant to be JSON.
+		if trimmed := bytes.TrimLeft(source, " \t\r\n"); len(trimmed) != 0 && trimmed[0] == '{' {
+			_, diags = encoding.DecodeJSONBytes(filename, source)
+		}
+		return nil, diags
+	}
+
+	if obj, ok := root.(*syntax.ObjectNode); ok {
+		if obj.Len() == 0 && obj.Syntax() == syntax.NoSyntax {
+			return source, nil
+		}
+		for i := 0; i < obj.Len(); i++ {
+			kvp := obj.Index(i)
+			if kvp.Key.Value() != "values" {
+				continue
+			}
+			values, _, _ := syntax.Walk(kvp.Value, shortenBuiltin)
+			if opts.SortKeys {
+				values = sortKeys(values)
+			}
+			obj.SetIndex(i, syntax.ObjectPropertySyntax(kvp.Syntax, kvp.Key, values))
+		}
+	} else if root == nil {
+		return source, nil
+	}
+
+	var b bytes.Buffer
+	if isJSON {
+		diags = encoding.EncodeJSON(&b, root)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return b.Bytes(), nil
+	}
+
+	yamlNode, diags := encoding.MarshalYAML(root)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	normalize(yamlNode)
+
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode); err != nil {
+		return nil, syntax.Diagnostics{syntax.Error(nil, err.Error(), "").WithCode(syntax.CodeYAMLEncode)}
+	}
+	return b.Bytes(), nil
+}
+
+// normalize clears the flow and quoting styles of a YAML node and its descendants. Strings that must be quoted in
+// order to preserve their type are quoted, and multi-line strings are written as literal blocks.
+func normalize(n *yaml.Node) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) != 0 && n.Tag == "" {
+			n.Tag = "!!str"
+		}
+		style := n.Style & yaml.TaggedStyle
+		if n.Tag == "!!str" {
+			// Quote strings that look like numbers or booleans the same way that encoding.MarshalYAML does.
+			if _, err := strconv.ParseFloat(n.Value, 32); err == nil || n.Value == "true" || n.Value == "false" {
+				style |= yaml.SingleQuotedStyle
+			} else if strings.Contains(n.Value, "\n") {
+				style |= yaml.LiteralStyle
+			}
+		}
+		n.Style = style
+	default:
+		n.Style &^= yaml.FlowStyle
+		for _, c := range n.Content {
+			normalize(c)
+		}
+	}
+}
+
+// sortKeys sorts the keys of each object in the tree rooted at n.
+func sortKeys(n syntax.Node) syntax.Node {
+	switch n := n.(type) {
+	case *syntax.ArrayNode:
+		for i := 0; i < n.Len(); i++ {
+			n.SetIndex(i, sortKeys(n.Index(i)))
+		}
+	case *syntax.ObjectNode:
+		entries := make([]syntax.ObjectPropertyDef, n.Len())
+		for i := range entries {
+			kvp := n.Index(i)
+			entries[i] = syntax.ObjectPropertySyntax(kvp.Syntax, kvp.Key, sortKeys(kvp.Value))
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Key.Value() < entries[j].Key.Value()
+		})
+		return syntax.ObjectSyntax(n.Syntax(), entries...)
+	}
+	return n
+}
+
+// shortenBuiltin rewrites a long-form call to fn::open or fn::rotate to its short form. Calls that have errors or
+// that have arguments other than those understood by the builtin are left as-is. Any comments attached to the
+// provider name are moved to the builtin's name.
+func shortenBuiltin(n syntax.Node) (syntax.Node, syntax.Diagnostics, error) {
+	obj, ok := n.(*syntax.ObjectNode)
+	if !ok || obj.Len() != 1 {
+		return n, nil, nil
+	}
+	call := obj.Index(0)
+	if name := call.Key.Value(); name != "fn::open" && name != "fn::rotate" {
+		return n, nil, nil
+	}
+	args, ok := call.Value.(*syntax.ObjectNode)
+	if !ok {
+		return n, nil, nil
+	}
+
+	var provider *ast.StringExpr
+	var argNames []string
+	expr, diags := ast.ParseExpr(obj)
+	switch expr := expr.(type) {
+	case *ast.OpenExpr:
+		provider, argNames = expr.Provider, []string{"provider", "inputs"}
+	case *ast.RotateExpr:
+		provider, argNames = expr.Provider, []string{"provider", "inputs", "state"}
+	}
+	if provider == nil || provider.Value == "" || len(diags) != 0 {
+		return n, nil, nil
+	}
+
+	// Only rewrite calls whose arguments are all understood by the builtin.
+	seen := map[string]bool{}
+	for i := 0; i < args.Len(); i++ {
+		name := args.Index(i).Key.Value()
+		if seen[name] || !contains(argNames, name) {
+			return n, nil, nil
+		}
+		seen[name] = true
+	}
+
+	var moved []syntax.Syntax
+	var rest []syntax.ObjectPropertyDef
+	var inputs syntax.Node
+	for i := 0; i < args.Len(); i++ {
+		kvp := args.Index(i)
+		switch kvp.Key.Value() {
+		case "provider":
+			moved = append(moved, kvp.Key.Syntax(), kvp.Value.Syntax())
+		case "inputs":
+			inputs = kvp.Value
+			rest = append(rest, kvp)
+		default:
+			rest = append(rest, kvp)
+		}
+	}
+
+	var value syntax.Node
+	if _, ok := expr.(*ast.OpenExpr); ok {
+		// The inputs become the argument to the short form, so comments on the inputs key move to the name.
+		moved = append(moved, rest[0].Key.Syntax())
+		value = inputs
+	} else {
+		value = syntax.ObjectSyntax(args.Syntax(), rest...)
+	}
+
+	name := syntax.StringSyntax(mergeTrivia(call.Key.Syntax(), moved...), call.Key.Value()+"::"+provider.Value)
+	return syntax.ObjectSyntax(obj.Syntax(), syntax.ObjectPropertySyntax(call.Syntax, name, value)), nil, nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// trivia is a syntax.Syntax that carries comments.
+type trivia struct {
+	syntax.Syntax
+
+	head, line, foot string
+}
+
+func (t trivia) HeadComment() string { return t.head }
+func (t trivia) LineComment() string { return t.line }
+func (t trivia) FootComment() string { return t.foot }
+
+// mergeTrivia returns a syntax.Syntax that has the range and path of s and the concatenated comments of s and each of
+// the given syntaxes.
+func mergeTrivia(s syntax.Syntax, others ...syntax.Syntax) syntax.Syntax {
+	var head, line, foot []string
+	for _, o := range append([]syntax.Syntax{s}, others...) {
+		if t, ok := o.(syntax.Trivia); ok {
+			head, line, foot = appendComment(head, t.HeadComment()), appendComment(line, t.LineComment()), appendComment(foot, t.FootComment())
+		}
+	}
+	return trivia{Syntax: s, head: strings.Join(head, "\n"), line: strings.Join(line, " "), foot: strings.Join(foot, "\n")}
+}
+
+func appendComment(comments []string, comment string) []string {
+	if comment == "" {
+		return comments
+	}
+	return append(comments, comment)
+}
@@ -0,0 +1,96 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/esc/syntax"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func accept() bool {
+	return cmdutil.IsTruthy(os.Getenv("PULUMI_ACCEPT"))
+}
+
+func TestSource(t *testing.T) {
+	path := "testdata"
+	entries, err := os.ReadDir(path)
+	require.NoError(t, err)
+	for _, e := range entries {
+		t.Run(e.Name(), func(t *testing.T) {
+			basepath := filepath.Join(path, e.Name())
+
+			ext := ".yaml"
+			if _, err := os.Stat(filepath.Join(basepath, "doc.json")); err == nil {
+				ext = ".json"
+			}
+
+			source, err := os.ReadFile(filepath.Join(basepath, "doc"+ext))
+			require.NoError(t, err)
+
+			for name, opts := range map[string]Options{"formatted": {}, "sorted": {SortKeys: true}} {
+				t.Run(name, func(t *testing.T) {
+					expectedPath := filepath.Join(basepath, name+ext)
+
+					formatted, diags := Source(e.Name(), source, opts)
+					require.Empty(t, diags)
+
+					// Formatting must be idempotent.
+					reformatted, diags := Source(e.Name(), formatted, opts)
+					require.Empty(t, diags)
+					assert.Equal(t, string(formatted), string(reformatted))
+
+					if accept() {
+						err = os.WriteFile(expectedPath, formatted, 0o600)
+						require.NoError(t, err)
+						return
+					}
+
+					expected, err := os.ReadFile(expectedPath)
+					require.NoError(t, err)
+					assert.Equal(t, string(expected), string(formatted))
+				})
+			}
+		})
+	}
+}
+
+func TestSourceInvalid(t *testing.T) {
+	formatted, diags := Source("invalid", []byte("values: [\n"), Options{})
+	assert.Nil(t, formatted)
+	assert.True(t, diags.HasErrors())
+}
+
+func TestSourceFlowYAML(t *testing.T) {
+	formatted, diags := Source("flow", []byte("{values: {foo: bar}}\n"), Options{})
+	assert.Empty(t, diags)
+	assert.Equal(t, "values:\n  foo: bar\n", string(formatted))
+
+	formatted, diags = Source("invalid", []byte(`{"values": {"foo": "bar" "baz": 1}}`), Options{})
+	assert.Nil(t, formatted)
+	require.Len(t, diags, 1)
+	assert.Equal(t, syntax.CodeJSONSyntax, diags[0].Code)
+}
+
+func TestSourceEmpty(t *testing.T) {
+	formatted, diags := Source("empty", nil, Options{})
+	assert.Empty(t, diags)
+	assert.Empty(t, formatted)
+}
@@ -42,3 +42,23 @@ type Rotator interface {
 	// Rotate rotates the provider's secret, and returns the rotator's new state to be persisted.
 	Rotate(ctx context.Context, inputs, state map[string]Value, executionContext EnvExecContext) (Value, error)
 }
+
+// A TransactionalRotator is a Rotator that supports two-phase rotation. When rotating an environment, the evaluator
+// calls Prepare in place of Rotate. Once every rotation in the environment has been prepared and the new state has
+// been persisted, the caller commits each prepared rotation. If any rotation fails or the new state cannot be
+// persisted, each prepared rotation is aborted instead.
+type TransactionalRotator interface {
+	Rotator
+
+	// Prepare creates the rotator's new secret and returns the rotator's new state. The previous secret must remain
+	// valid until the rotation is committed, and the new secret must be revoked if the rotation is aborted.
+	Prepare(ctx context.Context, inputs, state map[string]Value, executionContext EnvExecContext) (Value, error)
+
+	// Commit completes a prepared rotation, e.g. by revoking the previous secret. The state is the state prior to
+	// rotation, and newState is the state returned by Prepare.
+	Commit(ctx context.Context, inputs, state, newState map[string]Value, executionContext EnvExecContext) error
+
+	// Abort undoes a prepared rotation, e.g. by revoking the new secret. The state is the state prior to rotation,
+	// and newState is the state returned by Prepare.
+	Abort(ctx context.Context, inputs, state, newState map[string]Value, executionContext EnvExecContext) error
+}
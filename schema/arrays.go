@@ -47,6 +47,18 @@ func (b *ArrayBuilder) OneOf(oneOf ...Builder) *ArrayBuilder {
 	return buildOneOf(b, oneOf)
 }
 
+func (b *ArrayBuilder) AllOf(allOf ...Builder) *ArrayBuilder {
+	return buildAllOf(b, allOf)
+}
+
+func (b *ArrayBuilder) Not(not Builder) *ArrayBuilder {
+	return buildNot(b, not)
+}
+
+func (b *ArrayBuilder) If(cond, then, els Builder) *ArrayBuilder {
+	return buildIf(b, cond, then, els)
+}
+
 func (b *ArrayBuilder) PrefixItems(prefixItems ...Builder) *ArrayBuilder {
 	b.s.PrefixItems = make([]*Schema, len(prefixItems))
 	for i, e := range prefixItems {
@@ -60,6 +72,21 @@ func (b *ArrayBuilder) Items(items Builder) *ArrayBuilder {
 	return b
 }
 
+func (b *ArrayBuilder) Contains(s Builder) *ArrayBuilder {
+	b.s.Contains = s.Schema()
+	return b
+}
+
+func (b *ArrayBuilder) MinContains(n int) *ArrayBuilder {
+	b.s.MinContains = json.Number(strconv.FormatInt(int64(n), 10))
+	return b
+}
+
+func (b *ArrayBuilder) MaxContains(n int) *ArrayBuilder {
+	b.s.MaxContains = json.Number(strconv.FormatInt(int64(n), 10))
+	return b
+}
+
 func (b *ArrayBuilder) MinItems(n int) *ArrayBuilder {
 	b.s.MinItems = json.Number(strconv.FormatInt(int64(n), 10))
 	return b
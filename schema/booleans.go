@@ -34,6 +34,18 @@ func (b *BooleanBuilder) OneOf(oneOf ...Builder) *BooleanBuilder {
 	return buildOneOf(b, oneOf)
 }
 
+func (b *BooleanBuilder) AllOf(allOf ...Builder) *BooleanBuilder {
+	return buildAllOf(b, allOf)
+}
+
+func (b *BooleanBuilder) Not(not Builder) *BooleanBuilder {
+	return buildNot(b, not)
+}
+
+func (b *BooleanBuilder) If(cond, then, els Builder) *BooleanBuilder {
+	return buildIf(b, cond, then, els)
+}
+
 func (b *BooleanBuilder) Const(v bool) *BooleanBuilder {
 	b.s.Const = v
 	return b
@@ -0,0 +1,427 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/esc/internal/util"
+	"golang.org/x/exp/maps"
+)
+
+// A ChangeKind describes the kind of a change between two schemas.
+type ChangeKind string
+
+const (
+	// ChangeAdded indicates that a property or array element was added.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved indicates that a property was removed.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeRetyped indicates that a value's type was changed to an unrelated type.
+	ChangeRetyped ChangeKind = "retyped"
+	// ChangeNarrowed indicates that a value's type or enumerated values were narrowed.
+	ChangeNarrowed ChangeKind = "narrowed"
+	// ChangeWidened indicates that a value's type or enumerated values were widened.
+	ChangeWidened ChangeKind = "widened"
+	// ChangeRequired indicates that a required property was added or that an existing property became required.
+	ChangeRequired ChangeKind = "required"
+	// ChangeOptional indicates that a property is no longer required.
+	ChangeOptional ChangeKind = "optional"
+	// ChangeSecret indicates that a plaintext value became secret.
+	ChangeSecret ChangeKind = "secret"
+	// ChangePlaintext indicates that a secret value became plaintext.
+	ChangePlaintext ChangeKind = "plaintext"
+	// ChangeShrunk indicates that trailing elements were removed from a fixed-length array.
+	ChangeShrunk ChangeKind = "shrunk"
+)
+
+// Breaking returns true if changes of this kind may break consumers of a schema.
+func (k ChangeKind) Breaking() bool {
+	switch k {
+	case ChangeRemoved, ChangeRetyped, ChangeNarrowed, ChangeRequired, ChangePlaintext, ChangeShrunk:
+		return true
+	default:
+		return false
+	}
+}
+
+// A Change describes a single change between two schemas.
+type Change struct {
+	// Path is the path to the changed value, e.g. `aws.creds[0]`. The path of the root value is empty. The path of
+	// an array's items is suffixed with `[*]`.
+	Path string `json:"path"`
+
+	// Kind is the kind of the change.
+	Kind ChangeKind `json:"kind"`
+
+	// Breaking is true if the change may break consumers of the schema.
+	Breaking bool `json:"breaking"`
+
+	// Description is a human-readable description of the change.
+	Description string `json:"description"`
+}
+
+// Changes is a list of changes between two schemas.
+type Changes []Change
+
+// Breaking returns true if any of the changes are breaking.
+func (c Changes) Breaking() bool {
+	for _, change := range c {
+		if change.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff returns the changes between the base and compare schemas, in depth-first order. Constant values are not
+// compared, as the schemas of environments describe their current values.
+//
+// The following changes are breaking:
+//
+//   - Removing a property
+//   - Changing a value's type, or narrowing its type or enumerated values
+//   - Adding a required property or requiring an existing property
+//   - Changing a secret value to a plaintext value
+//   - Removing trailing elements from a fixed-length array, as consumers may index the removed elements
+//
+// The members of unions are compared with the members of the other union that share their type.
+func Diff(base, compare *Schema) (Changes, error) {
+	if err := base.Compile(); err != nil {
+		return nil, fmt.Errorf("base schema: %w", err)
+	}
+	if err := compare.Compile(); err != nil {
+		return nil, fmt.Errorf("compare schema: %w", err)
+	}
+
+	d := differ{visiting: map[[2]*Schema]bool{}}
+	d.diff("", base, compare)
+	return d.changes, nil
+}
+
+type differ struct {
+	changes  Changes
+	visiting map[[2]*Schema]bool
+}
+
+func (d *differ) change(path string, kind ChangeKind, format string, args ...any) {
+	d.changes = append(d.changes, Change{
+		Path:        path,
+		Kind:        kind,
+		Breaking:    kind.Breaking(),
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) diff(path string, base, compare *Schema) {
+	base, compare = resolveRef(base), resolveRef(compare)
+
+	// Guard against cycles introduced by recursive references.
+	key := [2]*Schema{base, compare}
+	if d.visiting[key] {
+		return
+	}
+	d.visiting[key] = true
+	defer delete(d.visiting, key)
+
+	switch {
+	case base.isSecret() && !compare.isSecret():
+		d.change(path, ChangePlaintext, "value is no longer secret")
+	case !base.isSecret() && compare.isSecret():
+		d.change(path, ChangeSecret, "value is now secret")
+	}
+
+	baseTypes, compareTypes := base.types(), compare.types()
+	switch {
+	case baseTypes == nil && compareTypes == nil:
+		// OK
+	case baseTypes == nil:
+		d.change(path, ChangeNarrowed, "type narrowed from any to %v", formatTypes(compareTypes))
+		return
+	case compareTypes == nil:
+		d.change(path, ChangeWidened, "type widened from %v to any", formatTypes(baseTypes))
+		return
+	case isSubset(baseTypes, compareTypes) && isSubset(compareTypes, baseTypes):
+		// OK
+	case isSubset(compareTypes, baseTypes):
+		d.change(path, ChangeNarrowed, "type narrowed from %v to %v", formatTypes(baseTypes), formatTypes(compareTypes))
+		return
+	case isSubset(baseTypes, compareTypes):
+		d.change(path, ChangeWidened, "type widened from %v to %v", formatTypes(baseTypes), formatTypes(compareTypes))
+		return
+	default:
+		d.change(path, ChangeRetyped, "type changed from %v to %v", formatTypes(baseTypes), formatTypes(compareTypes))
+		return
+	}
+
+	d.diffEnum(path, base, compare)
+
+	if base.isUnion() || compare.isUnion() {
+		d.diffUnion(path, base, compare)
+		return
+	}
+	switch base.Type {
+	case "array":
+		d.diffArray(path, base, compare)
+	case "object":
+		d.diffObject(path, base, compare)
+	}
+}
+
+func (d *differ) diffEnum(path string, base, compare *Schema) {
+	if len(compare.Enum) == 0 {
+		if len(base.Enum) != 0 {
+			d.change(path, ChangeWidened, "values are no longer restricted to enumerated values")
+		}
+		return
+	}
+	if len(base.Enum) == 0 {
+		d.change(path, ChangeNarrowed, "values are now restricted to enumerated values")
+		return
+	}
+
+	baseValues, compareValues := enumSet(base.Enum), enumSet(compare.Enum)
+	if removed := difference(baseValues, compareValues); len(removed) != 0 {
+		d.change(path, ChangeNarrowed, "enumerated values removed: %v", strings.Join(removed, ", "))
+	}
+	if added := difference(compareValues, baseValues); len(added) != 0 {
+		d.change(path, ChangeWidened, "enumerated values added: %v", strings.Join(added, ", "))
+	}
+}
+
+// diffUnion compares the members of two schemas, at least one of which is a union. Members are paired by type in
+// declaration order; members without a single type are not compared.
+func (d *differ) diffUnion(path string, base, compare *Schema) {
+	baseMembers := base.unionMembers(map[string][]*Schema{}, map[*Schema]bool{})
+	compareMembers := compare.unionMembers(map[string][]*Schema{}, map[*Schema]bool{})
+
+	types := maps.Keys(baseMembers)
+	sort.Strings(types)
+	for _, t := range types {
+		baseMembers, compareMembers := baseMembers[t], compareMembers[t]
+		for i := 0; i < len(baseMembers) && i < len(compareMembers); i++ {
+			d.diff(path, baseMembers[i], compareMembers[i])
+		}
+	}
+}
+
+func (d *differ) diffArray(path string, base, compare *Schema) {
+	if compare.Items == nil || compare.Items.Never {
+		if len(compare.PrefixItems) < len(base.PrefixItems) {
+			d.change(path, ChangeShrunk, "array shrunk from %v to %v elements", len(base.PrefixItems), len(compare.PrefixItems))
+		}
+	}
+
+	for i := 0; i < len(base.PrefixItems) || i < len(compare.PrefixItems); i++ {
+		elementPath := fmt.Sprintf("%v[%v]", path, i)
+		switch {
+		case i >= len(compare.PrefixItems):
+			if compare.Items != nil && !compare.Items.Never {
+				d.diff(elementPath, base.PrefixItems[i], compare.Items)
+			}
+		case i >= len(base.PrefixItems):
+			if base.Items == nil || base.Items.Never {
+				d.change(elementPath, ChangeAdded, "element added")
+			} else {
+				d.diff(elementPath, base.Items, compare.PrefixItems[i])
+			}
+		default:
+			d.diff(elementPath, base.PrefixItems[i], compare.PrefixItems[i])
+		}
+	}
+
+	if base.Items != nil && !base.Items.Never && compare.Items != nil && !compare.Items.Never {
+		d.diff(path+"[*]", base.Items, compare.Items)
+	}
+}
+
+func (d *differ) diffObject(path string, base, compare *Schema) {
+	baseRequired, compareRequired := stringSet(base.Required), stringSet(compare.Required)
+
+	names := maps.Keys(base.Properties)
+	for name := range compare.Properties {
+		if _, ok := base.Properties[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertyPath := util.JoinKey(path, name)
+
+		baseProperty, inBase := base.Properties[name]
+		compareProperty, inCompare := compare.Properties[name]
+		switch {
+		case !inCompare:
+			d.change(propertyPath, ChangeRemoved, "property removed")
+		case !inBase:
+			if compareRequired[name] {
+				d.change(propertyPath, ChangeRequired, "required property added")
+			} else {
+				d.change(propertyPath, ChangeAdded, "property added")
+			}
+		default:
+			switch {
+			case !baseRequired[name] && compareRequired[name]:
+				d.change(propertyPath, ChangeRequired, "property is now required")
+			case baseRequired[name] && !compareRequired[name]:
+				d.change(propertyPath, ChangeOptional, "property is no longer required")
+			}
+			d.diff(propertyPath, baseProperty, compareProperty)
+		}
+	}
+}
+
+// resolveRef returns the schema referenced by s, if any.
+func resolveRef(s *Schema) *Schema {
+	for i := 0; s != nil && s.GetRef() != nil && i < 32; i++ {
+		s = s.GetRef()
+	}
+	return s
+}
+
+func (s *Schema) isSecret() bool {
+	return s != nil && s.Secret
+}
+
+func (s *Schema) isUnion() bool {
+	return len(s.AnyOf) != 0 || len(s.OneOf) != 0
+}
+
+// unionMembers adds the members of s to the given map, grouped by type. The members of nested unions are flattened.
+func (s *Schema) unionMembers(members map[string][]*Schema, seen map[*Schema]bool) map[string][]*Schema {
+	s = resolveRef(s)
+	if s == nil || seen[s] {
+		return members
+	}
+	seen[s] = true
+
+	if !s.isUnion() {
+		if s.Type != "" {
+			members[s.Type] = append(members[s.Type], s)
+		}
+		return members
+	}
+	for _, x := range s.AnyOf {
+		members = x.unionMembers(members, seen)
+	}
+	for _, x := range s.OneOf {
+		members = x.unionMembers(members, seen)
+	}
+	return members
+}
+
+// types returns the set of types permitted by a schema. A nil set permits any type.
+func (s *Schema) types() map[string]bool {
+	s = resolveRef(s)
+	switch {
+	case s == nil || s.Always:
+		return nil
+	case s.Never:
+		return map[string]bool{}
+	case s.Type != "":
+		return map[string]bool{s.Type: true}
+	}
+
+	if len(s.AnyOf) != 0 || len(s.OneOf) != 0 {
+		types := map[string]bool{}
+		for _, x := range append(append([]*Schema{}, s.AnyOf...), s.OneOf...) {
+			xtypes := x.types()
+			if xtypes == nil {
+				return nil
+			}
+			for t := range xtypes {
+				types[t] = true
+			}
+		}
+		return types
+	}
+
+	var types map[string]bool
+	for _, x := range s.AllOf {
+		xtypes := x.types()
+		switch {
+		case xtypes == nil:
+			// OK
+		case types == nil:
+			types = xtypes
+		default:
+			for t := range types {
+				if !xtypes[t] {
+					delete(types, t)
+				}
+			}
+		}
+	}
+	return types
+}
+
+func formatTypes(types map[string]bool) string {
+	if len(types) == 0 {
+		return "never"
+	}
+	names := maps.Keys(types)
+	sort.Strings(names)
+	return strings.Join(names, " | ")
+}
+
+func isSubset(a, b map[string]bool) bool {
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+func enumSet(values []any) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[jsonString(v)] = true
+	}
+	return set
+}
+
+// jsonString returns the JSON representation of v.
+func jsonString(v any) string {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(bytes)
+}
+
+// difference returns the sorted elements of a that are not in b.
+func difference(a, b map[string]bool) []string {
+	var diff []string
+	for k := range a {
+		if !b[k] {
+			diff = append(diff, k)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
@@ -0,0 +1,140 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	secret := func(b Builder) *Schema {
+		s := b.Schema()
+		s.Secret = true
+		return s
+	}
+
+	cases := []struct {
+		name     string
+		base     Builder
+		compare  Builder
+		expected Changes
+	}{
+		{
+			name:    "unchanged",
+			base:    Record(BuilderMap{"a": String().Const("x")}),
+			compare: Record(BuilderMap{"a": String().Const("y")}),
+		},
+		{
+			name:    "added and removed",
+			base:    Record(BuilderMap{"a": String(), "b": Number()}),
+			compare: Object().Properties(BuilderMap{"a": String(), "c": Boolean()}).Required("a"),
+			expected: Changes{
+				{Path: "b", Kind: ChangeRemoved, Breaking: true, Description: "property removed"},
+				{Path: "c", Kind: ChangeAdded, Description: "property added"},
+			},
+		},
+		{
+			name:    "required added",
+			base:    Record(BuilderMap{"a": String()}),
+			compare: Record(BuilderMap{"a": String(), "b": String()}),
+			expected: Changes{
+				{Path: "b", Kind: ChangeRequired, Breaking: true, Description: "required property added"},
+			},
+		},
+		{
+			name:    "retyped",
+			base:    Record(BuilderMap{"port": String()}),
+			compare: Record(BuilderMap{"port": Number()}),
+			expected: Changes{
+				{Path: "port", Kind: ChangeRetyped, Breaking: true, Description: "type changed from string to number"},
+			},
+		},
+		{
+			name:    "narrowed and widened",
+			base:    Record(BuilderMap{"a": AnyOf(String(), Number()), "b": String()}),
+			compare: Record(BuilderMap{"a": String(), "b": OneOf(String(), Null())}),
+			expected: Changes{
+				{Path: "a", Kind: ChangeNarrowed, Breaking: true, Description: "type narrowed from number | string to string"},
+				{Path: "b", Kind: ChangeWidened, Description: "type widened from string to null | string"},
+			},
+		},
+		{
+			name:    "required",
+			base:    Object().Properties(BuilderMap{"a": String(), "b": String()}).Required("b"),
+			compare: Object().Properties(BuilderMap{"a": String(), "b": String()}).Required("a"),
+			expected: Changes{
+				{Path: "a", Kind: ChangeRequired, Breaking: true, Description: "property is now required"},
+				{Path: "b", Kind: ChangeOptional, Description: "property is no longer required"},
+			},
+		},
+		{
+			name:    "secret",
+			base:    Record(BuilderMap{"a": secret(String()), "b": String()}),
+			compare: Record(BuilderMap{"a": String(), "b": secret(String())}),
+			expected: Changes{
+				{Path: "a", Kind: ChangePlaintext, Breaking: true, Description: "value is no longer secret"},
+				{Path: "b", Kind: ChangeSecret, Description: "value is now secret"},
+			},
+		},
+		{
+			name:    "enum",
+			base:    String().Enum("a", "b"),
+			compare: String().Enum("b", "c"),
+			expected: Changes{
+				{Kind: ChangeNarrowed, Breaking: true, Description: `enumerated values removed: "a"`},
+				{Kind: ChangeWidened, Description: `enumerated values added: "c"`},
+			},
+		},
+		{
+			name:    "arrays",
+			base:    Record(BuilderMap{"tuple": Tuple(String(), Number()), "list": Array().Items(String())}),
+			compare: Record(BuilderMap{"tuple": Tuple(String()), "list": Array().Items(Number())}),
+			expected: Changes{
+				{Path: "list[*]", Kind: ChangeRetyped, Breaking: true, Description: "type changed from string to number"},
+				{Path: "tuple", Kind: ChangeShrunk, Breaking: true, Description: "array shrunk from 2 to 1 elements"},
+			},
+		},
+		{
+			name:    "unions",
+			base:    Record(BuilderMap{"a": AnyOf(String(), Record(BuilderMap{"x": String()}))}),
+			compare: Record(BuilderMap{"a": OneOf(Record(BuilderMap{"x": Number()}), String())}),
+			expected: Changes{
+				{Path: "a.x", Kind: ChangeRetyped, Breaking: true, Description: "type changed from string to number"},
+			},
+		},
+		{
+			name: "nested refs",
+			base: Object().
+				Defs(BuilderMap{"creds": Record(BuilderMap{"key": String(), "secret": String()})}).
+				Properties(BuilderMap{"creds": Ref("#/$defs/creds")}),
+			compare: Object().
+				Properties(BuilderMap{"creds": Record(BuilderMap{"key": String()})}),
+			expected: Changes{
+				{Path: "creds.secret", Kind: ChangeRemoved, Breaking: true, Description: "property removed"},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			changes, err := Diff(c.base.Schema(), c.compare.Schema())
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, changes)
+			assert.Equal(t, len(c.expected) != 0 && c.expected[0].Breaking, changes.Breaking())
+		})
+	}
+}
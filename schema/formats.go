@@ -0,0 +1,118 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// A FormatChecker checks that a string conforms to a format.
+type FormatChecker func(s string) error
+
+var formats = map[string]FormatChecker{
+	"date-time": checkDateTime,
+	"duration":  checkDuration,
+	"email":     checkEmail,
+	"hostname":  checkHostname,
+	"ipv4":      checkIPv4,
+	"ipv6":      checkIPv6,
+	"uri":       checkURI,
+	"uuid":      checkUUID,
+}
+
+// LookupFormat returns the checker for the named format. Unknown formats are treated as annotations and have no
+// checker.
+func LookupFormat(name string) (FormatChecker, bool) {
+	f, ok := formats[name]
+	return f, ok
+}
+
+func checkDateTime(s string) error {
+	if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+		return errors.New("not an RFC 3339 date-time")
+	}
+	return nil
+}
+
+var durationRegexp = regexp.MustCompile(`^P(?:\d+W|(?:\d+Y)?(?:\d+M)?(?:\d+D)?(?:T(?:\d+H)?(?:\d+M)?(?:\d+(?:\.\d+)?S)?)?)$`)
+
+func checkDuration(s string) error {
+	if !durationRegexp.MatchString(s) || s == "P" || strings.HasSuffix(s, "T") {
+		return errors.New("not an ISO 8601 duration")
+	}
+	return nil
+}
+
+func checkEmail(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Name != "" || addr.Address != s {
+		return errors.New("not an email address")
+	}
+	return nil
+}
+
+var hostnameLabelRegexp = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+func checkHostname(s string) error {
+	if len(s) == 0 || len(s) > 253 {
+		return errors.New("not a hostname")
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(s, "."), ".") {
+		if !hostnameLabelRegexp.MatchString(label) {
+			return fmt.Errorf("not a hostname: invalid label %q", label)
+		}
+	}
+	return nil
+}
+
+func checkIPv4(s string) error {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || !addr.Is4() {
+		return errors.New("not an IPv4 address")
+	}
+	return nil
+}
+
+func checkIPv6(s string) error {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || !addr.Is6() || addr.Zone() != "" {
+		return errors.New("not an IPv6 address")
+	}
+	return nil
+}
+
+func checkURI(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || !u.IsAbs() {
+		return errors.New("not an absolute URI")
+	}
+	return nil
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func checkUUID(s string) error {
+	if !uuidRegexp.MatchString(s) {
+		return errors.New("not a UUID")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormats(t *testing.T) {
+	cases := []struct {
+		format  string
+		valid   []string
+		invalid []string
+	}{
+		{
+			format:  "date-time",
+			valid:   []string{"2024-01-02T03:04:05Z", "2024-01-02T03:04:05.123+01:00"},
+			invalid: []string{"2024-01-02", "03:04:05", "2024-01-02 03:04:05Z"},
+		},
+		{
+			format:  "duration",
+			valid:   []string{"P1D", "PT1H30M", "P1Y2M3DT4H5M6.5S", "P2W"},
+			invalid: []string{"P", "PT", "P1DT", "1d", "P1W2D"},
+		},
+		{
+			format:  "email",
+			valid:   []string{"ops@example.com", "first.last+tag@sub.example.org"},
+			invalid: []string{"ops", "Ops <ops@example.com>", "@example.com"},
+		},
+		{
+			format:  "hostname",
+			valid:   []string{"localhost", "db-1.internal.example.com", "example.com."},
+			invalid: []string{"", "-bad.example.com", "bad_host", "a..b"},
+		},
+		{
+			format:  "ipv4",
+			valid:   []string{"10.0.0.1", "255.255.255.255"},
+			invalid: []string{"10.0.0", "256.0.0.1", "::1"},
+		},
+		{
+			format:  "ipv6",
+			valid:   []string{"::1", "2001:db8::1"},
+			invalid: []string{"10.0.0.1", "fe80::1%eth0", "2001:db8:::1"},
+		},
+		{
+			format:  "uri",
+			valid:   []string{"https://example.com/path?q=1", "urn:isbn:0451450523"},
+			invalid: []string{"/relative/path", "example.com", "://missing-scheme"},
+		},
+		{
+			format:  "uuid",
+			valid:   []string{"0f8fad5b-d9cb-469f-a165-70867728950e", "0F8FAD5B-D9CB-469F-A165-70867728950E"},
+			invalid: []string{"0f8fad5bd9cb469fa16570867728950e", "not-a-uuid"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.format, func(t *testing.T) {
+			check, ok := LookupFormat(c.format)
+			require.True(t, ok)
+
+			for _, v := range c.valid {
+				assert.NoError(t, check(v), v)
+			}
+			for _, v := range c.invalid {
+				assert.Error(t, check(v), v)
+			}
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		_, ok := LookupFormat("custom")
+		assert.False(t, ok)
+
+		s := String().Format("custom").Schema()
+		require.NoError(t, s.Compile())
+		assert.Nil(t, s.GetFormat())
+	})
+}
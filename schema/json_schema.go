@@ -34,18 +34,29 @@ func JSONSchemaSchema() *Schema {
 
 							// Applicator vocabulary
 							"$ref":  {Type: "string"},
+							"allOf": {Type: "array", Items: &Schema{Ref: "#/$defs/schema"}},
 							"anyOf": {Type: "array", Items: &Schema{Ref: "#/$defs/schema"}},
 							"oneOf": {Type: "array", Items: &Schema{Ref: "#/$defs/schema"}},
+							"not":   {Ref: "#/$defs/schema"},
+							"if":    {Ref: "#/$defs/schema"},
+							"then":  {Ref: "#/$defs/schema"},
+							"else":  {Ref: "#/$defs/schema"},
 							"prefixItems": {
 								Type:  "array",
 								Items: &Schema{Ref: "#/$defs/schema"},
 							},
 							"items":                {Ref: "#/$defs/schema"},
+							"contains":             {Ref: "#/$defs/schema"},
 							"additionalProperties": {Ref: "#/$defs/schema"},
 							"properties": {
 								Type:                 "object",
 								AdditionalProperties: &Schema{Ref: "#/$defs/schema"},
 							},
+							"patternProperties": {
+								Type:                 "object",
+								AdditionalProperties: &Schema{Ref: "#/$defs/schema"},
+							},
+							"propertyNames": {Ref: "#/$defs/schema"},
 
 							// Validation vocabulary
 							"type": {
@@ -62,9 +73,12 @@ func JSONSchemaSchema() *Schema {
 							"maxLength":        {Type: "number"},
 							"minLength":        {Type: "number"},
 							"pattern":          {Type: "string"},
+							"format":           {Type: "string"},
 							"maxItems":         {Type: "number"},
 							"minItems":         {Type: "number"},
 							"uniqueItems":      {Type: "boolean"},
+							"maxContains":      {Type: "number"},
+							"minContains":      {Type: "number"},
 							"maxProperties":    {Type: "number"},
 							"minProperties":    {Type: "number"},
 							"required": {
@@ -34,6 +34,18 @@ func (b *NullBuilder) OneOf(oneOf ...Builder) *NullBuilder {
 	return buildOneOf(b, oneOf)
 }
 
+func (b *NullBuilder) AllOf(allOf ...Builder) *NullBuilder {
+	return buildAllOf(b, allOf)
+}
+
+func (b *NullBuilder) Not(not Builder) *NullBuilder {
+	return buildNot(b, not)
+}
+
+func (b *NullBuilder) If(cond, then, els Builder) *NullBuilder {
+	return buildIf(b, cond, then, els)
+}
+
 func (b *NullBuilder) Title(title string) *NullBuilder {
 	b.s.Title = title
 	return b
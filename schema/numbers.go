@@ -36,6 +36,18 @@ func (b *NumberBuilder) OneOf(oneOf ...Builder) *NumberBuilder {
 	return buildOneOf(b, oneOf)
 }
 
+func (b *NumberBuilder) AllOf(allOf ...Builder) *NumberBuilder {
+	return buildAllOf(b, allOf)
+}
+
+func (b *NumberBuilder) Not(not Builder) *NumberBuilder {
+	return buildNot(b, not)
+}
+
+func (b *NumberBuilder) If(cond, then, els Builder) *NumberBuilder {
+	return buildIf(b, cond, then, els)
+}
+
 func (b *NumberBuilder) Const(n json.Number) *NumberBuilder {
 	b.s.Const = n
 	return b
@@ -55,11 +55,33 @@ func (b *ObjectBuilder) OneOf(oneOf ...Builder) *ObjectBuilder {
 	return buildOneOf(b, oneOf)
 }
 
+func (b *ObjectBuilder) AllOf(allOf ...Builder) *ObjectBuilder {
+	return buildAllOf(b, allOf)
+}
+
+func (b *ObjectBuilder) Not(not Builder) *ObjectBuilder {
+	return buildNot(b, not)
+}
+
+func (b *ObjectBuilder) If(cond, then, els Builder) *ObjectBuilder {
+	return buildIf(b, cond, then, els)
+}
+
 func (b *ObjectBuilder) Properties(m MapBuilder) *ObjectBuilder {
 	b.s.Properties = m.Build()
 	return b
 }
 
+func (b *ObjectBuilder) PatternProperties(m MapBuilder) *ObjectBuilder {
+	b.s.PatternProperties = m.Build()
+	return b
+}
+
+func (b *ObjectBuilder) PropertyNames(s Builder) *ObjectBuilder {
+	b.s.PropertyNames = s.Schema()
+	return b
+}
+
 func (b *ObjectBuilder) AdditionalProperties(s Builder) *ObjectBuilder {
 	b.s.AdditionalProperties = s.Schema()
 	return b
@@ -77,6 +77,21 @@ func OneOf(oneOf ...Builder) *Schema {
 	return buildOneOf(s, oneOf)
 }
 
+func AllOf(allOf ...Builder) *Schema {
+	s := &Schema{}
+	return buildAllOf(s, allOf)
+}
+
+func Not(not Builder) *Schema {
+	s := &Schema{}
+	return buildNot(s, not)
+}
+
+func If(cond, then, els Builder) *Schema {
+	s := &Schema{}
+	return buildIf(s, cond, then, els)
+}
+
 type Schema struct {
 	// Core vocabulary
 
@@ -88,12 +103,20 @@ type Schema struct {
 	// Applicator vocabulary
 
 	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
 	AnyOf                []*Schema          `json:"anyOf,omitempty"`
 	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Not                  *Schema            `json:"not,omitempty"`
+	If                   *Schema            `json:"if,omitempty"`
+	Then                 *Schema            `json:"then,omitempty"`
+	Else                 *Schema            `json:"else,omitempty"`
 	PrefixItems          []*Schema          `json:"prefixItems,omitempty"`
 	Items                *Schema            `json:"items,omitempty"`
+	Contains             *Schema            `json:"contains,omitempty"`
 	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
 	Properties           map[string]*Schema `json:"properties,omitempty"`
+	PatternProperties    map[string]*Schema `json:"patternProperties,omitempty"`
+	PropertyNames        *Schema            `json:"propertyNames,omitempty"`
 
 	// Validation vocabulary
 
@@ -108,9 +131,12 @@ type Schema struct {
 	MaxLength         json.Number         `json:"maxLength,omitempty"`
 	MinLength         json.Number         `json:"minLength,omitempty"`
 	Pattern           string              `json:"pattern,omitempty"`
+	Format            string              `json:"format,omitempty"`
 	MaxItems          json.Number         `json:"maxItems,omitempty"`
 	MinItems          json.Number         `json:"minItems,omitempty"`
 	UniqueItems       bool                `json:"uniqueItems,omitempty"`
+	MaxContains       json.Number         `json:"maxContains,omitempty"`
+	MinContains       json.Number         `json:"minContains,omitempty"`
 	MaxProperties     json.Number         `json:"maxProperties,omitempty"`
 	MinProperties     json.Number         `json:"minProperties,omitempty"`
 	Required          []string            `json:"required,omitempty"`
@@ -137,15 +163,26 @@ type Schema struct {
 	maxLength        *uint
 	minLength        *uint
 	pattern          *regexp.Regexp
+	format           FormatChecker
 	maxItems         *uint
 	minItems         *uint
+	maxContains      *uint
+	minContains      *uint
 	maxProperties    *uint
 	minProperties    *uint
 	rotateOnly       bool
 
+	patternProperties []patternProperty
+
 	compiled bool
 }
 
+// A patternProperty is a compiled entry in a schema's PatternProperties.
+type patternProperty struct {
+	pattern *regexp.Regexp
+	schema  *Schema
+}
+
 func (s *Schema) UnmarshalJSON(data []byte) error {
 	var b bool
 	if err := json.Unmarshal(data, &b); err == nil {
@@ -208,6 +245,9 @@ func (s *Schema) objectProperty(name string) *Schema {
 	if p, ok := s.Properties[name]; ok {
 		return p
 	}
+	if patterns := s.MatchPatternProperties(name); len(patterns) != 0 {
+		return intersection(patterns)
+	}
 	return s.AdditionalProperties
 }
 
@@ -220,7 +260,42 @@ func (s *Schema) Property(name string) *Schema {
 		oneOf = append(oneOf, x.Property(name))
 	}
 	oneOf = append(oneOf, s.objectProperty(name))
-	return union(oneOf)
+	if len(s.AllOf) == 0 {
+		return union(oneOf)
+	}
+
+	// The property must satisfy each of the schemas in AllOf. If the schema only consists of an AllOf, the property's
+	// schema is the intersection of the AllOf schemas' properties.
+	var allOf []*Schema
+	if s.Type != "" || len(s.AnyOf) != 0 || len(s.OneOf) != 0 {
+		allOf = append(allOf, union(oneOf))
+	}
+	for _, x := range s.AllOf {
+		allOf = append(allOf, x.Property(name))
+	}
+	return intersection(allOf)
+}
+
+// MatchPatternProperties returns the schemas of the entries in PatternProperties whose patterns match the given
+// property name. The schemas are returned in the order of their patterns.
+func (s *Schema) MatchPatternProperties(name string) []*Schema {
+	if len(s.PatternProperties) == 0 {
+		return nil
+	}
+
+	patterns := s.patternProperties
+	if patterns == nil {
+		// The schema has not been compiled. Compile its patterns on demand and skip any that are invalid.
+		patterns, _ = compilePatternProperties(s.PatternProperties, false)
+	}
+
+	var matches []*Schema
+	for _, p := range patterns {
+		if p.pattern.MatchString(name) {
+			matches = append(matches, p.schema)
+		}
+	}
+	return matches
 }
 
 func (s *Schema) IsRotateOnly() bool {
@@ -236,8 +311,11 @@ func (s *Schema) GetExclusiveMinimum() *big.Float { return s.exclusiveMinimum }
 func (s *Schema) GetMaxLength() *uint             { return s.maxLength }
 func (s *Schema) GetMinLength() *uint             { return s.minLength }
 func (s *Schema) GetPattern() *regexp.Regexp      { return s.pattern }
+func (s *Schema) GetFormat() FormatChecker        { return s.format }
 func (s *Schema) GetMaxItems() *uint              { return s.maxItems }
 func (s *Schema) GetMinItems() *uint              { return s.minItems }
+func (s *Schema) GetMaxContains() *uint           { return s.maxContains }
+func (s *Schema) GetMinContains() *uint           { return s.minContains }
 func (s *Schema) GetMaxProperties() *uint         { return s.maxProperties }
 func (s *Schema) GetMinProperties() *uint         { return s.minProperties }
 
@@ -259,12 +337,22 @@ func setRotateOnly(s *Schema) *Schema {
 	copy.rotateOnly = true
 
 	copy.ref = setRotateOnly(s.ref)
+	copy.AllOf = slices.Collect(fxs.Map(s.AllOf, setRotateOnly))
 	copy.AnyOf = slices.Collect(fxs.Map(s.AnyOf, setRotateOnly))
 	copy.OneOf = slices.Collect(fxs.Map(s.OneOf, setRotateOnly))
+	copy.Not = setRotateOnly(s.Not)
+	copy.If = setRotateOnly(s.If)
+	copy.Then = setRotateOnly(s.Then)
+	copy.Else = setRotateOnly(s.Else)
 	copy.PrefixItems = slices.Collect(fxs.Map(s.PrefixItems, setRotateOnly))
 	copy.Items = setRotateOnly(s.Items)
+	copy.Contains = setRotateOnly(s.Contains)
 	copy.AdditionalProperties = setRotateOnly(s.AdditionalProperties)
 	copy.Properties = maps.Collect(fxm.Map(s.Properties, func(k string, s *Schema) (string, *Schema) { return k, setRotateOnly(s) }))
+	copy.PatternProperties = maps.Collect(fxm.Map(s.PatternProperties, func(k string, s *Schema) (string, *Schema) { return k, setRotateOnly(s) }))
+	copy.patternProperties = slices.Collect(fxs.Map(s.patternProperties, func(p patternProperty) patternProperty {
+		return patternProperty{pattern: p.pattern, schema: setRotateOnly(p.schema)}
+	}))
 	copy.RotateOnly = slices.Collect(maps.Keys(s.Properties))
 
 	return &copy
@@ -286,6 +374,11 @@ func (s *Schema) compile(root *Schema) error {
 		}
 	}
 
+	for _, s := range s.AllOf {
+		if err := s.compile(root); err != nil {
+			return err
+		}
+	}
 	for _, s := range s.AnyOf {
 		if err := s.compile(root); err != nil {
 			return err
@@ -296,6 +389,11 @@ func (s *Schema) compile(root *Schema) error {
 			return err
 		}
 	}
+	for _, s := range []*Schema{s.Not, s.If, s.Then, s.Else, s.Contains, s.PropertyNames} {
+		if err := s.compile(root); err != nil {
+			return err
+		}
+	}
 
 	for _, s := range s.PrefixItems {
 		if err := s.compile(root); err != nil {
@@ -313,6 +411,14 @@ func (s *Schema) compile(root *Schema) error {
 			return err
 		}
 	}
+	for _, v := range s.PatternProperties {
+		if err := v.compile(root); err != nil {
+			return err
+		}
+	}
+	if s.patternProperties, err = compilePatternProperties(s.PatternProperties, true); err != nil {
+		return err
+	}
 	for _, name := range s.RotateOnly {
 		// need to push the rotateOnly flag down onto the actual properties, so it is available to the evaluator while evaluating object properties
 		if p, ok := s.Properties[name]; ok {
@@ -344,12 +450,19 @@ func (s *Schema) compile(root *Schema) error {
 	if s.pattern, err = parseRegexp(s.Pattern); err != nil {
 		return err
 	}
+	s.format, _ = LookupFormat(s.Format)
 	if s.maxItems, err = parseUint(s.MaxItems); err != nil {
 		return err
 	}
 	if s.minItems, err = parseUint(s.MinItems); err != nil {
 		return err
 	}
+	if s.maxContains, err = parseUint(s.MaxContains); err != nil {
+		return err
+	}
+	if s.minContains, err = parseUint(s.MinContains); err != nil {
+		return err
+	}
 	if s.maxProperties, err = parseUint(s.MaxProperties); err != nil {
 		return err
 	}
@@ -398,6 +511,23 @@ func parseUint(n json.Number) (*uint, error) {
 	return &v, nil
 }
 
+// compilePatternProperties compiles the patterns of a schema's PatternProperties. The compiled patterns are sorted
+// by their source text. If strict is false, invalid patterns are skipped rather than returning an error.
+func compilePatternProperties(m map[string]*Schema, strict bool) ([]patternProperty, error) {
+	patterns := make([]patternProperty, 0, len(m))
+	for _, pattern := range slices.Sorted(maps.Keys(m)) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			if strict {
+				return nil, fmt.Errorf("invalid pattern property %q: %w", pattern, err)
+			}
+			continue
+		}
+		patterns = append(patterns, patternProperty{pattern: re, schema: m[pattern]})
+	}
+	return patterns, nil
+}
+
 func parseRegexp(pattern string) (*regexp.Regexp, error) {
 	if pattern == "" {
 		return nil, nil
@@ -437,6 +567,62 @@ func buildOneOf[T Builder](b T, oneOf []Builder) T {
 	return b
 }
 
+func buildAllOf[T Builder](b T, allOf []Builder) T {
+	s := b.Schema()
+	s.AllOf = make([]*Schema, len(allOf))
+	for i, b := range allOf {
+		s.AllOf[i] = b.Schema()
+	}
+	return b
+}
+
+func buildNot[T Builder](b T, not Builder) T {
+	b.Schema().Not = not.Schema()
+	return b
+}
+
+func buildIf[T Builder](b T, cond, then, els Builder) T {
+	s := b.Schema()
+	s.If = cond.Schema()
+	if then != nil {
+		s.Then = then.Schema()
+	}
+	if els != nil {
+		s.Else = els.Schema()
+	}
+	return b
+}
+
+// intersection returns a schema that requires each of the given schemas.
+func intersection(allOf []*Schema) *Schema {
+	// Filter out Always schemas.
+	n, rotateOnly := 0, true
+	for _, s := range allOf {
+		if s == nil || s.Always {
+			continue
+		}
+		if s.Never {
+			return Never()
+		}
+		allOf[n] = s
+		rotateOnly = rotateOnly && s.rotateOnly
+		n++
+	}
+	allOf = allOf[:n]
+
+	switch len(allOf) {
+	case 0:
+		// If there are no schemas left, return Always.
+		return Always()
+	case 1:
+		// If there is one schema left, return it.
+		return allOf[0]
+	default:
+		// Otherwise, return an AllOf.
+		return &Schema{AllOf: allOf, rotateOnly: rotateOnly}
+	}
+}
+
 func union(oneOf []*Schema) *Schema {
 	// Filter out Never schemas.
 	n, rotateOnly := 0, true
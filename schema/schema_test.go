@@ -159,6 +159,40 @@ func TestProperty(t *testing.T) {
 	})
 }
 
+func TestPropertyApplicators(t *testing.T) {
+	t.Run("allOf", func(t *testing.T) {
+		a := Object().Properties(BuilderMap{"a": String()}).AdditionalProperties(Always()).Schema()
+		b := Object().Properties(BuilderMap{"a": String().MinLength(1), "b": Number()}).Schema()
+
+		s := AllOf(a, b).Schema()
+		assert.Equal(t, AllOf(String(), String().MinLength(1)).Schema(), s.Property("a"))
+		assert.Equal(t, Number().Schema(), s.Property("b"))
+		assert.True(t, s.Property("c").Never)
+	})
+
+	t.Run("patternProperties", func(t *testing.T) {
+		s := Object().
+			Properties(BuilderMap{"a": String()}).
+			PatternProperties(BuilderMap{"^x-": Number(), "^x-y": Number().Minimum(json.Number("0"))}).
+			AdditionalProperties(Boolean()).
+			Schema()
+		require.NoError(t, s.Compile())
+
+		assert.Equal(t, "string", s.Property("a").Type)
+		assert.Equal(t, "number", s.Property("x-a").Type)
+		assert.Equal(t, "boolean", s.Property("b").Type)
+
+		xy := s.Property("x-y")
+		require.Len(t, xy.AllOf, 2)
+		assert.Equal(t, json.Number("0"), xy.AllOf[1].Minimum)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		s := Object().PatternProperties(BuilderMap{"(": Number()}).Schema()
+		assert.Error(t, s.Compile())
+	})
+}
+
 func TestCompile(t *testing.T) {
 	path := "testdata"
 	entries, err := os.ReadDir(path)
@@ -197,3 +231,31 @@ func TestRotateOnly(t *testing.T) {
 	assert.True(t, root.Property("rotateOnly").Item(0).IsRotateOnly())
 	assert.False(t, root.Property("standard").IsRotateOnly())
 }
+
+func TestRotateOnlyApplicators(t *testing.T) {
+	root := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"rotateOnly": {
+				Type:              "object",
+				Not:               Null().Schema(),
+				If:                Object().Schema(),
+				Then:              Object().Properties(BuilderMap{"a": String()}).Schema(),
+				Else:              Array().Schema(),
+				Contains:          String().Schema(),
+				PatternProperties: map[string]*Schema{"^x-": String().Schema()},
+			},
+		},
+		RotateOnly: []string{"rotateOnly"},
+	}
+	err := root.Compile()
+	require.NoError(t, err)
+
+	p := root.Properties["rotateOnly"]
+	assert.True(t, p.IsRotateOnly())
+	for _, s := range []*Schema{p.Not, p.If, p.Then, p.Else, p.Contains, p.PatternProperties["^x-"]} {
+		assert.True(t, s.IsRotateOnly())
+	}
+	assert.True(t, p.Then.Property("a").IsRotateOnly())
+	assert.True(t, p.Property("x-y").IsRotateOnly())
+}
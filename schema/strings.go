@@ -39,6 +39,18 @@ func (b *StringBuilder) OneOf(oneOf ...Builder) *StringBuilder {
 	return buildOneOf(b, oneOf)
 }
 
+func (b *StringBuilder) AllOf(allOf ...Builder) *StringBuilder {
+	return buildAllOf(b, allOf)
+}
+
+func (b *StringBuilder) Not(not Builder) *StringBuilder {
+	return buildNot(b, not)
+}
+
+func (b *StringBuilder) If(cond, then, els Builder) *StringBuilder {
+	return buildIf(b, cond, then, els)
+}
+
 func (b *StringBuilder) Const(n string) *StringBuilder {
 	b.s.Const = n
 	return b
@@ -68,6 +80,11 @@ func (b *StringBuilder) Pattern(pattern string) *StringBuilder {
 	return b
 }
 
+func (b *StringBuilder) Format(format string) *StringBuilder {
+	b.s.Format = format
+	return b
+}
+
 func (b *StringBuilder) Title(title string) *StringBuilder {
 	b.s.Title = title
 	return b
@@ -77,6 +77,13 @@ func TestStrings(t *testing.T) {
 		}
 	})
 
+	t.Run("format", func(t *testing.T) {
+		s := String().
+			Format("uri").
+			Schema()
+		require.Equal(t, "uri", s.Format)
+	})
+
 	t.Run("maxLength", func(t *testing.T) {
 		maxLength := json.Number("5")
 		s := String().
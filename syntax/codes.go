@@ -0,0 +1,174 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syntax
+
+import "sort"
+
+// Diagnostic codes. Every diagnostic issued while decoding, parsing, checking, or evaluating an environment carries
+// one of these codes. Codes are stable: once a code has been published, its meaning does not change and it is not
+// reused. The message that accompanies a code is not stable, so tools should match on codes rather than messages.
+//
+// The Codes catalog documents each code.
+const (
+	// Codes for diagnostics issued while decoding YAML or JSON and encoding or decoding Go values (package
+	// syntax/encoding).
+
+	CodeYAMLSyntax         = "yaml-syntax"
+	CodeYAMLAlias          = "yaml-alias"
+	CodeYAMLUnsupported    = "yaml-unsupported"
+	CodeYAMLEncode         = "yaml-encode"
+	CodeYAMLMerge          = "yaml-merge"
+	CodeJSONSyntax         = "json-syntax"
+	CodeJSONEncode         = "json-encode"
+	CodeNonStringKey       = "non-string-key"
+	CodeUnsupportedType    = "unsupported-type"
+	CodeEncodeTypeMismatch = "encode-type-mismatch"
+
+	// Codes for diagnostics issued while parsing an environment definition (package ast).
+
+	CodeDuplicateKey          = "duplicate-key"
+	CodeUnknownField          = "unknown-field"
+	CodeInvalidType           = "invalid-type"
+	CodeInvalidAccessSyntax   = "invalid-access-syntax"
+	CodeReservedPrefix        = "reserved-prefix"
+	CodeTopLevelBuiltin       = "top-level-builtin"
+	CodeBuiltinNotAlone       = "builtin-not-alone"
+	CodeInvalidBuiltinArgs    = "invalid-builtin-args"
+	CodeInvalidImport         = "invalid-import"
+	CodeInvalidImportPath     = "invalid-import-path"
+	CodeInvalidImportAlias    = "invalid-import-alias"
+	CodeUnusedImportAlias     = "unused-import-alias"
+	CodeInvalidImportCond     = "invalid-import-condition"
+	CodeUnknownDiagnosticCode = "unknown-diagnostic-code"
+
+	// Codes for diagnostics issued while evaluating an environment (package eval).
+
+	CodeReservedKey           = "reserved-key"
+	CodeImportFailed          = "import-failed"
+	CodeCyclicImport          = "cyclic-import"
+	CodeOptionalImportSkipped = "optional-import-skipped"
+	CodeCyclicReference       = "cyclic-reference"
+	CodeFinalOverride         = "final-override"
+	CodeUnknownProperty       = "unknown-property"
+	CodeInvalidAccess         = "invalid-access"
+	CodeDecryptFailed         = "decrypt-failed"
+	CodeProviderFailed        = "provider-failed"
+	CodeOpenFailed            = "open-failed"
+	CodeRotateFailed          = "rotate-failed"
+	CodeInvalidBase64         = "invalid-base64"
+	CodeInvalidJSON           = "invalid-json"
+	CodeInvalidSchema         = "invalid-schema"
+	CodeInternalError         = "internal-error"
+
+	// Codes for diagnostics issued while validating values against schemas (packages eval and schema).
+
+	CodeSchemaType      = "schema-type"
+	CodeSchemaConst     = "schema-const"
+	CodeSchemaSubschema = "schema-subschema"
+	CodeSchemaRequired  = "schema-required"
+	CodeSchemaRange     = "schema-range"
+	CodeSchemaLength    = "schema-length"
+	CodeSchemaPattern   = "schema-pattern"
+	CodeSchemaFormat    = "schema-format"
+)
+
+// A CodeInfo documents a diagnostic code.
+type CodeInfo struct {
+	// Code is the diagnostic code.
+	Code string `json:"code"`
+
+	// Severity is the usual severity of diagnostics with this code, either "error" or "warning".
+	Severity string `json:"severity"`
+
+	// Description describes the problems reported by diagnostics with this code.
+	Description string `json:"description"`
+}
+
+// Codes is the catalog of diagnostic codes.
+var Codes = []CodeInfo{
+	{CodeYAMLSyntax, "error", "The environment definition is not valid YAML."},
+	{CodeYAMLAlias, "error", "A YAML alias refers to an unknown or enclosing anchor, or aliases expand to too many nodes."},
+	{CodeYAMLUnsupported, "error", "The environment definition contains a YAML node that is not supported."},
+	{CodeYAMLEncode, "error", "A value could not be encoded as YAML."},
+	{CodeYAMLMerge, "error", "The value of a YAML merge key (`<<`) is not a mapping or a sequence of mappings."},
+	{CodeJSONSyntax, "error", "The environment definition is not valid JSON."},
+	{CodeJSONEncode, "error", "A value could not be encoded as JSON."},
+	{CodeNonStringKey, "error", "An object or mapping key is not a string."},
+	{CodeUnsupportedType, "error", "A Go value of an unsupported type could not be decoded into a syntax node."},
+	{CodeEncodeTypeMismatch, "error", "A syntax node could not be encoded into a Go value of the requested type."},
+
+	{CodeDuplicateKey, "warning", "A key appears more than once in the same object."},
+	{CodeUnknownField, "warning", "A key does not name a field of the declaration that contains it."},
+	{CodeInvalidType, "error", "A declaration or field has the wrong type, e.g. a list where an object is required."},
+	{CodeInvalidAccessSyntax, "error", "A property access or interpolation is malformed."},
+	{CodeReservedPrefix, "error", "A key uses the reserved 'fn::' prefix but does not name a builtin function."},
+	{CodeTopLevelBuiltin, "error", "A builtin function is called at the top level of 'values'."},
+	{CodeBuiltinNotAlone, "error", "A builtin function call shares its object with other keys."},
+	{CodeInvalidBuiltinArgs, "error", "The arguments to a builtin function are missing or malformed."},
+	{CodeInvalidImport, "error", "An import is malformed."},
+	{CodeInvalidImportPath, "error", "The 'path' of an import is malformed or does not select a value."},
+	{CodeInvalidImportAlias, "error", "The 'as' of an import is malformed."},
+	{CodeUnusedImportAlias, "warning", "The 'as' of an import has no effect because the import is not merged."},
+	{CodeInvalidImportCond, "error", "The 'when' condition of an import is malformed or cannot be evaluated."},
+	{CodeUnknownDiagnosticCode, "warning", "A code listed in 'diagnostics.allow' is not a known diagnostic code."},
+
+	{CodeReservedKey, "error", "A top-level key is reserved and may not be defined."},
+	{CodeImportFailed, "error", "An imported environment could not be loaded."},
+	{CodeCyclicImport, "error", "An environment imports itself, directly or indirectly."},
+	{CodeOptionalImportSkipped, "warning", "An optional import could not be loaded and was skipped."},
+	{CodeCyclicReference, "error", "A value refers to itself, directly or indirectly."},
+	{CodeFinalOverride, "warning", "A value attempts to override a final value."},
+	{CodeUnknownProperty, "error", "A reference names a property that does not exist."},
+	{CodeInvalidAccess, "error", "A reference accesses a value in an invalid way, e.g. an array index that is out of bounds."},
+	{CodeDecryptFailed, "error", "A secret could not be decrypted."},
+	{CodeProviderFailed, "error", "A provider or rotator could not be loaded."},
+	{CodeOpenFailed, "error", "A provider or rotator failed to open."},
+	{CodeRotateFailed, "error", "A rotator failed to rotate its secret."},
+	{CodeInvalidBase64, "error", "A string passed to fn::fromBase64 is not valid base64."},
+	{CodeInvalidJSON, "error", "A value could not be encoded as or decoded from JSON."},
+	{CodeInvalidSchema, "error", "A schema is not a valid schema."},
+	{CodeInternalError, "error", "An internal error occurred. Please report these errors."},
+
+	{CodeSchemaType, "error", "A value does not have the type required by its schema."},
+	{CodeSchemaConst, "error", "A value does not match the constant or enumerated values required by its schema."},
+	{CodeSchemaSubschema, "error", "A value does not match the required number of subschemas of its schema."},
+	{CodeSchemaRequired, "error", "An object is missing properties required by its schema."},
+	{CodeSchemaRange, "error", "A number is outside of the range allowed by its schema."},
+	{CodeSchemaLength, "error", "A string, array, or object is shorter or longer than allowed by its schema."},
+	{CodeSchemaPattern, "error", "A string does not match the pattern required by its schema."},
+	{CodeSchemaFormat, "error", "A string does not conform to the format required by its schema."},
+}
+
+var codesByName = func() map[string]CodeInfo {
+	m := make(map[string]CodeInfo, len(Codes))
+	for _, info := range Codes {
+		m[info.Code] = info
+	}
+	return m
+}()
+
+// LookupCode returns the catalog entry for the given code, if any.
+func LookupCode(code string) (CodeInfo, bool) {
+	info, ok := codesByName[code]
+	return info, ok
+}
+
+// SortedCodes returns the catalog of diagnostic codes sorted by code.
+func SortedCodes() []CodeInfo {
+	sorted := make([]CodeInfo, len(Codes))
+	copy(sorted, Codes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Code < sorted[j].Code })
+	return sorted
+}
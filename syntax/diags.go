@@ -26,6 +26,29 @@ type Diagnostic struct {
 	hcl.Diagnostic
 
 	Path string
+
+	// Code is a short, stable identifier for the kind of the diagnostic. See Codes for the catalog of codes. Warnings
+	// may be allowed by listing their codes in an environment definition.
+	Code string `json:",omitempty"`
+
+	// AliasRange is the textual range of the alias through which the diagnostic's subject was reached, if any. When
+	// set, the subject lies within the anchored node that the alias refers to.
+	AliasRange *hcl.Range `json:",omitempty"`
+}
+
+// WithCode sets the diagnostic's code and returns the diagnostic.
+func (d *Diagnostic) WithCode(code string) *Diagnostic {
+	d.Code = code
+	return d
+}
+
+// WithAliasRange records the range of the alias through which the given syntax was reached, if any, and returns the
+// diagnostic.
+func (d *Diagnostic) WithAliasRange(s Syntax) *Diagnostic {
+	if s, ok := s.(AliasedSyntax); ok {
+		d.AliasRange = s.AliasRange()
+	}
+	return d
 }
 
 // Error creates a new error-level diagnostic from the given subject, summary, and detail.
@@ -37,15 +60,13 @@ func Error(rng *hcl.Range, summary, path string) *Diagnostic {
 }
 
 // NodeError creates a new error-level diagnostic from the given node, summary, and detail. If the node is non-nil,
-// the diagnostic will be associated with the range of its associated syntax, if any.
+// the diagnostic will be associated with the range of its associated syntax and the range of the alias through which
+// the node was reached, if any.
 func NodeError(node Node, summary string) *Diagnostic {
-	var rng *hcl.Range
-	var path string
-	if node != nil {
-		rng = node.Syntax().Range()
-		path = node.Syntax().Path()
+	if node == nil {
+		return Error(nil, summary, "")
 	}
-	return Error(rng, summary, path)
+	return Error(node.Syntax().Range(), summary, node.Syntax().Path()).WithAliasRange(node.Syntax())
 }
 
 // Diagnostics is a list of diagnostics.
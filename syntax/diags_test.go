@@ -38,3 +38,31 @@ func TestDiagsError(t *testing.T) {
 		assert.Equal(t, "\n-error: <nil>: error diag; \n-warning: <nil>: warning diag; ", diags.Error())
 	})
 }
+
+func TestCodes(t *testing.T) {
+	seen := map[string]bool{}
+	for _, info := range Codes {
+		assert.False(t, seen[info.Code], "duplicate code %q", info.Code)
+		seen[info.Code] = true
+
+		assert.Contains(t, []string{"error", "warning"}, info.Severity, "code %q", info.Code)
+		assert.NotEmpty(t, info.Description, "code %q", info.Code)
+
+		found, ok := LookupCode(info.Code)
+		assert.True(t, ok)
+		assert.Equal(t, info, found)
+	}
+
+	_, ok := LookupCode("no-such-code")
+	assert.False(t, ok)
+
+	sorted := SortedCodes()
+	assert.Len(t, sorted, len(Codes))
+	assert.IsIncreasing(t, func() []string {
+		codes := make([]string, len(sorted))
+		for i, info := range sorted {
+			codes[i] = info.Code
+		}
+		return codes
+	}())
+}
@@ -15,5 +15,5 @@
 package encoding
 
 // The encoding package provides encoders and decoders to and from the trees defined by the syntax package. Currently,
-// YAML and Go object encoders/decoders are supported. The YAML encoders/decoders may also be used to encode/decode
-// JSON documents.
+// YAML, JSON, and Go object encoders/decoders are supported. The YAML encoders/decoders may also be used to
+// encode/decode JSON documents, but the JSON encoders/decoders report accurate JSON source ranges and write JSON text.
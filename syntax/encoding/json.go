@@ -0,0 +1,745 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/esc/syntax"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/rivo/uniseg"
+)
+
+// A JSONKind is the kind of a JSON node.
+type JSONKind int
+
+const (
+	// JSONNull is the kind of the null literal.
+	JSONNull JSONKind = iota + 1
+	// JSONBoolean is the kind of the true and false literals.
+	JSONBoolean
+	// JSONNumber is the kind of number literals.
+	JSONNumber
+	// JSONString is the kind of string literals.
+	JSONString
+	// JSONArray is the kind of arrays.
+	JSONArray
+	// JSONObject is the kind of objects.
+	JSONObject
+)
+
+// A JSONNode is a node in a JSON document. Unlike the values produced by encoding/json, JSONNodes preserve the order
+// of object keys, the text of number literals, and the source position of each value.
+type JSONNode struct {
+	// Kind is the kind of the node. The zero kind denotes a node whose value has not been set.
+	Kind JSONKind
+
+	// Value holds the value of a scalar node. For null, boolean, and number literals, this is the literal's text. For
+	// strings, this is the decoded string.
+	Value string
+
+	// Content holds the elements of an array node or the keys and values of an object node. As with YAML mapping
+	// nodes, object entries are represented as a sequence of the form [key_0, value_0, ... key_n, value_n]. Keys are
+	// always string nodes.
+	Content []*JSONNode
+
+	// Start is the position of the node's first character. The zero position denotes a node with no source.
+	Start hcl.Pos
+
+	// End is the position immediately after the node's last character.
+	End hcl.Pos
+}
+
+// JSONSyntax is a syntax.Syntax implementation that is backed by a JSON node.
+type JSONSyntax struct {
+	*JSONNode
+	rng   *hcl.Range
+	path  string
+	value interface{}
+}
+
+// Range returns the textual range of the JSON node, if any.
+func (s JSONSyntax) Range() *hcl.Range {
+	return s.rng
+}
+
+// Path returns the path of the JSON node, if any.
+func (s JSONSyntax) Path() string {
+	return s.path
+}
+
+func (s JSONSyntax) ScalarRange(start, end int) *hcl.Range {
+	if s.rng == nil || s.Kind != JSONString {
+		return nil
+	}
+
+	// The range is only accurate if the string contains no escape sequences, in which case its source text is the
+	// value surrounded by quotes.
+	if s.rng.End.Byte-s.rng.Start.Byte != len(s.Value)+2 {
+		return nil
+	}
+
+	startPos := s.rng.Start
+	startPos.Byte += 1 + start
+	startPos.Column += 1 + uniseg.GraphemeClusterCount(s.Value[:start])
+
+	endPos := s.rng.Start
+	endPos.Byte += 1 + end
+	endPos.Column += 1 + uniseg.GraphemeClusterCount(s.Value[:end])
+
+	return &hcl.Range{
+		Filename: s.rng.Filename,
+		Start:    startPos,
+		End:      endPos,
+	}
+}
+
+// Get returns the node at the given path, if any.
+func (s JSONSyntax) Get(path resource.PropertyPath) (_ *JSONNode, ok bool) {
+	if len(path) == 0 {
+		return s.JSONNode, true
+	}
+
+	switch s.Kind {
+	case JSONArray:
+		index, ok := path[0].(int)
+		if !ok || index < 0 || index >= len(s.Content) {
+			return nil, false
+		}
+		return JSONSyntax{JSONNode: s.Content[index]}.Get(path[1:])
+	case JSONObject:
+		key, ok := path[0].(string)
+		if !ok {
+			return nil, false
+		}
+		for i := 0; i < len(s.Content); i += 2 {
+			if s.Content[i].Value == key {
+				return JSONSyntax{JSONNode: s.Content[i+1]}.Get(path[1:])
+			}
+		}
+		return nil, false
+	default:
+		return nil, false
+	}
+}
+
+// Set sets the value at the given path to new, creating any missing arrays and objects along the way, and returns the
+// updated node. The prefix is used to annotate errors.
+func (s JSONSyntax) Set(prefix, path resource.PropertyPath, new JSONNode) (*JSONNode, error) {
+	if len(path) == 0 {
+		s.Kind = new.Kind
+		s.Value = new.Value
+		s.Content = new.Content
+		return s.JSONNode, nil
+	}
+
+	prefix = append(prefix, path[0])
+	switch s.Kind {
+	case 0:
+		switch accessor := path[0].(type) {
+		case int:
+			s.Kind = JSONArray
+		case string:
+			s.Kind = JSONObject
+		default:
+			contract.Failf("unexpected accessor kind %T", accessor)
+			return nil, nil
+		}
+		return s.Set(prefix[:len(prefix)-1], path, new)
+	case JSONArray:
+		index, ok := path[0].(int)
+		if !ok {
+			return nil, fmt.Errorf("%v: key for an array must be an int", prefix)
+		}
+		if index < 0 || index > len(s.Content) {
+			return nil, fmt.Errorf("%v: array index out of range", prefix)
+		}
+		if index == len(s.Content) {
+			s.Content = append(s.Content, &JSONNode{})
+		}
+		return JSONSyntax{JSONNode: s.Content[index]}.Set(prefix, path[1:], new)
+	case JSONObject:
+		key, ok := path[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%v: key for a map must be a string", prefix)
+		}
+
+		var valueNode *JSONNode
+		for i := 0; i < len(s.Content); i += 2 {
+			if s.Content[i].Value == key {
+				valueNode = s.Content[i+1]
+				break
+			}
+		}
+		if valueNode == nil {
+			valueNode = &JSONNode{}
+			s.Content = append(s.Content, &JSONNode{Kind: JSONString, Value: key}, valueNode)
+		}
+		return JSONSyntax{JSONNode: valueNode}.Set(prefix, path[1:], new)
+	default:
+		return nil, fmt.Errorf("%v: expected an array or an object", prefix)
+	}
+}
+
+// Delete deletes the value at the given path. Deleting a missing object property is not an error. The prefix is used
+// to annotate errors.
+func (s JSONSyntax) Delete(prefix, path resource.PropertyPath) error {
+	prefix = append(prefix, path[0])
+	switch s.Kind {
+	case JSONArray:
+		index, ok := path[0].(int)
+		if !ok {
+			return fmt.Errorf("%v: key for an array must be an int", prefix)
+		}
+		if index < 0 || index >= len(s.Content) {
+			return fmt.Errorf("%v: array index out of range", prefix)
+		}
+		if len(path) == 1 {
+			s.Content = append(s.Content[:index], s.Content[index+1:]...)
+			return nil
+		}
+		return JSONSyntax{JSONNode: s.Content[index]}.Delete(prefix, path[1:])
+	case JSONObject:
+		key, ok := path[0].(string)
+		if !ok {
+			return fmt.Errorf("%v: key for a map must be a string", prefix)
+		}
+
+		i := 0
+		for ; i < len(s.Content); i += 2 {
+			if s.Content[i].Value == key {
+				break
+			}
+		}
+		if len(path) == 1 {
+			if i != len(s.Content) {
+				s.Content = append(s.Content[:i], s.Content[i+2:]...)
+			}
+			return nil
+		}
+		if i == len(s.Content) {
+			return fmt.Errorf("%v: property not found", prefix)
+		}
+		return JSONSyntax{JSONNode: s.Content[i+1]}.Delete(prefix, path[1:])
+	default:
+		return fmt.Errorf("%v: expected an array or an object", prefix)
+	}
+}
+
+// IsJSON returns true if the given source is a JSON object. Environment definitions that are JSON objects may be
+// decoded with DecodeJSONBytes rather than DecodeYAMLBytes in order to preserve their format. Sources that begin with
+// '{' but are not valid JSON, such as YAML flow mappings, are not JSON objects.
+func IsJSON(source []byte) bool {
+	if !startsWithBrace(source) {
+		return false
+	}
+	_, diags := ParseJSON("", source)
+	return !diags.HasErrors()
+}
+
+// startsWithBrace returns true if the first non-whitespace character of the given source is '{'.
+func startsWithBrace(source []byte) bool {
+	trimmed := bytes.TrimLeft(source, " \t\r\n")
+	return len(trimmed) != 0 && trimmed[0] == '{'
+}
+
+// ParseJSON parses a JSON document into a tree of JSONNodes. Each node records its position within the source.
+func ParseJSON(filename string, source []byte) (*JSONNode, syntax.Diagnostics) {
+	p := jsonParser{filename: filename, source: source, line: 1, column: 1}
+
+	p.skipSpace()
+	n, ok := p.parseValue()
+	if !ok {
+		return nil, p.diags
+	}
+	p.skipSpace()
+	if p.offset != len(p.source) {
+		p.errorf("unexpected %v after top-level value", p.describe())
+		return nil, p.diags
+	}
+	return n, nil
+}
+
+type jsonParser struct {
+	filename     string
+	source       []byte
+	offset       int
+	line         int
+	column       int // the column of columnOffset
+	columnOffset int // the offset at which column was last computed
+	diags        syntax.Diagnostics
+}
+
+// pos returns the position of the current offset. The column is advanced from the last computed position rather than
+// recomputed from the start of the line, so computing the positions of a line's nodes is linear in its length.
+func (p *jsonParser) pos() hcl.Pos {
+	if text := p.source[p.columnOffset:p.offset]; len(text) != 0 {
+		if isASCII(text) {
+			p.column += len(text)
+		} else {
+			p.column += uniseg.GraphemeClusterCount(string(text))
+		}
+		p.columnOffset = p.offset
+	}
+	return hcl.Pos{Line: p.line, Column: p.column, Byte: p.offset}
+}
+
+func (p *jsonParser) errorf(format string, args ...any) {
+	pos := p.pos()
+	rng := &hcl.Range{Filename: p.filename, Start: pos, End: pos}
+	p.diags.Extend(syntax.Error(rng, fmt.Sprintf(format, args...), "").WithCode(syntax.CodeJSONSyntax))
+}
+
+// describe describes the next token for use in error messages.
+func (p *jsonParser) describe() string {
+	if p.offset >= len(p.source) {
+		return "end of input"
+	}
+	r := []rune(string(p.source[p.offset:min(p.offset+4, len(p.source))]))[0]
+	return fmt.Sprintf("character %q", r)
+}
+
+func (p *jsonParser) skipSpace() {
+	for p.offset < len(p.source) {
+		switch p.source[p.offset] {
+		case '\n':
+			p.offset++
+			p.line, p.column, p.columnOffset = p.line+1, 1, p.offset
+		case ' ', '\t', '\r':
+			p.offset++
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsonParser) peek() byte {
+	if p.offset >= len(p.source) {
+		return 0
+	}
+	return p.source[p.offset]
+}
+
+func (p *jsonParser) parseValue() (*JSONNode, bool) {
+	switch c := p.peek(); {
+	case c == '{':
+		return p.parseObject()
+	case c == '[':
+		return p.parseArray()
+	case c == '"':
+		return p.parseString()
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	case c == 't':
+		return p.parseLiteral("true", JSONBoolean)
+	case c == 'f':
+		return p.parseLiteral("false", JSONBoolean)
+	case c == 'n':
+		return p.parseLiteral("null", JSONNull)
+	default:
+		p.errorf("unexpected %v looking for the beginning of a value", p.describe())
+		return nil, false
+	}
+}
+
+func (p *jsonParser) parseLiteral(text string, kind JSONKind) (*JSONNode, bool) {
+	start := p.pos()
+	if !bytes.HasPrefix(p.source[p.offset:], []byte(text)) {
+		p.errorf("invalid literal; expected %q", text)
+		return nil, false
+	}
+	p.offset += len(text)
+	return &JSONNode{Kind: kind, Value: text, Start: start, End: p.pos()}, true
+}
+
+func (p *jsonParser) parseNumber() (*JSONNode, bool) {
+	start := p.pos()
+
+	digits := func() int {
+		n := 0
+		for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+			p.offset, n = p.offset+1, n+1
+		}
+		return n
+	}
+
+	if p.peek() == '-' {
+		p.offset++
+	}
+	if p.peek() == '0' {
+		p.offset++
+	} else if digits() == 0 {
+		p.errorf("invalid number; expected a digit")
+		return nil, false
+	}
+	if p.peek() == '.' {
+		p.offset++
+		if digits() == 0 {
+			p.errorf("invalid number; expected a digit after the decimal point")
+			return nil, false
+		}
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.offset++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.offset++
+		}
+		if digits() == 0 {
+			p.errorf("invalid number; expected a digit in the exponent")
+			return nil, false
+		}
+	}
+
+	text := string(p.source[start.Byte:p.offset])
+	return &JSONNode{Kind: JSONNumber, Value: text, Start: start, End: p.pos()}, true
+}
+
+func (p *jsonParser) parseString() (*JSONNode, bool) {
+	start := p.pos()
+
+	// Find the closing quote, then let encoding/json validate and decode the literal.
+	end := p.offset + 1
+	for ; end < len(p.source); end++ {
+		c := p.source[end]
+		if c == '"' {
+			break
+		}
+		if c == '\\' {
+			end++
+			continue
+		}
+		if c < 0x20 {
+			p.offset = end
+			p.errorf("invalid control character in string")
+			return nil, false
+		}
+	}
+	if end >= len(p.source) {
+		p.offset = len(p.source)
+		p.errorf("unterminated string")
+		return nil, false
+	}
+
+	var value string
+	if err := json.Unmarshal(p.source[p.offset:end+1], &value); err != nil {
+		p.errorf("invalid string: %v", err)
+		return nil, false
+	}
+	p.offset = end + 1
+	return &JSONNode{Kind: JSONString, Value: value, Start: start, End: p.pos()}, true
+}
+
+func (p *jsonParser) parseArray() (*JSONNode, bool) {
+	n := &JSONNode{Kind: JSONArray, Start: p.pos()}
+	p.offset++
+
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.offset++
+		n.End = p.pos()
+		return n, true
+	}
+	for {
+		p.skipSpace()
+		e, ok := p.parseValue()
+		if !ok {
+			return nil, false
+		}
+		n.Content = append(n.Content, e)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.offset++
+		case ']':
+			p.offset++
+			n.End = p.pos()
+			return n, true
+		default:
+			p.errorf("unexpected %v after array element; expected ',' or ']'", p.describe())
+			return nil, false
+		}
+	}
+}
+
+func (p *jsonParser) parseObject() (*JSONNode, bool) {
+	n := &JSONNode{Kind: JSONObject, Start: p.pos()}
+	p.offset++
+
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.offset++
+		n.End = p.pos()
+		return n, true
+	}
+	for {
+		p.skipSpace()
+		if p.peek() != '"' {
+			p.errorf("unexpected %v looking for the beginning of an object key", p.describe())
+			return nil, false
+		}
+		k, ok := p.parseString()
+		if !ok {
+			return nil, false
+		}
+
+		p.skipSpace()
+		if p.peek() != ':' {
+			p.errorf("unexpected %v after object key; expected ':'", p.describe())
+			return nil, false
+		}
+		p.offset++
+
+		p.skipSpace()
+		v, ok := p.parseValue()
+		if !ok {
+			return nil, false
+		}
+		n.Content = append(n.Content, k, v)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.offset++
+		case '}':
+			p.offset++
+			n.End = p.pos()
+			return n, true
+		default:
+			p.errorf("unexpected %v after object value; expected ',' or '}'", p.describe())
+			return nil, false
+		}
+	}
+}
+
+func jsonNodeRange(filename string, n *JSONNode) *hcl.Range {
+	if n.Start.Line == 0 {
+		return nil
+	}
+	return &hcl.Range{Filename: filename, Start: n.Start, End: n.End}
+}
+
+// UnmarshalJSONNode unmarshals the given JSON node into a syntax node.
+//
+// Nodes are decoded as follows:
+// - Literals are decoded as the corresponding literal type (null -> NullNode, true/false -> BooleanNode, etc.)
+// - Arrays are decoded as array nodes
+// - Objects are decoded as object nodes
+//
+// Duplicate object keys are preserved.
+func UnmarshalJSONNode(filename string, n *JSONNode) (syntax.Node, syntax.Diagnostics) {
+	return unmarshalJSONNode(filename, nil, n)
+}
+
+func unmarshalJSONNode(filename string, path []any, n *JSONNode) (syntax.Node, syntax.Diagnostics) {
+	rng := jsonNodeRange(filename, n)
+	pathString := positionIndex{path: path}.pathString()
+
+	var diags syntax.Diagnostics
+	switch n.Kind {
+	case JSONNull:
+		return syntax.NullSyntax(JSONSyntax{n, rng, pathString, nil}), nil
+	case JSONBoolean:
+		v := n.Value == "true"
+		return syntax.BooleanSyntax(JSONSyntax{n, rng, pathString, v}, v), nil
+	case JSONNumber:
+		v := json.Number(n.Value)
+		return syntax.NumberSyntax(JSONSyntax{n, rng, pathString, v}, v), nil
+	case JSONString:
+		return syntax.StringSyntax(JSONSyntax{n, rng, pathString, n.Value}, n.Value), nil
+	case JSONArray:
+		var elements []syntax.Node
+		if len(n.Content) != 0 {
+			elements = make([]syntax.Node, len(n.Content))
+			for i, v := range n.Content {
+				e, ediags := unmarshalJSONNode(filename, append(path[:len(path):len(path)], i), v)
+				diags.Extend(ediags...)
+
+				elements[i] = e
+			}
+		}
+		return syntax.ArraySyntax(JSONSyntax{n, rng, pathString, nil}, elements...), diags
+	case JSONObject:
+		var entries []syntax.ObjectPropertyDef
+		if len(n.Content) != 0 {
+			entries = make([]syntax.ObjectPropertyDef, len(n.Content)/2)
+			for i := range entries {
+				keyNode, valueNode := n.Content[2*i], n.Content[2*i+1]
+				entryPath := append(path[:len(path):len(path)], keyNode.Value)
+				keySyntax := JSONSyntax{keyNode, jsonNodeRange(filename, keyNode), positionIndex{path: entryPath}.pathString(), keyNode.Value}
+
+				value, vdiags := unmarshalJSONNode(filename, entryPath, valueNode)
+				diags.Extend(vdiags...)
+
+				entries[i] = syntax.ObjectPropertySyntax(keySyntax, syntax.StringSyntax(keySyntax, keyNode.Value), value)
+			}
+		}
+		return syntax.ObjectSyntax(JSONSyntax{n, rng, pathString, nil}, entries...), diags
+	default:
+		return nil, syntax.Diagnostics{syntax.Error(rng, "JSON node has no value", pathString).WithCode(syntax.CodeJSONSyntax)}
+	}
+}
+
+// DecodeJSONBytes decodes a JSON document into a syntax node. See UnmarshalJSONNode for more details on the decoding
+// process.
+func DecodeJSONBytes(filename string, source []byte) (syntax.Node, syntax.Diagnostics) {
+	// If this is an empty file, return an empty object node.
+	if len(bytes.TrimSpace(source)) == 0 {
+		return &syntax.ObjectNode{}, nil
+	}
+	n, diags := ParseJSON(filename, source)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return UnmarshalJSONNode(filename, n)
+}
+
+// MarshalJSONNode marshals a syntax node into a JSON node. If a syntax node has an associated JSONSyntax annotation
+// and its value has not changed, the text of the original literal is preserved (e.g. `1.0` is not rewritten to `1`).
+// The marshaling process otherwise follows the inverse of the unmarshaling process described in the documentation for
+// UnmarshalJSONNode.
+func MarshalJSONNode(n syntax.Node) (*JSONNode, syntax.Diagnostics) {
+	if n == nil {
+		return &JSONNode{}, syntax.Diagnostics{syntax.Error(nil, "nil nodes are not supported", "").WithCode(syntax.CodeJSONEncode)}
+	}
+
+	var original *JSONNode
+	var originalValue interface{}
+	if s, ok := n.Syntax().(JSONSyntax); ok {
+		original, originalValue = s.JSONNode, s.value
+	}
+
+	var diags syntax.Diagnostics
+	switch n := n.(type) {
+	case *syntax.NullNode:
+		return &JSONNode{Kind: JSONNull, Value: "null"}, nil
+	case *syntax.BooleanNode:
+		return &JSONNode{Kind: JSONBoolean, Value: fmt.Sprint(n.Value())}, nil
+	case *syntax.NumberNode:
+		value := string(n.Value())
+		if original != nil && original.Kind == JSONNumber && originalValue == n.Value() {
+			value = original.Value
+		}
+		return &JSONNode{Kind: JSONNumber, Value: value}, nil
+	case *syntax.StringNode:
+		return &JSONNode{Kind: JSONString, Value: n.Value()}, nil
+	case *syntax.ArrayNode:
+		node := &JSONNode{Kind: JSONArray}
+		if n.Len() != 0 {
+			node.Content = make([]*JSONNode, n.Len())
+			for i := range node.Content {
+				e, ediags := MarshalJSONNode(n.Index(i))
+				diags.Extend(ediags...)
+
+				node.Content[i] = e
+			}
+		}
+		return node, diags
+	case *syntax.ObjectNode:
+		node := &JSONNode{Kind: JSONObject}
+		if n.Len() != 0 {
+			node.Content = make([]*JSONNode, 2*n.Len())
+			for i := 0; i < n.Len(); i++ {
+				kvp := n.Index(i)
+
+				v, vdiags := MarshalJSONNode(kvp.Value)
+				diags.Extend(vdiags...)
+
+				node.Content[2*i], node.Content[2*i+1] = &JSONNode{Kind: JSONString, Value: kvp.Key.Value()}, v
+			}
+		}
+		return node, diags
+	default:
+		return &JSONNode{}, syntax.Diagnostics{syntax.Error(nil, fmt.Sprintf("unsupported node of type %T", n), "").WithCode(syntax.CodeJSONEncode)}
+	}
+}
+
+// WriteJSON writes a JSON node to w as indented JSON text followed by a newline. Nodes whose value has not been set
+// are written as null.
+func WriteJSON(w io.Writer, n *JSONNode) error {
+	b := bufio.NewWriter(w)
+	writeJSONNode(b, n, "")
+	b.WriteByte('\n')
+	return b.Flush()
+}
+
+func writeJSONNode(w *bufio.Writer, n *JSONNode, indent string) {
+	switch n.Kind {
+	case JSONBoolean, JSONNumber:
+		w.WriteString(n.Value)
+	case JSONString:
+		writeJSONString(w, n.Value)
+	case JSONArray:
+		if len(n.Content) == 0 {
+			w.WriteString("[]")
+			return
+		}
+		w.WriteString("[\n")
+		for i, e := range n.Content {
+			w.WriteString(indent + "  ")
+			writeJSONNode(w, e, indent+"  ")
+			if i != len(n.Content)-1 {
+				w.WriteByte(',')
+			}
+			w.WriteByte('\n')
+		}
+		w.WriteString(indent + "]")
+	case JSONObject:
+		if len(n.Content) == 0 {
+			w.WriteString("{}")
+			return
+		}
+		w.WriteString("{\n")
+		for i := 0; i < len(n.Content); i += 2 {
+			w.WriteString(indent + "  ")
+			writeJSONString(w, n.Content[i].Value)
+			w.WriteString(": ")
+			writeJSONNode(w, n.Content[i+1], indent+"  ")
+			if i != len(n.Content)-2 {
+				w.WriteByte(',')
+			}
+			w.WriteByte('\n')
+		}
+		w.WriteString(indent + "}")
+	default:
+		w.WriteString("null")
+	}
+}
+
+func writeJSONString(w *bufio.Writer, s string) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(s)
+	contract.AssertNoErrorf(err, "encoding string")
+	w.WriteString(strings.TrimSuffix(b.String(), "\n"))
+}
+
+// EncodeJSON encodes a syntax node into indented JSON text and writes it to w. See MarshalJSONNode for more details
+// on the encoding process.
+func EncodeJSON(w io.Writer, n syntax.Node) syntax.Diagnostics {
+	jsonNode, diags := MarshalJSONNode(n)
+	if err := WriteJSON(w, jsonNode); err != nil {
+		diags.Extend(syntax.Error(nil, err.Error(), "").WithCode(syntax.CodeJSONEncode))
+	}
+	return diags
+}
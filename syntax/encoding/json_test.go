@@ -0,0 +1,216 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/esc/syntax"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON(t *testing.T) {
+	type expectedData struct {
+		Syntax      *Node              `json:"syntax,omitempty"`
+		Diags       syntax.Diagnostics `json:"diags,omitempty"`
+		EncodeDiags syntax.Diagnostics `json:"encodeDiags,omitempty"`
+	}
+
+	path := filepath.Join("testdata", "json")
+	entries, err := os.ReadDir(path)
+	require.NoError(t, err)
+	for _, e := range entries {
+		t.Run(e.Name(), func(t *testing.T) {
+			basepath := filepath.Join(path, e.Name())
+			jsonPath := filepath.Join(basepath, "doc.json")
+			decodedPath := filepath.Join(basepath, "decoded.json")
+			encodedPath := filepath.Join(basepath, "encoded.json")
+
+			jsonBytes, err := os.ReadFile(jsonPath)
+			require.NoError(t, err)
+
+			root, diags := DecodeJSONBytes(e.Name(), jsonBytes)
+			sortDiagnostics(diags)
+
+			var syn *Node
+			var encoded []byte
+			var encodeDiags syntax.Diagnostics
+			if root != nil {
+				s := NewNode(root)
+				syn = &s
+
+				var b bytes.Buffer
+				encodeDiags = EncodeJSON(&b, root)
+				encoded = b.Bytes()
+			}
+
+			if accept() {
+				bytes, err := json.MarshalIndent(expectedData{
+					Syntax:      syn,
+					Diags:       diags,
+					EncodeDiags: encodeDiags,
+				}, "", "    ")
+				require.NoError(t, err)
+
+				err = os.WriteFile(decodedPath, bytes, 0o600)
+				require.NoError(t, err)
+
+				if len(encoded) != 0 {
+					err = os.WriteFile(encodedPath, encoded, 0o600)
+					require.NoError(t, err)
+				}
+
+				return
+			}
+
+			var expected expectedData
+			expectedBytes, err := os.ReadFile(decodedPath)
+			require.NoError(t, err)
+			dec := json.NewDecoder(bytes.NewReader(expectedBytes))
+			dec.UseNumber()
+			err = dec.Decode(&expected)
+			require.NoError(t, err)
+
+			var expectedJSON []byte
+			if root != nil {
+				b, err := os.ReadFile(encodedPath)
+				require.NoError(t, err)
+				expectedJSON = b
+			}
+
+			assert.Equal(t, expected.Syntax, syn)
+			assert.Equal(t, expected.Diags, diags)
+			assert.Equal(t, expectedJSON, encoded)
+			assert.Equal(t, encodeDiags, expected.EncodeDiags)
+		})
+	}
+}
+
+func TestJSONScalarRange(t *testing.T) {
+	root, diags := DecodeJSONBytes("scalar", []byte(`{"a": "${foo} é ${bar}", "b": "\t${foo}"}`))
+	require.Empty(t, diags)
+
+	obj := root.(*syntax.ObjectNode)
+
+	a := obj.Index(0).Value.Syntax().(JSONSyntax)
+	assert.Equal(t, &hcl.Range{
+		Filename: "scalar",
+		Start:    hcl.Pos{Line: 1, Column: 8, Byte: 7},
+		End:      hcl.Pos{Line: 1, Column: 14, Byte: 13},
+	}, a.ScalarRange(0, 6))
+	assert.Equal(t, &hcl.Range{
+		Filename: "scalar",
+		Start:    hcl.Pos{Line: 1, Column: 17, Byte: 17},
+		End:      hcl.Pos{Line: 1, Column: 23, Byte: 23},
+	}, a.ScalarRange(10, 16))
+
+	// Strings that contain escape sequences do not have accurate scalar ranges.
+	b := obj.Index(1).Value.Syntax().(JSONSyntax)
+	assert.Nil(t, b.ScalarRange(1, 7))
+}
+
+func TestJSONEdit(t *testing.T) {
+	const doc = `{
+  "imports": ["a", "b"],
+  "values": {"foo": 1.50, "bar": {"baz": "qux"}}
+}`
+
+	const expected = `{
+  "imports": [
+    "a",
+    "c"
+  ],
+  "values": {
+    "foo": 1.50,
+    "bar": {},
+    "new": {
+      "list": [
+        true
+      ]
+    }
+  }
+}
+`
+
+	root, diags := ParseJSON("edit", []byte(doc))
+	require.Empty(t, diags)
+	s := JSONSyntax{JSONNode: root}
+
+	_, err := s.Set(nil, resource.PropertyPath{"imports", 1}, JSONNode{Kind: JSONString, Value: "c"})
+	require.NoError(t, err)
+	_, err = s.Set(nil, resource.PropertyPath{"values", "new", "list", 0}, JSONNode{Kind: JSONBoolean, Value: "true"})
+	require.NoError(t, err)
+	require.NoError(t, s.Delete(nil, resource.PropertyPath{"values", "bar", "baz"}))
+	require.NoError(t, s.Delete(nil, resource.PropertyPath{"values", "missing"}))
+
+	_, err = s.Set(nil, resource.PropertyPath{"imports", "key"}, JSONNode{Kind: JSONNull, Value: "null"})
+	assert.EqualError(t, err, "imports.key: key for an array must be an int")
+	_, err = s.Set(nil, resource.PropertyPath{"imports", 5}, JSONNode{Kind: JSONNull, Value: "null"})
+	assert.EqualError(t, err, "imports[5]: array index out of range")
+	err = s.Delete(nil, resource.PropertyPath{"values", "missing", "foo"})
+	assert.EqualError(t, err, "values.missing: property not found")
+
+	var b strings.Builder
+	require.NoError(t, WriteJSON(&b, root))
+	assert.Equal(t, expected, b.String())
+}
+
+func TestJSONPositions(t *testing.T) {
+	root, diags := ParseJSON("positions", []byte("{\"é\": [\"ü\", 1],\n  \"b\": true}"))
+	require.Empty(t, diags)
+
+	assert.Equal(t, hcl.Pos{Line: 1, Column: 1, Byte: 0}, root.Start)
+	assert.Equal(t, hcl.Pos{Line: 2, Column: 13, Byte: 30}, root.End)
+
+	key, array := root.Content[0], root.Content[1]
+	assert.Equal(t, hcl.Pos{Line: 1, Column: 2, Byte: 1}, key.Start)
+	assert.Equal(t, hcl.Pos{Line: 1, Column: 5, Byte: 5}, key.End)
+	assert.Equal(t, hcl.Pos{Line: 1, Column: 13, Byte: 14}, array.Content[1].Start)
+
+	b := root.Content[3]
+	assert.Equal(t, hcl.Pos{Line: 2, Column: 8, Byte: 25}, b.Start)
+}
+
+func TestJSONEmpty(t *testing.T) {
+	root, diags := DecodeJSONBytes("empty", []byte(" \n"))
+	require.Empty(t, diags)
+	assert.Equal(t, &syntax.ObjectNode{}, root)
+
+	root, diags = DecodeJSONBytes("empty", []byte(`{"a": [], "b": {}}`))
+	require.Empty(t, diags)
+
+	var b strings.Builder
+	diags = EncodeJSON(&b, root)
+	require.Empty(t, diags)
+	assert.Equal(t, "{\n  \"a\": [],\n  \"b\": {}\n}\n", b.String())
+}
+
+func TestIsJSON(t *testing.T) {
+	assert.True(t, IsJSON([]byte(` {"values": {}}`)))
+	assert.False(t, IsJSON([]byte(`values: {}`)))
+	assert.False(t, IsJSON([]byte(`{"values": {}`)))
+	assert.False(t, IsJSON([]byte(`{values: {foo: bar}}`)))
+	assert.False(t, IsJSON([]byte(`{"values": {}} # note`)))
+	assert.False(t, IsJSON([]byte(`[]`)))
+	assert.False(t, IsJSON([]byte(``)))
+}
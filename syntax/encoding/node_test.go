@@ -27,13 +27,25 @@ type NodeProperty struct {
 }
 
 type Node struct {
-	Literal any            `json:"literal,omitempty"`
-	Array   []Node         `json:"array,omitempty"`
-	Object  []NodeProperty `json:"object,omitempty"`
-	Range   *hcl.Range     `json:"range,omitempty"`
+	Literal    any            `json:"literal,omitempty"`
+	Array      []Node         `json:"array,omitempty"`
+	Object     []NodeProperty `json:"object,omitempty"`
+	Range      *hcl.Range     `json:"range,omitempty"`
+	AliasRange *hcl.Range     `json:"aliasRange,omitempty"`
 }
 
 func NewNode(n syntax.Node) Node {
+	if n == nil {
+		return Node{}
+	}
+	node := newNode(n)
+	if s, ok := n.Syntax().(YAMLSyntax); ok {
+		node.AliasRange = s.AliasRange()
+	}
+	return node
+}
+
+func newNode(n syntax.Node) Node {
 	switch n := n.(type) {
 	case nil:
 		return Node{}
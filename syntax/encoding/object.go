@@ -87,7 +87,7 @@ func decodeValue(v reflect.Value) (syntax.Node, syntax.Diagnostics) {
 			return syntax.Array(elements...), diags
 		case reflect.Map:
 			if v.Type().Key().Kind() != reflect.String {
-				return nil, syntax.Diagnostics{syntax.Error(nil, fmt.Sprintf("cannot decode value of type %v (map keys must be strings)", v.Type()), "")}
+				return nil, syntax.Diagnostics{syntax.Error(nil, fmt.Sprintf("cannot decode value of type %v (map keys must be strings)", v.Type()), "").WithCode(syntax.CodeUnsupportedType)}
 			}
 
 			if v.IsNil() {
@@ -152,7 +152,7 @@ func decodeValue(v reflect.Value) (syntax.Node, syntax.Diagnostics) {
 			}
 			return syntax.Object(entries...), diags
 		default:
-			return nil, syntax.Diagnostics{syntax.Error(nil, fmt.Sprintf("cannot decode value of type %v", v.Type()), "")}
+			return nil, syntax.Diagnostics{syntax.Error(nil, fmt.Sprintf("cannot decode value of type %v", v.Type()), "").WithCode(syntax.CodeUnsupportedType)}
 		}
 	}
 }
@@ -220,7 +220,7 @@ func encodeValue(n syntax.Node, v reflect.Value) syntax.Diagnostics {
 			nv := reflect.ValueOf(n)
 			if !nv.Type().AssignableTo(v.Type()) {
 				rng := n.Syntax().Range()
-				return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode %v into location of type %v", nv.Type(), v.Type()), "")}
+				return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode %v into location of type %v", nv.Type(), v.Type()), "").WithCode(syntax.CodeEncodeTypeMismatch)}
 			}
 			v.Set(nv)
 			return nil
@@ -241,7 +241,7 @@ func encodeValue(n syntax.Node, v reflect.Value) syntax.Diagnostics {
 
 		if v.Kind() != reflect.Bool {
 			rng := n.Syntax().Range()
-			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode boolean into location of type %v", v.Type()), "")}
+			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode boolean into location of type %v", v.Type()), "").WithCode(syntax.CodeEncodeTypeMismatch)}
 		}
 		v.SetBool(n.Value())
 		return nil
@@ -254,7 +254,7 @@ func encodeValue(n syntax.Node, v reflect.Value) syntax.Diagnostics {
 
 		reprError := func() syntax.Diagnostics {
 			rng := n.Syntax().Range()
-			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot represent %v as type %v", n.Value(), v.Type()), "")}
+			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot represent %v as type %v", n.Value(), v.Type()), "").WithCode(syntax.CodeEncodeTypeMismatch)}
 		}
 
 		parseFloat := func(bitSize int) syntax.Diagnostics {
@@ -318,7 +318,7 @@ func encodeValue(n syntax.Node, v reflect.Value) syntax.Diagnostics {
 			}
 
 			rng := n.Syntax().Range()
-			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode number into location of type %v", v.Type()), "")}
+			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode number into location of type %v", v.Type()), "").WithCode(syntax.CodeEncodeTypeMismatch)}
 		}
 	case *syntax.StringNode:
 		if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
@@ -329,7 +329,7 @@ func encodeValue(n syntax.Node, v reflect.Value) syntax.Diagnostics {
 
 		if v.Kind() != reflect.String {
 			rng := n.Syntax().Range()
-			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode string into location of type %v", v.Type()), "")}
+			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode string into location of type %v", v.Type()), "").WithCode(syntax.CodeEncodeTypeMismatch)}
 		}
 		v.SetString(n.Value())
 		return nil
@@ -347,7 +347,7 @@ func encodeValue(n syntax.Node, v reflect.Value) syntax.Diagnostics {
 			v.Set(reflect.MakeSlice(v.Type(), n.Len(), n.Len()))
 		default:
 			rng := n.Syntax().Range()
-			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode list into location of type %v", v.Type()), "")}
+			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode list into location of type %v", v.Type()), "").WithCode(syntax.CodeEncodeTypeMismatch)}
 		}
 
 		l := n.Len()
@@ -432,7 +432,7 @@ func encodeValue(n syntax.Node, v reflect.Value) syntax.Diagnostics {
 			syn := n.Syntax()
 			rng := syn.Range()
 			path := syn.Path()
-			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode object into location of type %v", v.Type()), path)}
+			return syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("cannot encode object into location of type %v", v.Type()), path).WithCode(syntax.CodeEncodeTypeMismatch)}
 		}
 	default:
 		panic("unreachable")
@@ -44,6 +44,7 @@ type YAMLSyntax struct {
 	rng   *hcl.Range
 	path  string
 	value interface{}
+	alias *hcl.Range
 }
 
 // Range returns the textual range of the YAML node, if any.
@@ -56,6 +57,13 @@ func (s YAMLSyntax) Path() string {
 	return s.path
 }
 
+// AliasRange returns the textual range of the alias through which the YAML node was reached, if any. The node's own
+// range (as returned by Range) lies within the anchored node that the alias refers to. If the node was reached through
+// nested aliases, the range of the outermost alias is returned.
+func (s YAMLSyntax) AliasRange() *hcl.Range {
+	return s.alias
+}
+
 func (s YAMLSyntax) ScalarRange(start, end int) *hcl.Range {
 	if s.rng == nil || s.Kind != yaml.ScalarNode {
 		return nil
@@ -259,8 +267,53 @@ type linePosition struct {
 }
 
 type positionIndex struct {
-	lines []linePosition
-	path  []any
+	lines   []linePosition
+	path    []any
+	alias   *yamlAlias
+	aliases *aliasExpansion
+}
+
+// maxAliasExpansion is the maximum number of nodes that may be produced by expanding aliases in a single document.
+// This guards against documents that use nested aliases to expand into an enormous number of nodes (i.e. "billion
+// laughs" attacks).
+const maxAliasExpansion = 10000
+
+// A yamlAlias records the alias through which a node was reached.
+type yamlAlias struct {
+	name string
+	rng  *hcl.Range
+}
+
+// An aliasExpansion tracks the aliases that are being expanded while decoding a document.
+type aliasExpansion struct {
+	// active holds the anchored nodes that are currently being unmarshaled, either directly or through an alias.
+	active []*yaml.Node
+	// nodes is the number of nodes that have been produced by expanding aliases.
+	nodes int
+	// exceeded is true if nodes has exceeded maxAliasExpansion.
+	exceeded bool
+}
+
+func newAliasPositionIndex() positionIndex {
+	return positionIndex{aliases: &aliasExpansion{}}
+}
+
+// syntax returns a YAMLSyntax for the given node.
+func (p positionIndex) syntax(n *yaml.Node, rng *hcl.Range, value any) YAMLSyntax {
+	s := YAMLSyntax{Node: n, rng: rng, path: p.pathString(), value: value}
+	if p.alias != nil {
+		s.alias = p.alias.rng
+	}
+	return s
+}
+
+// error returns an error diagnostic. If the diagnostic was issued while expanding an alias, its summary notes the
+// location of the alias.
+func (p positionIndex) error(rng *hcl.Range, summary, code string) *syntax.Diagnostic {
+	if p.alias != nil && p.alias.rng != nil {
+		summary = fmt.Sprintf("%v (expanded from the alias *%v at %v:%v)", summary, p.alias.name, p.alias.rng.Start.Line, p.alias.rng.Start.Column)
+	}
+	return syntax.Error(rng, summary, p.pathString()).WithCode(code)
 }
 
 func (p positionIndex) pathString() string {
@@ -288,13 +341,13 @@ func isASCII(s []byte) bool {
 }
 
 func newPositionIndex(yaml []byte) positionIndex {
-	offset, lines, path := 0, []linePosition(nil), []any(nil)
+	offset, lines := 0, []linePosition(nil)
 	for {
 		line, rest, found := bytes.Cut(yaml, []byte{'\n'})
 
 		lines = append(lines, linePosition{offset: offset, ascii: isASCII(line), line: line})
 		if !found {
-			return positionIndex{lines, path}
+			return positionIndex{lines: lines, aliases: &aliasExpansion{}}
 		}
 		offset, yaml = offset+len(line)+1, rest
 	}
@@ -335,6 +388,8 @@ func (p positionIndex) yamlEndPos(n *yaml.Node) hcl.Pos {
 			return p.yamlEndPos(n.Content[len(n.Content)-1])
 		}
 		return p.pos(n.Line, n.Column)
+	case yaml.AliasNode:
+		return p.pos(n.Line, n.Column+1+len(n.Value))
 	default:
 		line, col, s := n.Line, n.Column, n.Value
 		switch n.Style {
@@ -364,12 +419,27 @@ func (p positionIndex) yamlNodeRange(filename string, n *yaml.Node) *hcl.Range {
 // for the node itself, though it does use the tag decoder for the node's children. This allows tag decoders to call
 // UnmarshalYAMLNode without infinitely recurring on the same node. See UnmarshalYAML for more details.
 func UnmarshalYAMLNode(filename string, n *yaml.Node, tags TagDecoder) (syntax.Node, syntax.Diagnostics) {
-	return unmarshalYAMLNode(filename, positionIndex{}, n, tags)
+	positions := newAliasPositionIndex()
+	return positions.checkAliases(unmarshalYAMLNode(filename, positions, n, tags))
 }
 
 func unmarshalYAMLNode(filename string, positions positionIndex, n *yaml.Node, tags TagDecoder) (syntax.Node, syntax.Diagnostics) {
 	rng := positions.yamlNodeRange(filename, n)
-	path := positions.pathString()
+
+	if positions.alias != nil {
+		if positions.aliases.exceeded {
+			return nil, nil
+		}
+		if positions.aliases.nodes++; positions.aliases.nodes > maxAliasExpansion {
+			positions.aliases.exceeded = true
+			summary := fmt.Sprintf("aliases may expand to at most %v nodes", maxAliasExpansion)
+			return nil, syntax.Diagnostics{syntax.Error(positions.alias.rng, summary, positions.pathString()).WithCode(syntax.CodeYAMLAlias)}
+		}
+	}
+	if n.Anchor != "" {
+		positions.aliases.active = append(positions.aliases.active, n)
+		defer positions.aliases.leave()
+	}
 
 	var diags syntax.Diagnostics
 	switch n.Kind {
@@ -379,78 +449,198 @@ func unmarshalYAMLNode(filename string, positions positionIndex, n *yaml.Node, t
 			elements = make([]syntax.Node, len(n.Content))
 			for i, v := range n.Content {
 				pos := positions
-				pos.path = append(pos.path, i)
+				pos.path = append(pos.path[:len(pos.path):len(pos.path)], i)
 				e, ediags := unmarshalYAML(filename, pos, v, tags)
 				diags.Extend(ediags...)
 
 				elements[i] = e
 			}
 		}
-		return syntax.ArraySyntax(YAMLSyntax{n, rng, path, nil}, elements...), diags
+		return syntax.ArraySyntax(positions.syntax(n, rng, nil), elements...), diags
 	case yaml.MappingNode:
-		var entries []syntax.ObjectPropertyDef
-		if len(n.Content) != 0 {
-			// mappings are represented as a sequence of the form [key_0, value_0, ... key_n, value_n]
-			numEntries := len(n.Content) / 2
-			entries = make([]syntax.ObjectPropertyDef, numEntries)
-			for i := range entries {
-				keyNode, valueNode := n.Content[2*i], n.Content[2*i+1]
-
-				pos := positions
-				accessor := keyNode.Value
-				pos.path = append(pos.path, accessor)
-
-				keyn, kdiags := unmarshalYAML(filename, pos, keyNode, tags)
-				diags.Extend(kdiags...)
-
-				key, ok := keyn.(*syntax.StringNode)
-				if !ok {
-					keyRange := keyn.Syntax().Range()
-					diags.Extend(syntax.Error(keyRange, "mapping keys must be strings", keyn.Syntax().Path()))
-				}
-
-				value, vdiags := unmarshalYAML(filename, pos, valueNode, tags)
-				diags.Extend(vdiags...)
-
-				entries[i] = syntax.ObjectPropertySyntax(YAMLSyntax{keyNode, rng, pos.pathString(), nil}, key, value)
-			}
-		}
-		return syntax.ObjectSyntax(YAMLSyntax{n, rng, path, nil}, entries...), diags
+		return unmarshalYAMLMapping(filename, positions, n, rng, tags)
 	case yaml.ScalarNode:
 		var v interface{}
 		if err := n.Decode(&v); err != nil {
-			diags.Extend(syntax.Error(rng, err.Error(), path))
+			diags.Extend(positions.error(rng, err.Error(), syntax.CodeYAMLSyntax))
 			return nil, diags
 		}
 		if v == nil {
-			return syntax.NullSyntax(YAMLSyntax{n, rng, path, nil}), nil
+			return syntax.NullSyntax(positions.syntax(n, rng, nil)), nil
 		}
 
 		switch v := v.(type) {
 		case bool:
-			return syntax.BooleanSyntax(YAMLSyntax{n, rng, path, v}, v), nil
+			return syntax.BooleanSyntax(positions.syntax(n, rng, v), v), nil
 		case float64:
 			nv := syntax.AsNumber(v)
-			return syntax.NumberSyntax(YAMLSyntax{n, rng, path, nv}, nv), nil
+			return syntax.NumberSyntax(positions.syntax(n, rng, nv), nv), nil
 		case int:
 			nv := syntax.AsNumber(v)
-			return syntax.NumberSyntax(YAMLSyntax{n, rng, path, nv}, nv), nil
+			return syntax.NumberSyntax(positions.syntax(n, rng, nv), nv), nil
 		case int64:
 			nv := syntax.AsNumber(v)
-			return syntax.NumberSyntax(YAMLSyntax{n, rng, path, nv}, nv), nil
+			return syntax.NumberSyntax(positions.syntax(n, rng, nv), nv), nil
 		case uint64:
 			nv := syntax.AsNumber(v)
-			return syntax.NumberSyntax(YAMLSyntax{n, rng, path, nv}, nv), nil
+			return syntax.NumberSyntax(positions.syntax(n, rng, nv), nv), nil
 		default:
-			return syntax.StringSyntax(YAMLSyntax{n, rng, path, v}, n.Value), nil
+			return syntax.StringSyntax(positions.syntax(n, rng, v), n.Value), nil
 		}
 	case yaml.AliasNode:
-		return nil, syntax.Diagnostics{syntax.Error(rng, "alias nodes are not supported", path)}
+		pos, diags := positions.enterAlias(filename, n)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		defer positions.aliases.leave()
+
+		return unmarshalYAML(filename, pos, n.Alias, tags)
 	default:
-		return nil, syntax.Diagnostics{syntax.Error(rng, fmt.Sprintf("unexpected node kind %v", n.Kind), path)}
+		return nil, syntax.Diagnostics{positions.error(rng, fmt.Sprintf("unexpected node kind %v", n.Kind), syntax.CodeYAMLUnsupported)}
 	}
 }
 
+// enterAlias begins the expansion of the given alias node. If the alias can be expanded, the caller must call
+// leave on the alias expansion once the expansion is complete.
+func (p positionIndex) enterAlias(filename string, n *yaml.Node) (positionIndex, syntax.Diagnostics) {
+	rng := p.yamlNodeRange(filename, n)
+	if n.Alias == nil {
+		return p, syntax.Diagnostics{p.error(rng, fmt.Sprintf("unknown anchor %q", n.Value), syntax.CodeYAMLAlias)}
+	}
+	for _, active := range p.aliases.active {
+		if active == n.Alias {
+			return p, syntax.Diagnostics{p.error(rng, fmt.Sprintf("alias *%v refers to an anchor that contains it", n.Value), syntax.CodeYAMLAlias)}
+		}
+	}
+	p.aliases.active = append(p.aliases.active, n.Alias)
+
+	// Nodes reached through nested aliases record the outermost alias, which is where the value appears in the
+	// document's structure.
+	if p.alias == nil {
+		p.alias = &yamlAlias{name: n.Value, rng: rng}
+	}
+	return p, nil
+}
+
+// checkAliases discards the result of unmarshaling a document if its aliases expanded to too many nodes.
+func (p positionIndex) checkAliases(n syntax.Node, diags syntax.Diagnostics) (syntax.Node, syntax.Diagnostics) {
+	if p.aliases.exceeded {
+		return nil, diags
+	}
+	return n, diags
+}
+
+// leave ends the expansion of the most recently entered alias or anchored node.
+func (e *aliasExpansion) leave() {
+	e.active = e.active[:len(e.active)-1]
+}
+
+// isMergeKey returns true if the given node is a YAML merge key (`<<`).
+func isMergeKey(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.Tag == "!!merge"
+}
+
+// unmarshalYAMLMapping unmarshals a YAML mapping into an object node.
+//
+// Merge keys are expanded in place: the entries of the merged mappings are inserted at the position of the merge key.
+// Following the YAML merge key specification, keys that are explicitly present in the mapping take precedence over
+// merged keys, and mappings earlier in a sequence of merged mappings take precedence over later mappings.
+func unmarshalYAMLMapping(filename string, positions positionIndex, n *yaml.Node, rng *hcl.Range, tags TagDecoder) (syntax.Node, syntax.Diagnostics) {
+	if len(n.Content) == 0 {
+		return syntax.ObjectSyntax(positions.syntax(n, rng, nil)), nil
+	}
+
+	explicit := map[string]bool{}
+	for i := 0; i < len(n.Content); i += 2 {
+		if keyNode := n.Content[i]; !isMergeKey(keyNode) {
+			explicit[keyNode.Value] = true
+		}
+	}
+
+	var diags syntax.Diagnostics
+	var entries []syntax.ObjectPropertyDef
+	merged := map[string]bool{}
+	for i := 0; i < len(n.Content); i += 2 {
+		// mappings are represented as a sequence of the form [key_0, value_0, ... key_n, value_n]
+		keyNode, valueNode := n.Content[i], n.Content[i+1]
+
+		if isMergeKey(keyNode) {
+			mdiags := unmarshalYAMLMerge(filename, positions, valueNode, tags, func(kvp syntax.ObjectPropertyDef) {
+				if k := kvp.Key.Value(); !explicit[k] && !merged[k] {
+					merged[k] = true
+					entries = append(entries, kvp)
+				}
+			})
+			diags.Extend(mdiags...)
+			continue
+		}
+
+		pos := positions
+		accessor := keyNode.Value
+		pos.path = append(pos.path[:len(pos.path):len(pos.path)], accessor)
+
+		keyn, kdiags := unmarshalYAML(filename, pos, keyNode, tags)
+		diags.Extend(kdiags...)
+
+		key, ok := keyn.(*syntax.StringNode)
+		if !ok && keyn != nil {
+			keyRange := keyn.Syntax().Range()
+			diags.Extend(pos.error(keyRange, "mapping keys must be strings", syntax.CodeNonStringKey))
+		}
+
+		value, vdiags := unmarshalYAML(filename, pos, valueNode, tags)
+		diags.Extend(vdiags...)
+
+		entries = append(entries, syntax.ObjectPropertySyntax(pos.syntax(keyNode, rng, nil), key, value))
+	}
+	return syntax.ObjectSyntax(positions.syntax(n, rng, nil), entries...), diags
+}
+
+// unmarshalYAMLMerge unmarshals the value of a merge key and calls merge for each entry of the merged mappings. The
+// value must be a mapping, an alias to a mapping, or a sequence of mappings or aliases to mappings. The entries of the
+// merged mappings have the paths of the entries of the mapping that contains the merge key.
+func unmarshalYAMLMerge(
+	filename string,
+	positions positionIndex,
+	n *yaml.Node,
+	tags TagDecoder,
+	merge func(kvp syntax.ObjectPropertyDef),
+) syntax.Diagnostics {
+	var diags syntax.Diagnostics
+
+	values := []*yaml.Node{n}
+	if n.Kind == yaml.AliasNode && n.Alias != nil && n.Alias.Kind == yaml.SequenceNode {
+		pos, adiags := positions.enterAlias(filename, n)
+		diags.Extend(adiags...)
+		if adiags.HasErrors() {
+			return diags
+		}
+		defer positions.aliases.leave()
+
+		positions, values = pos, n.Alias.Content
+	} else if n.Kind == yaml.SequenceNode {
+		values = n.Content
+	}
+
+	for _, v := range values {
+		value, vdiags := unmarshalYAML(filename, positions, v, tags)
+		diags.Extend(vdiags...)
+
+		obj, ok := value.(*syntax.ObjectNode)
+		if !ok {
+			if value != nil {
+				rng := positions.yamlNodeRange(filename, v)
+				diags.Extend(positions.error(rng, "the value of a merge key must be a mapping or a sequence of mappings", syntax.CodeYAMLMerge))
+			}
+			continue
+		}
+		for i := 0; i < obj.Len(); i++ {
+			merge(obj.Index(i))
+		}
+	}
+	return diags
+}
+
 // UnmarshalYAML unmarshals a YAML node into a syntax node.
 //
 // Nodes are decoded as follows:
@@ -460,8 +650,22 @@ func unmarshalYAMLNode(filename string, positions positionIndex, n *yaml.Node, t
 //
 // Tagged nodes are decoded using the given TagDecoder. To avoid infinite recursion, the TagDecoder must call
 // UnmarshalYAMLNode if it needs to unmarshal the node it is processing.
+//
+// Aliases (`*name`) are expanded into copies of the nodes they refer to. The nodes produced by expanding an alias have
+// the paths of the alias, the ranges of the anchored nodes, and an alias range (see YAMLSyntax.AliasRange) that points
+// at the alias itself. Diagnostics issued while expanding an alias note the location of the alias. Aliases that refer
+// to an anchor that contains them are not supported, and expansion is limited to 10,000 nodes per document in order to
+// guard against alias bombs.
+//
+// Merge keys (`<<`) are expanded as described by the YAML merge key specification: the entries of the merged mappings
+// are inserted at the position of the merge key, unless they are overridden by keys that are explicitly present in the
+// mapping.
+//
+// Anchors, aliases, and merge keys are not preserved by MarshalYAML: encoding a decoded document writes the expanded
+// values.
 func UnmarshalYAML(filename string, n *yaml.Node, tags TagDecoder) (syntax.Node, syntax.Diagnostics) {
-	return unmarshalYAML(filename, positionIndex{}, n, tags)
+	positions := newAliasPositionIndex()
+	return positions.checkAliases(unmarshalYAML(filename, positions, n, tags))
 }
 
 func unmarshalYAML(filename string, positions positionIndex, n *yaml.Node, tags TagDecoder) (syntax.Node, syntax.Diagnostics) {
@@ -473,12 +677,23 @@ func unmarshalYAML(filename string, positions positionIndex, n *yaml.Node, tags
 	return unmarshalYAMLNode(filename, positions, n, tags)
 }
 
+// isAmbiguousString returns true if the given string would be decoded as a number, boolean, or null if it were
+// written as a plain YAML scalar.
+func isAmbiguousString(value string) bool {
+	switch value {
+	case "true", "True", "TRUE", "false", "False", "FALSE", "null", "Null", "NULL", "~":
+		return true
+	}
+	_, err := strconv.ParseFloat(value, 32)
+	return err == nil
+}
+
 // MarshalYAML marshals a syntax node into a YAML node. If a syntax node has an associated YAMLSyntax annotation,
 // the tag, style, and comments on the result will be pulled from the YAMLSyntax. The marshaling process otherwise
 // follows the inverse of the unmarshaling process described in the documentation for UnmarshalYAML.
 func MarshalYAML(n syntax.Node) (*yaml.Node, syntax.Diagnostics) {
 	if n == nil {
-		return &yaml.Node{}, syntax.Diagnostics{syntax.Error(nil, "nil nodes are not supported", "")}
+		return &yaml.Node{}, syntax.Diagnostics{syntax.Error(nil, "nil nodes are not supported", "").WithCode(syntax.CodeYAMLUnsupported)}
 	}
 
 	var yamlNode yaml.Node
@@ -539,7 +754,7 @@ func MarshalYAML(n syntax.Node) (*yaml.Node, syntax.Diagnostics) {
 		if yamlNode.Tag != "" && yamlNode.Tag != "!!str" {
 			yamlNode.Tag = "!!str"
 		}
-		if _, err := strconv.ParseFloat(value, 32); err == nil || value == "true" || value == "false" {
+		if isAmbiguousString(value) && yamlNode.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) == 0 {
 			yamlNode.Style = yaml.SingleQuotedStyle
 		}
 		if originalValue != value {
@@ -594,7 +809,7 @@ type yamlValue struct {
 }
 
 func (v *yamlValue) UnmarshalYAML(n *yaml.Node) error {
-	v.node, v.diags = unmarshalYAML(v.filename, v.positions, n, v.tags)
+	v.node, v.diags = v.positions.checkAliases(unmarshalYAML(v.filename, v.positions, n, v.tags))
 	return nil
 }
 
@@ -607,7 +822,7 @@ func DecodeYAMLBytes(filename string, bytes []byte, tags TagDecoder) (syntax.Nod
 	}
 	v := yamlValue{filename: filename, positions: newPositionIndex(bytes), tags: tags}
 	if err := yaml.Unmarshal(bytes, &v); err != nil {
-		return nil, syntax.Diagnostics{syntax.Error(nil, err.Error(), "")}
+		return nil, syntax.Diagnostics{syntax.Error(nil, err.Error(), "").WithCode(syntax.CodeYAMLSyntax)}
 	}
 	return v.node, v.diags
 }
@@ -615,12 +830,12 @@ func DecodeYAMLBytes(filename string, bytes []byte, tags TagDecoder) (syntax.Nod
 // DecodeYAML decodes a YAML value from the given decoder into a syntax node. See UnmarshalYAML for mode details on the
 // decoding process.
 func DecodeYAML(filename string, d *yaml.Decoder, tags TagDecoder) (syntax.Node, syntax.Diagnostics) {
-	v := yamlValue{filename: filename, tags: tags}
+	v := yamlValue{filename: filename, positions: newAliasPositionIndex(), tags: tags}
 	if err := d.Decode(&v); err != nil {
 		if errors.Is(err, io.EOF) {
 			return &syntax.ObjectNode{}, v.diags
 		}
-		return nil, syntax.Diagnostics{syntax.Error(nil, err.Error(), "")}
+		return nil, syntax.Diagnostics{syntax.Error(nil, err.Error(), "").WithCode(syntax.CodeYAMLSyntax)}
 	}
 	return v.node, v.diags
 }
@@ -630,7 +845,7 @@ func DecodeYAML(filename string, d *yaml.Decoder, tags TagDecoder) (syntax.Node,
 func EncodeYAML(e *yaml.Encoder, n syntax.Node) syntax.Diagnostics {
 	yamlNode, diags := MarshalYAML(n)
 	if err := e.Encode(yamlNode); err != nil {
-		diags.Extend(syntax.Error(nil, err.Error(), ""))
+		diags.Extend(syntax.Error(nil, err.Error(), "").WithCode(syntax.CodeYAMLEncode))
 	}
 	return diags
 }
@@ -179,6 +179,31 @@ baz: qux
 	assert.Equal(t, expected, b.String())
 }
 
+func TestYAMLAmbiguousStrings(t *testing.T) {
+	const expected = `'null': '~'
+'true': 'False'
+number: '1.5'
+`
+
+	root := syntax.Object(
+		syntax.ObjectProperty(syntax.String("null"), syntax.String("~")),
+		syntax.ObjectProperty(syntax.String("true"), syntax.String("False")),
+		syntax.ObjectProperty(syntax.String("number"), syntax.String("1.5")),
+	)
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	diags := EncodeYAML(enc, root)
+	assert.Empty(t, diags)
+	assert.Equal(t, expected, b.String())
+
+	// The encoded strings decode as strings.
+	decoded, diags := DecodeYAML("yaml", yaml.NewDecoder(strings.NewReader(b.String())), nil)
+	assert.Empty(t, diags)
+	assert.Equal(t, root.String(), decoded.String())
+}
+
 func TestYAMLDeleteAllValuesThenAdd(t *testing.T) {
 	const doc = `values:
     example1: abc`
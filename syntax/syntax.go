@@ -23,6 +23,14 @@ type Syntax interface {
 	Path() string
 }
 
+// AliasedSyntax is implemented by syntax that may have been reached through an alias, such as a YAML alias.
+type AliasedSyntax interface {
+	Syntax
+
+	// AliasRange returns the textual range of the alias through which the syntax was reached, if any.
+	AliasRange() *hcl.Range
+}
+
 var NoSyntax = noSyntax(0)
 
 type noSyntax int
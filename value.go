@@ -100,6 +100,10 @@ type Trace struct {
 
 	// Base is the base value with which a value was merged.
 	Base *Value `json:"base,omitempty"`
+
+	// Default is true if the value was filled in from a schema's default. If so, Def is the range of the default
+	// within the schema's definition.
+	Default bool `json:"default,omitempty"`
 }
 
 func (v *Value) UnmarshalJSON(data []byte) error {